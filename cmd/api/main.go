@@ -9,16 +9,21 @@ import (
 	"os/signal"
 	"syscall"
 	"time"
+	_ "time/tzdata" // user timezones; the runtime image has no zoneinfo
 
 	"github.com/jackc/pgx/v5/pgxpool"
 	httpSwagger "github.com/swaggo/http-swagger/v2"
 
 	_ "github.com/always-tired/crud-subscriptions/docs"
+	"github.com/always-tired/crud-subscriptions/internal/broker"
 	"github.com/always-tired/crud-subscriptions/internal/config"
 	"github.com/always-tired/crud-subscriptions/internal/logger"
+	"github.com/always-tired/crud-subscriptions/internal/notifier"
+	"github.com/always-tired/crud-subscriptions/internal/publisher"
 	"github.com/always-tired/crud-subscriptions/internal/repository/postgres"
 	httptransport "github.com/always-tired/crud-subscriptions/internal/transport/http"
 	"github.com/always-tired/crud-subscriptions/internal/usecase"
+	"github.com/always-tired/crud-subscriptions/internal/worker"
 )
 
 // @title Subscription Aggregator API
@@ -41,10 +46,80 @@ func main() {
 	}
 	defer pool.Close()
 
-	repo := postgres.NewSubscriptionRepository(pool)
-	service := usecase.NewService(repo, log)
+	notifiers := []usecase.Notifier{notifier.NewLog(log)}
+	if smtpCfg := cfg.Reminders.SMTP; smtpCfg.Addr != "" {
+		n, err := notifier.NewSMTP(smtpCfg.Addr, smtpCfg.Username, smtpCfg.Password, smtpCfg.From, smtpCfg.To, 30*time.Second)
+		if err != nil {
+			log.Error("smtp notifier", "error", err)
+			os.Exit(1)
+		}
+		notifiers = append(notifiers, n)
+	}
+	if cfg.Reminders.WebhookURL != "" {
+		notifiers = append(notifiers, notifier.NewWebhook(cfg.Reminders.WebhookURL, cfg.Reminders.WebhookSecret, 10*time.Second))
+	}
+
+	var clock usecase.Clock = usecase.SystemClock{}
+	if !cfg.Clock.FrozenAt.IsZero() {
+		clock = usecase.FixedClock(cfg.Clock.FrozenAt)
+		log.Warn("clock frozen", "at", cfg.Clock.FrozenAt)
+	}
+
+	repo := postgres.NewSubscriptionRepository(pool, clock)
+	outbox := postgres.NewOutboxRepository(pool)
+	hub := broker.NewHub()
+	service := usecase.NewService(repo, log,
+		usecase.WithClock(clock),
+		usecase.WithTxManager(postgres.NewTxManager(pool)),
+		usecase.WithAuditLog(postgres.NewAuditRepository(pool)),
+		usecase.WithOutbox(outbox, cfg.Events.MaxAttempts),
+		usecase.WithEventStream(outbox, hub),
+		usecase.WithWebhooks(
+			postgres.NewWebhookRepository(pool),
+			publisher.NewWebhookSender(&http.Client{Timeout: cfg.Webhooks.Timeout}),
+			cfg.Webhooks.MaxAttempts,
+		),
+		usecase.WithCalendarTokens(postgres.NewCalendarTokenRepository(pool)),
+		usecase.WithUsers(postgres.NewUserRepository(pool), cfg.Users.Strict),
+		usecase.WithCategories(postgres.NewCategoryRepository(pool)),
+		usecase.WithPaymentMethods(postgres.NewPaymentMethodRepository(pool)),
+		usecase.WithServiceCatalog(postgres.NewServiceCatalogRepository(pool), cfg.Catalog.Strict),
+		usecase.WithStrictOverlaps(cfg.Overlaps.Strict),
+		usecase.WithAnomalyDetection(cfg.Anomalies.Threshold, cfg.Anomalies.Window, cfg.Anomalies.Notify),
+		usecase.WithBudgets(postgres.NewBudgetRepository(pool)),
+		usecase.WithReminders(postgres.NewReminderRepository(pool), notifiers...),
+		usecase.WithAdminToken(cfg.Admin.Token),
+	)
 	h := httptransport.NewHandler(service, log)
 
+	workerCtx, stopWorkers := context.WithCancel(ctx)
+	defer stopWorkers()
+	if cfg.Purge.Retention > 0 {
+		go worker.NewPurger(service, cfg.Purge.Interval, cfg.Purge.Retention, log).Run(workerCtx)
+	}
+	go postgres.NewListener(pool, hub, log).Run(workerCtx)
+	go worker.NewEndedScanner(service, time.Hour).Run(workerCtx)
+	if cfg.Reminders.Days > 0 || cfg.Anomalies.Notify {
+		within := time.Duration(cfg.Reminders.Days) * 24 * time.Hour
+		go worker.NewReminderScheduler(service, cfg.Reminders.Interval, within, log).Run(workerCtx)
+	}
+
+	publishers := publisher.Multi{service.WebhookPublisher()}
+	if cfg.Events.File != "" {
+		p, closer, err := publisher.OpenFile(cfg.Events.File)
+		if err != nil {
+			log.Error("events file", "error", err)
+			os.Exit(1)
+		}
+		defer closer.Close()
+		publishers = append(publishers, p)
+	}
+	if cfg.Events.WebhookURL != "" {
+		publishers = append(publishers, publisher.NewWebhookPublisher(cfg.Events.WebhookURL, 10*time.Second))
+	}
+	go worker.NewRelay(service, publishers, cfg.Events.RelayInterval, log).Run(workerCtx)
+	go worker.NewWebhookDispatcher(service, cfg.Webhooks.DeliveryInterval, log).Run(workerCtx)
+
 	r := h.Router()
 	r.Get("/swagger/*", httpSwagger.Handler(
 		httpSwagger.URL("/swagger/doc.json"),
@@ -61,6 +136,8 @@ func main() {
 		WriteTimeout: cfg.HTTP.WriteTimeout,
 		IdleTimeout:  cfg.HTTP.IdleTimeout,
 	}
+	// Shutdown does not cancel request contexts, so end open event streams.
+	srv.RegisterOnShutdown(hub.Close)
 
 	go func() {
 		log.Info("http server started", "addr", addr)
@@ -72,6 +149,7 @@ func main() {
 	stop := make(chan os.Signal, 1)
 	signal.Notify(stop, syscall.SIGINT, syscall.SIGTERM)
 	<-stop
+	stopWorkers()
 
 	ctxShutdown, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
@@ -27,73 +27,803 @@ const docTemplate = `{
       ],
       "responses": {
         "201": {"description": "Created", "schema": {"$ref": "#/definitions/Subscription"}},
+        "400": {"description": "Bad request", "schema": {"$ref": "#/definitions/Error"}},
+        "409": {"description": "Already exists, or overlaps another subscription of the user for the service with OVERLAPS_STRICT", "schema": {"$ref": "#/definitions/Error"}}
+      }
+    },
+    "get": {
+      "summary": "List subscriptions",
+      "parameters": [
+        {"in": "query", "name": "user_id", "type": "string", "format": "uuid"},
+        {"in": "query", "name": "service_name", "type": "string"},
+        {"in": "query", "name": "category", "type": "string", "description": "category id or name"},
+        {"in": "query", "name": "tag", "type": "array", "items": {"type": "string"}, "collectionFormat": "multi", "description": "tags, all must match"},
+        {"in": "query", "name": "trial_ending_within", "type": "integer", "description": "only trials whose first charge is due within this many days"},
+        {"in": "query", "name": "limit", "type": "integer"},
+        {"in": "query", "name": "offset", "type": "integer"},
+        {"in": "query", "name": "include_deleted", "type": "boolean", "description": "include soft-deleted subscriptions (admin)"},
+        {"in": "header", "name": "X-Admin-Token", "type": "string", "description": "admin token, required with include_deleted"}
+      ],
+      "responses": {
+        "200": {"description": "OK", "schema": {"type": "array", "items": {"$ref": "#/definitions/Subscription"}}},
+        "403": {"description": "include_deleted without a valid admin token", "schema": {"$ref": "#/definitions/Error"}}
+      }
+    }
+  },
+  "/subscriptions/{id}": {
+    "get": {
+      "summary": "Get subscription by id",
+      "parameters": [
+        {"in": "path", "name": "id", "required": true, "type": "string", "format": "uuid"}
+      ],
+      "responses": {
+        "200": {"description": "OK", "schema": {"$ref": "#/definitions/Subscription"}},
+        "404": {"description": "Not found", "schema": {"$ref": "#/definitions/Error"}}
+      }
+    },
+    "put": {
+      "summary": "Update subscription",
+      "parameters": [
+        {"in": "path", "name": "id", "required": true, "type": "string", "format": "uuid"},
+        {"in": "body", "name": "subscription", "required": true, "schema": {"$ref": "#/definitions/SubscriptionRequest"}}
+      ],
+      "responses": {
+        "200": {"description": "OK", "schema": {"$ref": "#/definitions/Subscription"}},
+        "400": {"description": "Bad request", "schema": {"$ref": "#/definitions/Error"}},
+        "404": {"description": "Not found", "schema": {"$ref": "#/definitions/Error"}},
+        "409": {"description": "Already exists, or overlaps another subscription of the user for the service with OVERLAPS_STRICT", "schema": {"$ref": "#/definitions/Error"}}
+      }
+    },
+    "delete": {
+      "summary": "Delete subscription",
+      "parameters": [
+        {"in": "path", "name": "id", "required": true, "type": "string", "format": "uuid"}
+      ],
+      "responses": {
+        "204": {"description": "No Content"},
+        "404": {"description": "Not found", "schema": {"$ref": "#/definitions/Error"}}
+      }
+    }
+  },
+  "/subscriptions/summary": {
+    "get": {
+      "summary": "Get total cost for period",
+      "parameters": [
+        {"in": "query", "name": "start", "required": true, "type": "string", "example": "07-2025"},
+        {"in": "query", "name": "end", "required": true, "type": "string", "example": "12-2025"},
+        {"in": "query", "name": "user_id", "type": "string", "format": "uuid", "description": "counts the user's shares of shared subscriptions"},
+        {"in": "query", "name": "service_name", "type": "string"},
+        {"in": "query", "name": "category", "type": "string", "description": "category id or name"},
+        {"in": "query", "name": "tag", "type": "array", "items": {"type": "string"}, "collectionFormat": "multi", "description": "tags, all must match"},
+        {"in": "query", "name": "group_by", "type": "string", "enum": ["category", "payment_method"]}
+      ],
+      "responses": {
+        "200": {"description": "OK", "schema": {"$ref": "#/definitions/Summary"}},
+        "400": {"description": "Bad request", "schema": {"$ref": "#/definitions/Error"}}
+      }
+    }
+  },
+  "/subscriptions/events": {
+    "get": {
+      "summary": "Stream subscription events",
+      "description": "Server-Sent Events stream of subscription changes. Each event has the outbox cursor as id, the event type as event and the JSON envelope as data. Resume with the Last-Event-ID header.",
+      "produces": ["text/event-stream"],
+      "parameters": [
+        {"in": "query", "name": "user_id", "type": "string", "format": "uuid"},
+        {"in": "header", "name": "Last-Event-ID", "type": "string"},
+        {"in": "query", "name": "last_event_id", "type": "string"}
+      ],
+      "responses": {
+        "200": {"description": "OK", "schema": {"type": "string"}},
+        "400": {"description": "Bad request", "schema": {"$ref": "#/definitions/Error"}}
+      }
+    }
+  },
+  "/subscriptions/{id}:restore": {
+    "post": {
+      "summary": "Restore deleted subscription",
+      "parameters": [
+        {"in": "path", "name": "id", "required": true, "type": "string", "format": "uuid"}
+      ],
+      "responses": {
+        "200": {"description": "OK", "schema": {"$ref": "#/definitions/Subscription"}},
+        "404": {"description": "Not found", "schema": {"$ref": "#/definitions/Error"}},
+        "409": {"description": "Already exists, or overlaps another subscription of the user for the service with OVERLAPS_STRICT", "schema": {"$ref": "#/definitions/Error"}}
+      }
+    }
+  },
+  "/subscriptions/{id}:pause": {
+    "post": {
+      "summary": "Pause subscription",
+      "description": "Stops billing from from (default: next month) through until, or until resumed. The body is optional.",
+      "parameters": [
+        {"in": "path", "name": "id", "required": true, "type": "string", "format": "uuid"},
+        {"in": "body", "name": "pause", "required": false, "schema": {"$ref": "#/definitions/PauseRequest"}}
+      ],
+      "responses": {
+        "200": {"description": "OK", "schema": {"$ref": "#/definitions/Subscription"}},
+        "400": {"description": "Bad request", "schema": {"$ref": "#/definitions/Error"}},
+        "404": {"description": "Not found", "schema": {"$ref": "#/definitions/Error"}}
+      }
+    }
+  },
+  "/subscriptions/{id}:resume": {
+    "post": {
+      "summary": "Resume subscription",
+      "description": "Restarts billing from from (default: next month), ending the pause that covers it. The body is optional.",
+      "parameters": [
+        {"in": "path", "name": "id", "required": true, "type": "string", "format": "uuid"},
+        {"in": "body", "name": "resume", "required": false, "schema": {"$ref": "#/definitions/ResumeRequest"}}
+      ],
+      "responses": {
+        "200": {"description": "OK", "schema": {"$ref": "#/definitions/Subscription"}},
+        "400": {"description": "Bad request", "schema": {"$ref": "#/definitions/Error"}},
+        "404": {"description": "Not found", "schema": {"$ref": "#/definitions/Error"}}
+      }
+    }
+  },
+  "/subscriptions/{id}:cancel": {
+    "post": {
+      "summary": "Cancel subscription",
+      "description": "when is end_of_period (default: the current month or the running trial is the last one), immediately (the previous month is the last one) or the last billed month as MM-YYYY.",
+      "parameters": [
+        {"in": "path", "name": "id", "required": true, "type": "string", "format": "uuid"},
+        {"in": "body", "name": "cancellation", "required": true, "schema": {"$ref": "#/definitions/CancelRequest"}}
+      ],
+      "responses": {
+        "200": {"description": "OK", "schema": {"$ref": "#/definitions/Subscription"}},
+        "400": {"description": "Bad request", "schema": {"$ref": "#/definitions/Error"}},
+        "404": {"description": "Not found", "schema": {"$ref": "#/definitions/Error"}}
+      }
+    }
+  },
+  "/subscriptions/{id}:reactivate": {
+    "post": {
+      "summary": "Reactivate subscription",
+      "description": "Undoes a cancellation that has not taken effect yet.",
+      "parameters": [
+        {"in": "path", "name": "id", "required": true, "type": "string", "format": "uuid"}
+      ],
+      "responses": {
+        "200": {"description": "OK", "schema": {"$ref": "#/definitions/Subscription"}},
+        "400": {"description": "Bad request", "schema": {"$ref": "#/definitions/Error"}},
+        "404": {"description": "Not found", "schema": {"$ref": "#/definitions/Error"}}
+      }
+    }
+  },
+  "/subscriptions/{id}/prices": {
+    "get": {
+      "summary": "List price history",
+      "parameters": [
+        {"in": "path", "name": "id", "required": true, "type": "string", "format": "uuid"}
+      ],
+      "responses": {
+        "200": {"description": "OK", "schema": {"type": "array", "items": {"$ref": "#/definitions/PricePeriod"}}},
+        "404": {"description": "Not found", "schema": {"$ref": "#/definitions/Error"}}
+      }
+    },
+    "post": {
+      "summary": "Schedule price change",
+      "description": "Sets the price charged from effective_from on. Earlier months keep their price.",
+      "parameters": [
+        {"in": "path", "name": "id", "required": true, "type": "string", "format": "uuid"},
+        {"in": "body", "name": "change", "required": true, "schema": {"$ref": "#/definitions/PricePeriod"}}
+      ],
+      "responses": {
+        "200": {"description": "OK", "schema": {"type": "array", "items": {"$ref": "#/definitions/PricePeriod"}}},
+        "400": {"description": "Bad request", "schema": {"$ref": "#/definitions/Error"}},
+        "404": {"description": "Not found", "schema": {"$ref": "#/definitions/Error"}}
+      }
+    }
+  },
+  "/subscriptions/{id}/prices/{month}": {
+    "delete": {
+      "summary": "Cancel price change",
+      "parameters": [
+        {"in": "path", "name": "id", "required": true, "type": "string", "format": "uuid"},
+        {"in": "path", "name": "month", "required": true, "type": "string", "example": "07-2025"}
+      ],
+      "responses": {
+        "204": {"description": "No Content"},
+        "404": {"description": "Not found", "schema": {"$ref": "#/definitions/Error"}}
+      }
+    }
+  },
+  "/subscriptions/{id}/discounts": {
+    "get": {
+      "summary": "List discounts",
+      "parameters": [
+        {"in": "path", "name": "id", "required": true, "type": "string", "format": "uuid"}
+      ],
+      "responses": {
+        "200": {"description": "OK", "schema": {"type": "array", "items": {"$ref": "#/definitions/Discount"}}},
+        "404": {"description": "Not found", "schema": {"$ref": "#/definitions/Error"}}
+      }
+    },
+    "post": {
+      "summary": "Add discount",
+      "description": "Lowers the price by a percentage or a fixed amount from from through until, open-ended without until, or over the first_months charged months. Discounts must not overlap.",
+      "parameters": [
+        {"in": "path", "name": "id", "required": true, "type": "string", "format": "uuid"},
+        {"in": "body", "name": "discount", "required": true, "schema": {"$ref": "#/definitions/DiscountRequest"}}
+      ],
+      "responses": {
+        "201": {"description": "Created", "schema": {"$ref": "#/definitions/Discount"}},
+        "400": {"description": "Bad request", "schema": {"$ref": "#/definitions/Error"}},
+        "404": {"description": "Not found", "schema": {"$ref": "#/definitions/Error"}}
+      }
+    }
+  },
+  "/subscriptions/{id}/discounts/{discount_id}": {
+    "delete": {
+      "summary": "Remove discount",
+      "parameters": [
+        {"in": "path", "name": "id", "required": true, "type": "string", "format": "uuid"},
+        {"in": "path", "name": "discount_id", "required": true, "type": "string", "format": "uuid"}
+      ],
+      "responses": {
+        "204": {"description": "No Content"},
+        "404": {"description": "Not found", "schema": {"$ref": "#/definitions/Error"}}
+      }
+    }
+  },
+  "/subscriptions/{id}/history": {
+    "get": {
+      "summary": "Subscription change history",
+      "parameters": [
+        {"in": "path", "name": "id", "required": true, "type": "string", "format": "uuid"},
+        {"in": "query", "name": "limit", "type": "integer"},
+        {"in": "query", "name": "offset", "type": "integer"}
+      ],
+      "responses": {
+        "200": {"description": "OK", "schema": {"type": "array", "items": {"$ref": "#/definitions/AuditEntry"}}}
+      }
+    }
+  },
+  "/audit": {
+    "get": {
+      "summary": "Query audit log",
+      "parameters": [
+        {"in": "query", "name": "actor", "type": "string"},
+        {"in": "query", "name": "from", "type": "string", "format": "date-time"},
+        {"in": "query", "name": "to", "type": "string", "format": "date-time"},
+        {"in": "query", "name": "limit", "type": "integer"},
+        {"in": "query", "name": "offset", "type": "integer"}
+      ],
+      "responses": {
+        "200": {"description": "OK", "schema": {"type": "array", "items": {"$ref": "#/definitions/AuditEntry"}}},
         "400": {"description": "Bad request", "schema": {"$ref": "#/definitions/Error"}}
       }
+    }
+  },
+  "/webhooks": {
+    "post": {
+      "summary": "Register webhook",
+      "description": "The secret is generated when omitted and is only returned here.",
+      "parameters": [
+        {"in": "body", "name": "webhook", "required": true, "schema": {"$ref": "#/definitions/WebhookRequest"}}
+      ],
+      "responses": {
+        "201": {"description": "Created", "schema": {"$ref": "#/definitions/Webhook"}},
+        "400": {"description": "Bad request", "schema": {"$ref": "#/definitions/Error"}}
+      }
+    },
+    "get": {
+      "summary": "List webhooks",
+      "responses": {
+        "200": {"description": "OK", "schema": {"type": "array", "items": {"$ref": "#/definitions/Webhook"}}}
+      }
+    }
+  },
+  "/webhooks/{id}": {
+    "get": {
+      "summary": "Get webhook",
+      "parameters": [
+        {"in": "path", "name": "id", "required": true, "type": "string", "format": "uuid"}
+      ],
+      "responses": {
+        "200": {"description": "OK", "schema": {"$ref": "#/definitions/Webhook"}},
+        "404": {"description": "Not found", "schema": {"$ref": "#/definitions/Error"}}
+      }
+    },
+    "put": {
+      "summary": "Update webhook",
+      "description": "The secret is rotated only when a new one is sent.",
+      "parameters": [
+        {"in": "path", "name": "id", "required": true, "type": "string", "format": "uuid"},
+        {"in": "body", "name": "webhook", "required": true, "schema": {"$ref": "#/definitions/WebhookRequest"}}
+      ],
+      "responses": {
+        "200": {"description": "OK", "schema": {"$ref": "#/definitions/Webhook"}},
+        "400": {"description": "Bad request", "schema": {"$ref": "#/definitions/Error"}},
+        "404": {"description": "Not found", "schema": {"$ref": "#/definitions/Error"}}
+      }
+    },
+    "delete": {
+      "summary": "Delete webhook",
+      "parameters": [
+        {"in": "path", "name": "id", "required": true, "type": "string", "format": "uuid"}
+      ],
+      "responses": {
+        "204": {"description": "No Content"},
+        "404": {"description": "Not found", "schema": {"$ref": "#/definitions/Error"}}
+      }
+    }
+  },
+  "/webhooks/{id}/deliveries": {
+    "get": {
+      "summary": "List webhook deliveries",
+      "parameters": [
+        {"in": "path", "name": "id", "required": true, "type": "string", "format": "uuid"},
+        {"in": "query", "name": "status", "type": "string", "enum": ["pending", "retrying", "succeeded", "dead"]},
+        {"in": "query", "name": "limit", "type": "integer"},
+        {"in": "query", "name": "offset", "type": "integer"}
+      ],
+      "responses": {
+        "200": {"description": "OK", "schema": {"type": "array", "items": {"$ref": "#/definitions/WebhookDelivery"}}},
+        "404": {"description": "Not found", "schema": {"$ref": "#/definitions/Error"}}
+      }
+    }
+  },
+  "/webhooks/{id}/deliveries/{delivery_id}": {
+    "get": {
+      "summary": "Get webhook delivery with attempts",
+      "parameters": [
+        {"in": "path", "name": "id", "required": true, "type": "string", "format": "uuid"},
+        {"in": "path", "name": "delivery_id", "required": true, "type": "string", "format": "uuid"}
+      ],
+      "responses": {
+        "200": {"description": "OK", "schema": {"$ref": "#/definitions/WebhookDelivery"}},
+        "404": {"description": "Not found", "schema": {"$ref": "#/definitions/Error"}}
+      }
+    }
+  },
+  "/webhooks/{id}/deliveries/{delivery_id}:redeliver": {
+    "post": {
+      "summary": "Redeliver webhook delivery",
+      "description": "Queues the delivery again with a fresh attempt budget, also when it is dead.",
+      "parameters": [
+        {"in": "path", "name": "id", "required": true, "type": "string", "format": "uuid"},
+        {"in": "path", "name": "delivery_id", "required": true, "type": "string", "format": "uuid"}
+      ],
+      "responses": {
+        "202": {"description": "Accepted", "schema": {"$ref": "#/definitions/WebhookDelivery"}},
+        "404": {"description": "Not found", "schema": {"$ref": "#/definitions/Error"}}
+      }
+    }
+  },
+  "/users/{user_id}/calendar-token": {
+    "post": {
+      "summary": "Issue renewal calendar token",
+      "description": "Creates a new feed token for the user and revokes the previous one.",
+      "parameters": [
+        {"in": "path", "name": "user_id", "required": true, "type": "string", "format": "uuid"}
+      ],
+      "responses": {
+        "201": {"description": "Created", "schema": {"$ref": "#/definitions/CalendarToken"}},
+        "400": {"description": "Bad request", "schema": {"$ref": "#/definitions/Error"}}
+      }
+    },
+    "delete": {
+      "summary": "Revoke renewal calendar token",
+      "parameters": [
+        {"in": "path", "name": "user_id", "required": true, "type": "string", "format": "uuid"}
+      ],
+      "responses": {
+        "204": {"description": "No Content"},
+        "404": {"description": "Not found", "schema": {"$ref": "#/definitions/Error"}}
+      }
+    }
+  },
+  "/users/{user_id}/renewals.ics": {
+    "get": {
+      "summary": "iCalendar feed of renewals",
+      "produces": ["text/calendar"],
+      "parameters": [
+        {"in": "path", "name": "user_id", "required": true, "type": "string", "format": "uuid"},
+        {"in": "query", "name": "token", "required": true, "type": "string"}
+      ],
+      "responses": {
+        "200": {"description": "RFC 5545 calendar", "schema": {"type": "string"}},
+        "403": {"description": "Forbidden", "schema": {"$ref": "#/definitions/Error"}}
+      }
+    }
+  },
+  "/budgets": {
+    "post": {
+      "summary": "Create budget",
+      "parameters": [
+        {"in": "body", "name": "budget", "required": true, "schema": {"$ref": "#/definitions/BudgetRequest"}}
+      ],
+      "responses": {
+        "201": {"description": "Created", "schema": {"$ref": "#/definitions/Budget"}},
+        "400": {"description": "Bad request", "schema": {"$ref": "#/definitions/Error"}},
+        "409": {"description": "Conflict", "schema": {"$ref": "#/definitions/Error"}}
+      }
+    },
+    "get": {
+      "summary": "List budgets",
+      "parameters": [
+        {"in": "query", "name": "user_id", "type": "string", "format": "uuid"}
+      ],
+      "responses": {
+        "200": {"description": "OK", "schema": {"type": "array", "items": {"$ref": "#/definitions/Budget"}}},
+        "400": {"description": "Bad request", "schema": {"$ref": "#/definitions/Error"}}
+      }
+    }
+  },
+  "/budgets/{id}": {
+    "get": {
+      "summary": "Get budget",
+      "parameters": [
+        {"in": "path", "name": "id", "required": true, "type": "string", "format": "uuid"}
+      ],
+      "responses": {
+        "200": {"description": "OK", "schema": {"$ref": "#/definitions/Budget"}},
+        "404": {"description": "Not found", "schema": {"$ref": "#/definitions/Error"}}
+      }
+    },
+    "put": {
+      "summary": "Update budget",
+      "parameters": [
+        {"in": "path", "name": "id", "required": true, "type": "string", "format": "uuid"},
+        {"in": "body", "name": "budget", "required": true, "schema": {"$ref": "#/definitions/BudgetRequest"}}
+      ],
+      "responses": {
+        "200": {"description": "OK", "schema": {"$ref": "#/definitions/Budget"}},
+        "400": {"description": "Bad request", "schema": {"$ref": "#/definitions/Error"}},
+        "404": {"description": "Not found", "schema": {"$ref": "#/definitions/Error"}},
+        "409": {"description": "Conflict", "schema": {"$ref": "#/definitions/Error"}}
+      }
+    },
+    "delete": {
+      "summary": "Delete budget",
+      "parameters": [
+        {"in": "path", "name": "id", "required": true, "type": "string", "format": "uuid"}
+      ],
+      "responses": {
+        "204": {"description": "No Content"},
+        "404": {"description": "Not found", "schema": {"$ref": "#/definitions/Error"}}
+      }
+    }
+  },
+  "/users/{user_id}/budget-status": {
+    "get": {
+      "summary": "Budget status",
+      "description": "Spend vs. limit for every month from start to end, both defaulting to the current month.",
+      "parameters": [
+        {"in": "path", "name": "user_id", "required": true, "type": "string", "format": "uuid"},
+        {"in": "query", "name": "start", "type": "string", "example": "01-2026"},
+        {"in": "query", "name": "end", "type": "string", "example": "12-2026"}
+      ],
+      "responses": {
+        "200": {"description": "OK", "schema": {"type": "array", "items": {"$ref": "#/definitions/BudgetStatus"}}},
+        "400": {"description": "Bad request", "schema": {"$ref": "#/definitions/Error"}}
+      }
+    }
+  },
+  "/subscriptions/forecast": {
+    "get": {
+      "summary": "Forecast spend",
+      "description": "Projected spend per month starting with the current month, with a running total. Subscriptions bill monthly: open-ended ones run on, end dates, trials, pauses, scheduled price changes and discounts are applied.",
+      "parameters": [
+        {"in": "query", "name": "months", "type": "integer", "default": 12, "maximum": 120},
+        {"in": "query", "name": "user_id", "type": "string", "format": "uuid"},
+        {"in": "query", "name": "service_name", "type": "string"},
+        {"in": "query", "name": "group_by", "type": "string", "enum": ["user", "service"]}
+      ],
+      "responses": {
+        "200": {"description": "OK", "schema": {"$ref": "#/definitions/Forecast"}},
+        "400": {"description": "Bad request", "schema": {"$ref": "#/definitions/Error"}}
+      }
+    }
+  },
+  "/categories": {
+    "post": {
+      "summary": "Create category",
+      "parameters": [
+        {"in": "body", "name": "category", "required": true, "schema": {"$ref": "#/definitions/CategoryRequest"}}
+      ],
+      "responses": {
+        "201": {"description": "Created", "schema": {"$ref": "#/definitions/Category"}},
+        "400": {"description": "Bad request", "schema": {"$ref": "#/definitions/Error"}},
+        "409": {"description": "Conflict", "schema": {"$ref": "#/definitions/Error"}}
+      }
+    },
+    "get": {
+      "summary": "List categories",
+      "responses": {
+        "200": {"description": "OK", "schema": {"type": "array", "items": {"$ref": "#/definitions/Category"}}}
+      }
+    }
+  },
+  "/categories/{id}": {
+    "get": {
+      "summary": "Get category",
+      "parameters": [
+        {"in": "path", "name": "id", "required": true, "type": "string", "format": "uuid"}
+      ],
+      "responses": {
+        "200": {"description": "OK", "schema": {"$ref": "#/definitions/Category"}},
+        "404": {"description": "Not found", "schema": {"$ref": "#/definitions/Error"}}
+      }
+    },
+    "put": {
+      "summary": "Update category",
+      "description": "Replaces the name and the tax rate; omitting tax_rate clears it.",
+      "parameters": [
+        {"in": "path", "name": "id", "required": true, "type": "string", "format": "uuid"},
+        {"in": "body", "name": "category", "required": true, "schema": {"$ref": "#/definitions/CategoryRequest"}}
+      ],
+      "responses": {
+        "200": {"description": "OK", "schema": {"$ref": "#/definitions/Category"}},
+        "400": {"description": "Bad request", "schema": {"$ref": "#/definitions/Error"}},
+        "404": {"description": "Not found", "schema": {"$ref": "#/definitions/Error"}},
+        "409": {"description": "Conflict", "schema": {"$ref": "#/definitions/Error"}}
+      }
+    },
+    "delete": {
+      "summary": "Delete category",
+      "description": "Subscriptions in the category become uncategorized; budgets for it are deleted.",
+      "parameters": [
+        {"in": "path", "name": "id", "required": true, "type": "string", "format": "uuid"}
+      ],
+      "responses": {
+        "204": {"description": "No Content"},
+        "404": {"description": "Not found", "schema": {"$ref": "#/definitions/Error"}}
+      }
+    }
+  },
+  "/payment-methods": {
+    "post": {
+      "summary": "Create payment method",
+      "parameters": [
+        {"in": "body", "name": "payment_method", "required": true, "schema": {"$ref": "#/definitions/PaymentMethodRequest"}}
+      ],
+      "responses": {
+        "201": {"description": "Created", "schema": {"$ref": "#/definitions/PaymentMethod"}},
+        "400": {"description": "Bad request", "schema": {"$ref": "#/definitions/Error"}}
+      }
+    },
+    "get": {
+      "summary": "List payment methods",
+      "responses": {
+        "200": {"description": "OK", "schema": {"type": "array", "items": {"$ref": "#/definitions/PaymentMethod"}}}
+      }
+    }
+  },
+  "/payment-methods/expiring": {
+    "get": {
+      "summary": "Payment methods expiring soon",
+      "description": "Payment methods whose last chargeable month is within within_months months, counting the current one, or has passed, with the subscriptions that still charge them.",
+      "parameters": [
+        {"in": "query", "name": "within_months", "type": "integer", "default": 3}
+      ],
+      "responses": {
+        "200": {"description": "OK", "schema": {"type": "array", "items": {"$ref": "#/definitions/ExpiringPaymentMethod"}}},
+        "400": {"description": "Bad request", "schema": {"$ref": "#/definitions/Error"}}
+      }
+    }
+  },
+  "/payment-methods/{id}": {
+    "get": {
+      "summary": "Get payment method",
+      "parameters": [
+        {"in": "path", "name": "id", "required": true, "type": "string", "format": "uuid"}
+      ],
+      "responses": {
+        "200": {"description": "OK", "schema": {"$ref": "#/definitions/PaymentMethod"}},
+        "404": {"description": "Not found", "schema": {"$ref": "#/definitions/Error"}}
+      }
+    },
+    "put": {
+      "summary": "Update payment method",
+      "parameters": [
+        {"in": "path", "name": "id", "required": true, "type": "string", "format": "uuid"},
+        {"in": "body", "name": "payment_method", "required": true, "schema": {"$ref": "#/definitions/PaymentMethodRequest"}}
+      ],
+      "responses": {
+        "200": {"description": "OK", "schema": {"$ref": "#/definitions/PaymentMethod"}},
+        "400": {"description": "Bad request", "schema": {"$ref": "#/definitions/Error"}},
+        "404": {"description": "Not found", "schema": {"$ref": "#/definitions/Error"}}
+      }
+    },
+    "delete": {
+      "summary": "Delete payment method",
+      "description": "Subscriptions paid with the method are left without one.",
+      "parameters": [
+        {"in": "path", "name": "id", "required": true, "type": "string", "format": "uuid"}
+      ],
+      "responses": {
+        "204": {"description": "No Content"},
+        "404": {"description": "Not found", "schema": {"$ref": "#/definitions/Error"}}
+      }
+    }
+  },
+  "/services": {
+    "post": {
+      "summary": "Create catalog service",
+      "description": "Subscriptions naming the service or one of its aliases are stored under the canonical name.",
+      "parameters": [
+        {"in": "body", "name": "service", "required": true, "schema": {"$ref": "#/definitions/ServiceRequest"}}
+      ],
+      "responses": {
+        "201": {"description": "Created", "schema": {"$ref": "#/definitions/Service"}},
+        "400": {"description": "Bad request", "schema": {"$ref": "#/definitions/Error"}},
+        "409": {"description": "Conflict", "schema": {"$ref": "#/definitions/Error"}}
+      }
+    },
+    "get": {
+      "summary": "List catalog services",
+      "responses": {
+        "200": {"description": "OK", "schema": {"type": "array", "items": {"$ref": "#/definitions/Service"}}}
+      }
+    }
+  },
+  "/services/resolve": {
+    "get": {
+      "summary": "Resolve service name",
+      "description": "Returns the catalog service a name resolves to, matching aliases and small typos.",
+      "parameters": [
+        {"in": "query", "name": "name", "required": true, "type": "string"}
+      ],
+      "responses": {
+        "200": {"description": "OK", "schema": {"$ref": "#/definitions/Service"}},
+        "400": {"description": "Bad request", "schema": {"$ref": "#/definitions/Error"}},
+        "404": {"description": "Not found", "schema": {"$ref": "#/definitions/Error"}}
+      }
+    }
+  },
+  "/services/{id}": {
+    "get": {
+      "summary": "Get catalog service",
+      "parameters": [
+        {"in": "path", "name": "id", "required": true, "type": "string", "format": "uuid"}
+      ],
+      "responses": {
+        "200": {"description": "OK", "schema": {"$ref": "#/definitions/Service"}},
+        "404": {"description": "Not found", "schema": {"$ref": "#/definitions/Error"}}
+      }
+    },
+    "put": {
+      "summary": "Update catalog service",
+      "description": "Existing subscriptions keep the name they were stored with.",
+      "parameters": [
+        {"in": "path", "name": "id", "required": true, "type": "string", "format": "uuid"},
+        {"in": "body", "name": "service", "required": true, "schema": {"$ref": "#/definitions/ServiceRequest"}}
+      ],
+      "responses": {
+        "200": {"description": "OK", "schema": {"$ref": "#/definitions/Service"}},
+        "400": {"description": "Bad request", "schema": {"$ref": "#/definitions/Error"}},
+        "404": {"description": "Not found", "schema": {"$ref": "#/definitions/Error"}},
+        "409": {"description": "Conflict", "schema": {"$ref": "#/definitions/Error"}}
+      }
+    },
+    "delete": {
+      "summary": "Delete catalog service",
+      "parameters": [
+        {"in": "path", "name": "id", "required": true, "type": "string", "format": "uuid"}
+      ],
+      "responses": {
+        "204": {"description": "No Content"},
+        "404": {"description": "Not found", "schema": {"$ref": "#/definitions/Error"}}
+      }
+    }
+  },
+  "/users": {
+    "post": {
+      "summary": "Create user",
+      "parameters": [
+        {"in": "body", "name": "user", "required": true, "schema": {"$ref": "#/definitions/UserRequest"}}
+      ],
+      "responses": {
+        "201": {"description": "Created", "schema": {"$ref": "#/definitions/User"}},
+        "400": {"description": "Bad request", "schema": {"$ref": "#/definitions/Error"}},
+        "409": {"description": "Conflict", "schema": {"$ref": "#/definitions/Error"}}
+      }
     },
     "get": {
-      "summary": "List subscriptions",
+      "summary": "List users",
       "parameters": [
-        {"in": "query", "name": "user_id", "type": "string", "format": "uuid"},
-        {"in": "query", "name": "service_name", "type": "string"},
+        {"in": "query", "name": "email", "type": "string", "description": "ignoring case"},
         {"in": "query", "name": "limit", "type": "integer"},
         {"in": "query", "name": "offset", "type": "integer"}
       ],
       "responses": {
-        "200": {"description": "OK", "schema": {"type": "array", "items": {"$ref": "#/definitions/Subscription"}}}
+        "200": {"description": "OK", "schema": {"type": "array", "items": {"$ref": "#/definitions/User"}}}
       }
     }
   },
-  "/subscriptions/{id}": {
+  "/users/{user_id}": {
     "get": {
-      "summary": "Get subscription by id",
+      "summary": "Get user",
       "parameters": [
-        {"in": "path", "name": "id", "required": true, "type": "string", "format": "uuid"}
+        {"in": "path", "name": "user_id", "required": true, "type": "string", "format": "uuid"}
       ],
       "responses": {
-        "200": {"description": "OK", "schema": {"$ref": "#/definitions/Subscription"}},
+        "200": {"description": "OK", "schema": {"$ref": "#/definitions/User"}},
         "404": {"description": "Not found", "schema": {"$ref": "#/definitions/Error"}}
       }
     },
     "put": {
-      "summary": "Update subscription",
+      "summary": "Update user",
       "parameters": [
-        {"in": "path", "name": "id", "required": true, "type": "string", "format": "uuid"},
-        {"in": "body", "name": "subscription", "required": true, "schema": {"$ref": "#/definitions/SubscriptionRequest"}}
+        {"in": "path", "name": "user_id", "required": true, "type": "string", "format": "uuid"},
+        {"in": "body", "name": "user", "required": true, "schema": {"$ref": "#/definitions/UserRequest"}}
       ],
       "responses": {
-        "200": {"description": "OK", "schema": {"$ref": "#/definitions/Subscription"}},
+        "200": {"description": "OK", "schema": {"$ref": "#/definitions/User"}},
         "400": {"description": "Bad request", "schema": {"$ref": "#/definitions/Error"}},
-        "404": {"description": "Not found", "schema": {"$ref": "#/definitions/Error"}}
+        "404": {"description": "Not found", "schema": {"$ref": "#/definitions/Error"}},
+        "409": {"description": "Conflict", "schema": {"$ref": "#/definitions/Error"}}
       }
     },
     "delete": {
-      "summary": "Delete subscription",
+      "summary": "Delete user",
+      "description": "Fails while the user owns subscriptions, deleted ones included; memberships in shared subscriptions are removed.",
       "parameters": [
-        {"in": "path", "name": "id", "required": true, "type": "string", "format": "uuid"}
+        {"in": "path", "name": "user_id", "required": true, "type": "string", "format": "uuid"}
       ],
       "responses": {
         "204": {"description": "No Content"},
+        "404": {"description": "Not found", "schema": {"$ref": "#/definitions/Error"}},
+        "409": {"description": "Conflict", "schema": {"$ref": "#/definitions/Error"}}
+      }
+    }
+  },
+  "/users/{user_id}/overview": {
+    "get": {
+      "summary": "User overview",
+      "description": "Subscriptions and spend of the user as of today in their timezone. Spend counts their shares of shared subscriptions.",
+      "parameters": [
+        {"in": "path", "name": "user_id", "required": true, "type": "string", "format": "uuid"}
+      ],
+      "responses": {
+        "200": {"description": "OK", "schema": {"$ref": "#/definitions/UserOverview"}},
         "404": {"description": "Not found", "schema": {"$ref": "#/definitions/Error"}}
       }
     }
   },
-  "/subscriptions/summary": {
+  "/insights/duplicates": {
     "get": {
-      "summary": "Get total cost for period",
+      "summary": "Duplicate subscriptions",
+      "description": "Pairs of subscriptions for the same service, aliases included, whose periods overlap. same_user pairs are paid twice by one user; separate_plans pairs by different users who could share one subscription.",
       "parameters": [
-        {"in": "query", "name": "start", "required": true, "type": "string", "example": "07-2025"},
-        {"in": "query", "name": "end", "required": true, "type": "string", "example": "12-2025"},
+        {"in": "query", "name": "user_id", "type": "string", "format": "uuid", "description": "only pairs the user pays for"}
+      ],
+      "responses": {
+        "200": {"description": "OK", "schema": {"type": "array", "items": {"$ref": "#/definitions/Duplicate"}}},
+        "400": {"description": "Bad request", "schema": {"$ref": "#/definitions/Error"}}
+      }
+    }
+  },
+  "/insights/anomalies": {
+    "get": {
+      "summary": "Spend anomalies",
+      "description": "Months whose spend jumps compared with the average of the months before it, e.g. after a price increase or a forgotten trial. Spend is charged like the summary. The range defaults to the twelve months up to the current one.",
+      "parameters": [
+        {"in": "query", "name": "start", "type": "string", "description": "first month checked (MM-YYYY)"},
+        {"in": "query", "name": "end", "type": "string", "description": "last month checked (MM-YYYY, default current month)"},
         {"in": "query", "name": "user_id", "type": "string", "format": "uuid"},
-        {"in": "query", "name": "service_name", "type": "string"}
+        {"in": "query", "name": "service_name", "type": "string"},
+        {"in": "query", "name": "threshold", "type": "integer", "description": "percent above the trailing average that flags a month (default ANOMALY_THRESHOLD, max 1000)"},
+        {"in": "query", "name": "window", "type": "integer", "description": "months in the trailing average (default ANOMALY_WINDOW, max 12)"}
       ],
       "responses": {
-        "200": {
-          "description": "OK",
-          "schema": {
-            "type": "object",
-            "properties": {"total": {"type": "integer"}}
-          }
-        },
+        "200": {"description": "OK", "schema": {"type": "array", "items": {"$ref": "#/definitions/Anomaly"}}},
+        "400": {"description": "Bad request", "schema": {"$ref": "#/definitions/Error"}}
+      }
+    }
+  },
+  "/insights/recommendations": {
+    "get": {
+      "summary": "Savings recommendations",
+      "description": "Suggestions to spend less, the largest savings first: cancel_unused for subscriptions flagged unused, switch_to_yearly when the catalog's yearly_price is below twelve monthly charges and family_plan when several users pay for one service separately. monthly_savings is estimated from the gross charges of the current month, after discounts and with tax.",
+      "parameters": [
+        {"in": "query", "name": "user_id", "type": "string", "format": "uuid", "description": "only suggestions for subscriptions the user pays for"}
+      ],
+      "responses": {
+        "200": {"description": "OK", "schema": {"type": "array", "items": {"$ref": "#/definitions/Recommendation"}}},
         "400": {"description": "Bad request", "schema": {"$ref": "#/definitions/Error"}}
       }
     }
@@ -102,13 +832,23 @@ const docTemplate = `{
 "definitions": {
   "SubscriptionRequest": {
     "type": "object",
-    "required": ["service_name", "price", "user_id", "start_date"],
+    "required": ["service_name", "user_id", "start_date"],
     "properties": {
       "service_name": {"type": "string"},
-      "price": {"type": "integer"},
+      "price": {"type": "integer", "description": "required unless the catalog has a default price"},
       "user_id": {"type": "string", "format": "uuid"},
       "start_date": {"type": "string", "example": "07-2025"},
-      "end_date": {"type": "string", "example": "12-2025"}
+      "end_date": {"type": "string", "example": "12-2025"},
+      "trial_end_date": {"type": "string", "example": "08-2025", "description": "last free month"},
+      "trial_months": {"type": "integer", "description": "free months from start_date, instead of trial_end_date"},
+      "category_id": {"type": "string", "format": "uuid"},
+      "tags": {"type": "array", "items": {"type": "string"}},
+      "split_rule": {"type": "string", "enum": ["equal", "percentage", "fixed"], "description": "required with members"},
+      "members": {"type": "array", "items": {"$ref": "#/definitions/Member"}},
+      "tax_rate": {"type": "integer", "description": "basis points; defaults to the rate of the category"},
+      "tax_inclusive": {"type": "boolean", "description": "price includes the tax instead of having it added on top"},
+      "payment_method_id": {"type": "string", "format": "uuid"},
+      "unused": {"type": "boolean", "description": "no longer used; recommends cancelling it"}
     }
   },
   "Subscription": {
@@ -116,14 +856,430 @@ const docTemplate = `{
     "properties": {
       "id": {"type": "string", "format": "uuid"},
       "service_name": {"type": "string"},
-      "price": {"type": "integer"},
+      "price": {"type": "integer", "description": "list price of the current month"},
+      "effective_price": {"type": "integer", "description": "price of the current month after discounts"},
       "user_id": {"type": "string", "format": "uuid"},
       "start_date": {"type": "string"},
       "end_date": {"type": "string"},
+      "trial_end_date": {"type": "string"},
+      "category_id": {"type": "string", "format": "uuid"},
+      "category": {"type": "string"},
+      "payment_method_id": {"type": "string", "format": "uuid"},
+      "payment_method": {"type": "string", "description": "label of the payment method"},
+      "tags": {"type": "array", "items": {"type": "string"}},
+      "pauses": {"type": "array", "items": {"$ref": "#/definitions/Pause"}},
+      "discounts": {"type": "array", "items": {"$ref": "#/definitions/Discount"}},
+      "split_rule": {"type": "string", "enum": ["equal", "percentage", "fixed"]},
+      "members": {"type": "array", "items": {"$ref": "#/definitions/Member"}},
+      "shares": {"type": "array", "items": {"$ref": "#/definitions/Share"}, "description": "split of the current effective price, owner first"},
+      "tax_rate": {"type": "integer", "description": "basis points"},
+      "tax_inclusive": {"type": "boolean"},
+      "unused": {"type": "boolean"},
+      "status": {"type": "string", "enum": ["active", "cancelled_pending", "ended", "paused", "upcoming"]},
+      "next_billing_date": {"type": "string", "format": "date"},
+      "months_active": {"type": "integer"},
+      "lifetime_cost": {"type": "integer"},
+      "cancel_reason": {"type": "string"},
+      "cancelled_at": {"type": "string", "format": "date-time"},
+      "created_at": {"type": "string", "format": "date-time"},
+      "updated_at": {"type": "string", "format": "date-time"},
+      "deleted_at": {"type": "string", "format": "date-time"}
+    }
+  },
+  "Discount": {
+    "type": "object",
+    "properties": {
+      "id": {"type": "string", "format": "uuid"},
+      "kind": {"type": "string", "enum": ["percentage", "fixed"]},
+      "value": {"type": "integer", "description": "percent off or amount off"},
+      "from": {"type": "string", "example": "01-2026"},
+      "until": {"type": "string", "example": "03-2026"}
+    }
+  },
+  "DiscountRequest": {
+    "type": "object",
+    "required": ["kind", "value"],
+    "properties": {
+      "kind": {"type": "string", "enum": ["percentage", "fixed"]},
+      "value": {"type": "integer"},
+      "from": {"type": "string", "example": "01-2026", "description": "defaults to the current month, or start_date when later; must not be in the past"},
+      "until": {"type": "string", "example": "03-2026", "description": "last discounted month; open-ended when omitted"},
+      "first_months": {"type": "integer", "description": "discount the first N charged months instead of from and until"}
+    }
+  },
+  "PricePeriod": {
+    "type": "object",
+    "required": ["price", "effective_from"],
+    "properties": {
+      "price": {"type": "integer"},
+      "effective_from": {"type": "string", "example": "01-2026"}
+    }
+  },
+  "AuditEntry": {
+    "type": "object",
+    "properties": {
+      "id": {"type": "string", "format": "uuid"},
+      "subscription_id": {"type": "string", "format": "uuid"},
+      "action": {"type": "string", "enum": ["create", "update", "delete", "restore", "schedule_price", "cancel_price_change"]},
+      "actor": {"type": "string"},
+      "request_id": {"type": "string"},
+      "before": {"type": "object"},
+      "after": {"type": "object"},
+      "created_at": {"type": "string", "format": "date-time"}
+    }
+  },
+  "WebhookRequest": {
+    "type": "object",
+    "required": ["url"],
+    "properties": {
+      "url": {"type": "string"},
+      "secret": {"type": "string"},
+      "events": {"type": "array", "items": {"type": "string"}, "description": "empty means all event types"},
+      "active": {"type": "boolean"}
+    }
+  },
+  "Webhook": {
+    "type": "object",
+    "properties": {
+      "id": {"type": "string", "format": "uuid"},
+      "url": {"type": "string"},
+      "secret": {"type": "string"},
+      "events": {"type": "array", "items": {"type": "string"}},
+      "active": {"type": "boolean"},
+      "created_at": {"type": "string", "format": "date-time"},
+      "updated_at": {"type": "string", "format": "date-time"}
+    }
+  },
+  "WebhookDelivery": {
+    "type": "object",
+    "properties": {
+      "id": {"type": "string", "format": "uuid"},
+      "webhook_id": {"type": "string", "format": "uuid"},
+      "event_id": {"type": "string", "format": "uuid"},
+      "event_type": {"type": "string"},
+      "status": {"type": "string", "enum": ["pending", "retrying", "succeeded", "dead"]},
+      "attempts": {"type": "integer"},
+      "next_attempt_at": {"type": "string", "format": "date-time"},
+      "last_status_code": {"type": "integer"},
+      "last_error": {"type": "string"},
+      "created_at": {"type": "string", "format": "date-time"},
+      "updated_at": {"type": "string", "format": "date-time"},
+      "attempt_log": {
+        "type": "array",
+        "items": {
+          "type": "object",
+          "properties": {
+            "attempted_at": {"type": "string", "format": "date-time"},
+            "status_code": {"type": "integer"},
+            "error": {"type": "string"},
+            "duration_ms": {"type": "integer"}
+          }
+        }
+      }
+    }
+  },
+  "CalendarToken": {
+    "type": "object",
+    "properties": {
+      "token": {"type": "string"},
+      "url": {"type": "string"}
+    }
+  },
+  "BudgetRequest": {
+    "type": "object",
+    "required": ["user_id", "monthly_limit"],
+    "properties": {
+      "user_id": {"type": "string", "format": "uuid"},
+      "category_id": {"type": "string", "format": "uuid"},
+      "monthly_limit": {"type": "integer"}
+    }
+  },
+  "Budget": {
+    "type": "object",
+    "properties": {
+      "id": {"type": "string", "format": "uuid"},
+      "user_id": {"type": "string", "format": "uuid"},
+      "category_id": {"type": "string", "format": "uuid"},
+      "monthly_limit": {"type": "integer"},
+      "created_at": {"type": "string", "format": "date-time"},
+      "updated_at": {"type": "string", "format": "date-time"}
+    }
+  },
+  "BudgetStatus": {
+    "type": "object",
+    "properties": {
+      "budget_id": {"type": "string", "format": "uuid"},
+      "category_id": {"type": "string", "format": "uuid"},
+      "monthly_limit": {"type": "integer"},
+      "months": {
+        "type": "array",
+        "items": {
+          "type": "object",
+          "properties": {
+            "month": {"type": "string", "example": "01-2026"},
+            "spent": {"type": "integer"},
+            "limit": {"type": "integer"},
+            "remaining": {"type": "integer"},
+            "exceeded": {"type": "boolean"}
+          }
+        }
+      }
+    }
+  },
+  "Forecast": {
+    "type": "object",
+    "properties": {
+      "months": {"type": "array", "items": {"$ref": "#/definitions/ForecastMonth"}},
+      "total": {"type": "integer"},
+      "groups": {
+        "type": "array",
+        "description": "only with group_by, largest total first",
+        "items": {
+          "type": "object",
+          "properties": {
+            "user_id": {"type": "string", "format": "uuid", "description": "with group_by=user"},
+            "service_name": {"type": "string", "description": "with group_by=service"},
+            "months": {"type": "array", "items": {"$ref": "#/definitions/ForecastMonth"}},
+            "total": {"type": "integer"}
+          }
+        }
+      }
+    }
+  },
+  "ForecastMonth": {
+    "type": "object",
+    "properties": {
+      "month": {"type": "string", "example": "01-2026"},
+      "total": {"type": "integer"},
+      "cumulative": {"type": "integer"}
+    }
+  },
+  "Summary": {
+    "type": "object",
+    "properties": {
+      "total": {"type": "integer", "description": "charges as priced"},
+      "net": {"type": "integer", "description": "charges without tax"},
+      "tax": {"type": "integer"},
+      "gross": {"type": "integer", "description": "net plus tax"},
+      "groups": {
+        "type": "array",
+        "description": "present with group_by; id and name are null for subscriptions outside any group",
+        "items": {
+          "type": "object",
+          "properties": {
+            "id": {"type": "string", "format": "uuid"},
+            "name": {"type": "string"},
+            "total": {"type": "integer"},
+            "net": {"type": "integer"},
+            "tax": {"type": "integer"},
+            "gross": {"type": "integer"}
+          }
+        }
+      }
+    }
+  },
+  "CategoryRequest": {
+    "type": "object",
+    "required": ["name"],
+    "properties": {
+      "name": {"type": "string"},
+      "tax_rate": {"type": "integer", "description": "basis points, e.g. 2000 for 20%; applies to subscriptions without their own rate"}
+    }
+  },
+  "Category": {
+    "type": "object",
+    "properties": {
+      "id": {"type": "string", "format": "uuid"},
+      "name": {"type": "string"},
+      "tax_rate": {"type": "integer", "description": "basis points"},
+      "created_at": {"type": "string", "format": "date-time"},
+      "updated_at": {"type": "string", "format": "date-time"}
+    }
+  },
+  "PaymentMethodRequest": {
+    "type": "object",
+    "required": ["label", "type"],
+    "properties": {
+      "label": {"type": "string", "example": "Corporate Visa"},
+      "type": {"type": "string", "enum": ["card", "bank_account", "wallet", "other"]},
+      "last4": {"type": "string", "example": "4242"},
+      "expiry": {"type": "string", "example": "09-2027", "description": "last month the method can be charged in"}
+    }
+  },
+  "PaymentMethod": {
+    "type": "object",
+    "properties": {
+      "id": {"type": "string", "format": "uuid"},
+      "label": {"type": "string"},
+      "type": {"type": "string", "enum": ["card", "bank_account", "wallet", "other"]},
+      "last4": {"type": "string"},
+      "expiry": {"type": "string", "example": "09-2027"},
+      "created_at": {"type": "string", "format": "date-time"},
+      "updated_at": {"type": "string", "format": "date-time"}
+    }
+  },
+  "ExpiringPaymentMethod": {
+    "type": "object",
+    "properties": {
+      "payment_method": {"$ref": "#/definitions/PaymentMethod"},
+      "expired": {"type": "boolean"},
+      "subscriptions": {"type": "array", "items": {"$ref": "#/definitions/Subscription"}}
+    }
+  },
+  "ServiceRequest": {
+    "type": "object",
+    "required": ["name"],
+    "properties": {
+      "name": {"type": "string"},
+      "aliases": {"type": "array", "items": {"type": "string"}},
+      "default_price": {"type": "integer"},
+      "yearly_price": {"type": "integer", "description": "price of a year billed at once"},
+      "family_price": {"type": "integer", "description": "monthly price of a plan shared by a household"},
+      "vendor_url": {"type": "string"},
+      "category_id": {"type": "string", "format": "uuid"}
+    }
+  },
+  "Service": {
+    "type": "object",
+    "properties": {
+      "id": {"type": "string", "format": "uuid"},
+      "name": {"type": "string"},
+      "aliases": {"type": "array", "items": {"type": "string"}},
+      "default_price": {"type": "integer"},
+      "yearly_price": {"type": "integer", "description": "price of a year billed at once"},
+      "family_price": {"type": "integer", "description": "monthly price of a plan shared by a household"},
+      "vendor_url": {"type": "string"},
+      "category_id": {"type": "string", "format": "uuid"},
+      "created_at": {"type": "string", "format": "date-time"},
+      "updated_at": {"type": "string", "format": "date-time"}
+    }
+  },
+  "PauseRequest": {
+    "type": "object",
+    "properties": {
+      "from": {"type": "string", "example": "09-2025"},
+      "until": {"type": "string", "example": "11-2025"}
+    }
+  },
+  "ResumeRequest": {
+    "type": "object",
+    "properties": {
+      "from": {"type": "string", "example": "12-2025"}
+    }
+  },
+  "Pause": {
+    "type": "object",
+    "properties": {
+      "from": {"type": "string"},
+      "until": {"type": "string"}
+    }
+  },
+  "CancelRequest": {
+    "type": "object",
+    "required": ["reason"],
+    "properties": {
+      "when": {"type": "string", "example": "end_of_period", "description": "immediately, end_of_period or MM-YYYY"},
+      "reason": {"type": "string", "enum": ["too_expensive", "not_using", "switched_service", "missing_features", "technical_issues", "temporary", "other"]}
+    }
+  },
+  "Member": {
+    "type": "object",
+    "required": ["user_id"],
+    "properties": {
+      "user_id": {"type": "string", "format": "uuid"},
+      "share": {"type": "integer", "description": "percentage with the percentage rule, monthly amount with the fixed rule"}
+    }
+  },
+  "Share": {
+    "type": "object",
+    "properties": {
+      "user_id": {"type": "string", "format": "uuid"},
+      "amount": {"type": "integer"}
+    }
+  },
+  "UserRequest": {
+    "type": "object",
+    "required": ["name"],
+    "properties": {
+      "name": {"type": "string"},
+      "email": {"type": "string", "format": "email"},
+      "currency": {"type": "string", "example": "RUB", "description": "ISO 4217, defaults to RUB"},
+      "timezone": {"type": "string", "example": "Europe/Moscow", "description": "IANA zone, defaults to UTC"}
+    }
+  },
+  "User": {
+    "type": "object",
+    "properties": {
+      "id": {"type": "string", "format": "uuid"},
+      "name": {"type": "string"},
+      "email": {"type": "string", "format": "email"},
+      "currency": {"type": "string"},
+      "timezone": {"type": "string"},
       "created_at": {"type": "string", "format": "date-time"},
       "updated_at": {"type": "string", "format": "date-time"}
     }
   },
+  "UserOverview": {
+    "type": "object",
+    "properties": {
+      "user": {"$ref": "#/definitions/User"},
+      "statuses": {"type": "object", "additionalProperties": {"type": "integer"}, "description": "subscriptions that have not ended, by status"},
+      "monthly_spend": {"type": "integer"},
+      "by_category": {
+        "type": "array",
+        "items": {
+          "type": "object",
+          "properties": {
+            "id": {"type": "string", "format": "uuid"},
+            "name": {"type": "string"},
+            "total": {"type": "integer"},
+            "net": {"type": "integer"},
+            "tax": {"type": "integer"},
+            "gross": {"type": "integer"}
+          }
+        }
+      },
+      "yearly_spend": {"type": "integer", "description": "the twelve months starting with the current one"},
+      "next_charge": {
+        "type": "object",
+        "description": "next charge of a subscription the user owns or is a member of",
+        "properties": {
+          "subscription_id": {"type": "string", "format": "uuid"},
+          "service_name": {"type": "string"},
+          "date": {"type": "string", "format": "date"},
+          "amount": {"type": "integer", "description": "the user's share"}
+        }
+      }
+    }
+  },
+  "Duplicate": {
+    "type": "object",
+    "properties": {
+      "kind": {"type": "string", "enum": ["same_user", "separate_plans"]},
+      "service_name": {"type": "string"},
+      "from": {"type": "string", "example": "01-2026", "description": "first month both run in"},
+      "until": {"type": "string", "example": "06-2026", "description": "last month both run in; omitted when open-ended"},
+      "subscriptions": {"type": "array", "items": {"$ref": "#/definitions/Subscription"}}
+    }
+  },
+  "Anomaly": {
+    "type": "object",
+    "properties": {
+      "month": {"type": "string", "example": "03-2026"},
+      "total": {"type": "integer"},
+      "trailing_average": {"type": "integer", "description": "average spend of the window months before"},
+      "change_percent": {"type": "integer", "description": "increase over trailing_average"}
+    }
+  },
+  "Recommendation": {
+    "type": "object",
+    "properties": {
+      "kind": {"type": "string", "enum": ["cancel_unused", "switch_to_yearly", "family_plan"]},
+      "service_name": {"type": "string"},
+      "monthly_savings": {"type": "integer"},
+      "subscriptions": {"type": "array", "items": {"$ref": "#/definitions/Subscription"}}
+    }
+  },
   "Error": {
     "type": "object",
     "properties": {"error": {"type": "string"}}
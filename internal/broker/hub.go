@@ -0,0 +1,88 @@
+package broker
+
+import (
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// Hub is an in-process broker that wakes up stream subscribers when events
+// for a user may be available. Signals carry no data: subscribers read the
+// events themselves, so a dropped or coalesced signal loses nothing.
+type Hub struct {
+	mu     sync.Mutex
+	subs   map[*subscriber]struct{}
+	closed bool
+}
+
+type subscriber struct {
+	ch     chan struct{}
+	userID *uuid.UUID
+}
+
+func NewHub() *Hub {
+	return &Hub{subs: make(map[*subscriber]struct{})}
+}
+
+// Subscribe registers for signals about userID, or about every user when
+// userID is nil. The returned function unsubscribes.
+func (h *Hub) Subscribe(userID *uuid.UUID) (<-chan struct{}, func()) {
+	sub := &subscriber{ch: make(chan struct{}, 1), userID: userID}
+
+	h.mu.Lock()
+	if h.closed {
+		h.mu.Unlock()
+		close(sub.ch)
+		return sub.ch, func() {}
+	}
+	h.subs[sub] = struct{}{}
+	h.mu.Unlock()
+
+	return sub.ch, func() {
+		h.mu.Lock()
+		delete(h.subs, sub)
+		h.mu.Unlock()
+	}
+}
+
+// Notify signals subscribers interested in userID without blocking.
+func (h *Hub) Notify(userID uuid.UUID) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for sub := range h.subs {
+		if sub.userID != nil && *sub.userID != userID {
+			continue
+		}
+		select {
+		case sub.ch <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// NotifyAll signals every subscriber, e.g. after a missed notification.
+func (h *Hub) NotifyAll() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for sub := range h.subs {
+		select {
+		case sub.ch <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// Close closes every subscriber channel so that long-lived readers return,
+// e.g. on shutdown. Later subscriptions get an already closed channel.
+func (h *Hub) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.closed = true
+	for sub := range h.subs {
+		close(sub.ch)
+		delete(h.subs, sub)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/joho/godotenv"
@@ -20,10 +21,101 @@ type DBConfig struct {
 	URL string
 }
 
+// PurgeConfig controls permanent removal of soft-deleted subscriptions.
+// A zero Retention disables purging.
+type PurgeConfig struct {
+	Interval  time.Duration
+	Retention time.Duration
+}
+
+// EventsConfig selects where outbox events are published. File is a path or
+// "stdout"; with neither File nor WebhookURL set events stay in the outbox.
+// An event is parked after MaxAttempts failed attempts.
+type EventsConfig struct {
+	RelayInterval time.Duration
+	File          string
+	WebhookURL    string
+	MaxAttempts   int
+}
+
+type WebhooksConfig struct {
+	DeliveryInterval time.Duration
+	Timeout          time.Duration
+	MaxAttempts      int
+}
+
+// RemindersConfig controls renewal and ending reminders. Reminders are sent
+// Days ahead of the due date; zero Days disables them. Reminders always go to
+// the log, and to SMTP and the webhook when their addresses are set.
+type RemindersConfig struct {
+	Interval      time.Duration
+	Days          int
+	WebhookURL    string
+	WebhookSecret string
+	SMTP          SMTPConfig
+}
+
+type SMTPConfig struct {
+	Addr     string
+	Username string
+	Password string
+	From     string
+	To       []string
+}
+
+// CatalogConfig controls the service catalog. In strict mode subscriptions
+// can only be created for services in the catalog.
+type CatalogConfig struct {
+	Strict bool
+}
+
+// UsersConfig controls how subscriptions for unknown users are handled. In
+// strict mode they are rejected; otherwise the users are registered.
+type UsersConfig struct {
+	Strict bool
+}
+
+// OverlapsConfig controls overlapping subscriptions. In strict mode a user
+// cannot create a subscription overlapping another one for the same service.
+type OverlapsConfig struct {
+	Strict bool
+}
+
+// AnomaliesConfig sets the defaults for spend anomalies: a month is flagged
+// when its spend is Threshold percent above the average of the Window months
+// before it. With Notify reminders also go out for the current month.
+type AnomaliesConfig struct {
+	Threshold int
+	Window    int
+	Notify    bool
+}
+
+// ClockConfig pins the time the service works with, e.g. for demos. A zero
+// FrozenAt uses the real time.
+type ClockConfig struct {
+	FrozenAt time.Time
+}
+
+// AdminConfig holds the token that unlocks admin-only reads, sent in the
+// X-Admin-Token header. An empty Token disables them.
+type AdminConfig struct {
+	Token string
+}
+
 type Config struct {
-	Env  string
-	HTTP HTTPConfig
-	DB   DBConfig
+	Env       string
+	HTTP      HTTPConfig
+	DB        DBConfig
+	Purge     PurgeConfig
+	Events    EventsConfig
+	Webhooks  WebhooksConfig
+	Reminders RemindersConfig
+	Catalog   CatalogConfig
+	Users     UsersConfig
+	Overlaps  OverlapsConfig
+	Anomalies AnomaliesConfig
+	Clock     ClockConfig
+	Admin     AdminConfig
 }
 
 func Load() (Config, error) {
@@ -37,6 +129,27 @@ func Load() (Config, error) {
 			WriteTimeout: 10 * time.Second,
 			IdleTimeout:  60 * time.Second,
 		},
+		Purge: PurgeConfig{
+			Interval:  time.Hour,
+			Retention: 30 * 24 * time.Hour,
+		},
+		Events: EventsConfig{
+			RelayInterval: 5 * time.Second,
+			MaxAttempts:   10,
+		},
+		Webhooks: WebhooksConfig{
+			DeliveryInterval: 5 * time.Second,
+			Timeout:          10 * time.Second,
+			MaxAttempts:      8,
+		},
+		Reminders: RemindersConfig{
+			Interval: time.Hour,
+			Days:     3,
+		},
+		Anomalies: AnomaliesConfig{
+			Threshold: 50,
+			Window:    3,
+		},
 	}
 
 	if v := os.Getenv("ENV"); v != "" {
@@ -64,6 +177,122 @@ func Load() (Config, error) {
 			cfg.HTTP.IdleTimeout = d
 		}
 	}
+	if v := os.Getenv("PURGE_INTERVAL"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil && d > 0 {
+			cfg.Purge.Interval = d
+		}
+	}
+	if v := os.Getenv("PURGE_RETENTION"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil {
+			cfg.Purge.Retention = d
+		}
+	}
+	if v := os.Getenv("EVENTS_RELAY_INTERVAL"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil && d > 0 {
+			cfg.Events.RelayInterval = d
+		}
+	}
+	cfg.Events.File = os.Getenv("EVENTS_FILE")
+	cfg.Events.WebhookURL = os.Getenv("EVENTS_WEBHOOK_URL")
+	if v := os.Getenv("EVENTS_MAX_ATTEMPTS"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			return cfg, errors.New("invalid EVENTS_MAX_ATTEMPTS")
+		}
+		cfg.Events.MaxAttempts = n
+	}
+	if v := os.Getenv("WEBHOOK_DELIVERY_INTERVAL"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil && d > 0 {
+			cfg.Webhooks.DeliveryInterval = d
+		}
+	}
+	if v := os.Getenv("WEBHOOK_TIMEOUT"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil && d > 0 {
+			cfg.Webhooks.Timeout = d
+		}
+	}
+	if v := os.Getenv("WEBHOOK_MAX_ATTEMPTS"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			return cfg, errors.New("invalid WEBHOOK_MAX_ATTEMPTS")
+		}
+		cfg.Webhooks.MaxAttempts = n
+	}
+	if v := os.Getenv("REMINDER_INTERVAL"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil && d > 0 {
+			cfg.Reminders.Interval = d
+		}
+	}
+	if v := os.Getenv("REMINDER_DAYS"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			return cfg, errors.New("invalid REMINDER_DAYS")
+		}
+		cfg.Reminders.Days = n
+	}
+	cfg.Reminders.WebhookURL = os.Getenv("REMINDER_WEBHOOK_URL")
+	cfg.Reminders.WebhookSecret = os.Getenv("REMINDER_WEBHOOK_SECRET")
+	cfg.Reminders.SMTP.Addr = os.Getenv("SMTP_ADDR")
+	cfg.Reminders.SMTP.Username = os.Getenv("SMTP_USERNAME")
+	cfg.Reminders.SMTP.Password = os.Getenv("SMTP_PASSWORD")
+	cfg.Reminders.SMTP.From = os.Getenv("SMTP_FROM")
+	for _, to := range strings.Split(os.Getenv("SMTP_TO"), ",") {
+		if to = strings.TrimSpace(to); to != "" {
+			cfg.Reminders.SMTP.To = append(cfg.Reminders.SMTP.To, to)
+		}
+	}
+	if v := os.Getenv("CATALOG_STRICT"); v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return cfg, errors.New("invalid CATALOG_STRICT")
+		}
+		cfg.Catalog.Strict = b
+	}
+	if v := os.Getenv("USERS_STRICT"); v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return cfg, errors.New("invalid USERS_STRICT")
+		}
+		cfg.Users.Strict = b
+	}
+	if v := os.Getenv("OVERLAPS_STRICT"); v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return cfg, errors.New("invalid OVERLAPS_STRICT")
+		}
+		cfg.Overlaps.Strict = b
+	}
+	if v := os.Getenv("ANOMALY_THRESHOLD"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 || n > 1000 {
+			return cfg, errors.New("invalid ANOMALY_THRESHOLD")
+		}
+		cfg.Anomalies.Threshold = n
+	}
+	if v := os.Getenv("ANOMALY_WINDOW"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 || n > 12 {
+			return cfg, errors.New("invalid ANOMALY_WINDOW")
+		}
+		cfg.Anomalies.Window = n
+	}
+	if v := os.Getenv("ANOMALY_NOTIFY"); v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return cfg, errors.New("invalid ANOMALY_NOTIFY")
+		}
+		cfg.Anomalies.Notify = b
+	}
+	if v := os.Getenv("CLOCK_FROZEN_AT"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			if t, err = time.Parse(time.DateOnly, v); err != nil {
+				return cfg, errors.New("invalid CLOCK_FROZEN_AT")
+			}
+		}
+		cfg.Clock.FrozenAt = t
+	}
+	cfg.Admin.Token = os.Getenv("ADMIN_TOKEN")
 	if v := os.Getenv("DB_URL"); v != "" {
 		cfg.DB.URL = v
 	}
@@ -0,0 +1,36 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	AuditActionCreate            = "create"
+	AuditActionUpdate            = "update"
+	AuditActionDelete            = "delete"
+	AuditActionRestore           = "restore"
+	AuditActionSchedulePrice     = "schedule_price"
+	AuditActionCancelPriceChange = "cancel_price_change"
+	AuditActionPause             = "pause"
+	AuditActionResume            = "resume"
+	AuditActionCancel            = "cancel"
+	AuditActionReactivate        = "reactivate"
+	AuditActionAddDiscount       = "add_discount"
+	AuditActionRemoveDiscount    = "remove_discount"
+)
+
+// AuditEntry records a single change of a subscription. Before and After hold
+// JSON snapshots; Before is nil for creations and After is nil for deletions.
+type AuditEntry struct {
+	ID             uuid.UUID
+	SubscriptionID uuid.UUID
+	Action         string
+	Actor          string
+	RequestID      string
+	Before         json.RawMessage
+	After          json.RawMessage
+	CreatedAt      time.Time
+}
@@ -0,0 +1,18 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Budget caps what a user should spend on subscriptions per month, either in
+// total or, with CategoryID set, in one category.
+type Budget struct {
+	ID           uuid.UUID
+	UserID       uuid.UUID
+	CategoryID   *uuid.UUID
+	MonthlyLimit int
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
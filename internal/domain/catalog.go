@@ -0,0 +1,24 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CatalogService is an entry of the service catalog. Subscriptions naming the
+// service or one of its aliases are stored under the canonical Name.
+// YearlyPrice and FamilyPrice, the price of a year billed at once and of a plan
+// shared by a household, feed the savings recommendations.
+type CatalogService struct {
+	ID           uuid.UUID
+	Name         string
+	Aliases      []string
+	DefaultPrice *int
+	YearlyPrice  *int
+	FamilyPrice  *int
+	VendorURL    string
+	CategoryID   *uuid.UUID
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
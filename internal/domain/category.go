@@ -0,0 +1,18 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Category groups subscriptions for reporting, e.g. "Streaming" or
+// "Dev tools". Names are unique regardless of case. TaxRate, in basis points,
+// applies to its subscriptions that set none; nil means untaxed.
+type Category struct {
+	ID        uuid.UUID
+	Name      string
+	TaxRate   *int
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
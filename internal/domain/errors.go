@@ -6,4 +6,6 @@ var (
 	ErrNotFound        = errors.New("not found")
 	ErrDuplicate       = errors.New("duplicate")
 	ErrInvalidArgument = errors.New("invalid argument")
+	ErrForbidden       = errors.New("forbidden")
+	ErrConflict        = errors.New("conflict")
 )
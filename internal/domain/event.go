@@ -0,0 +1,55 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	EventSubscriptionCreated     = "subscription.created"
+	EventSubscriptionUpdated     = "subscription.updated"
+	EventPriceChanged            = "subscription.price_changed"
+	EventSubscriptionCancelled   = "subscription.cancelled"
+	EventSubscriptionEnded       = "subscription.ended"
+	EventSubscriptionDeleted     = "subscription.deleted"
+	EventSubscriptionRestored    = "subscription.restored"
+	EventSubscriptionPaused      = "subscription.paused"
+	EventSubscriptionResumed     = "subscription.resumed"
+	EventSubscriptionReactivated = "subscription.reactivated"
+	EventBudgetExceeded          = "budget.exceeded"
+)
+
+// EventTypes lists every event type, e.g. to validate subscriptions to them.
+var EventTypes = []string{
+	EventSubscriptionCreated,
+	EventSubscriptionUpdated,
+	EventPriceChanged,
+	EventSubscriptionCancelled,
+	EventSubscriptionEnded,
+	EventSubscriptionDeleted,
+	EventSubscriptionRestored,
+	EventSubscriptionPaused,
+	EventSubscriptionResumed,
+	EventSubscriptionReactivated,
+	EventBudgetExceeded,
+}
+
+// Event is a domain event about a subscription. Budget events refer to the
+// subscription whose change exceeded the budget. Events are stored in the
+// outbox together with the change that caused them and published later.
+// DedupeKey, when set, guarantees the event is stored at most once. Cursor is
+// an opaque position in the event stream, set on events read back from it.
+// Attempts counts the failed attempts to publish the event so far.
+type Event struct {
+	ID             uuid.UUID
+	Type           string
+	SubscriptionID uuid.UUID
+	UserID         uuid.UUID
+	Payload        json.RawMessage
+	DedupeKey      string
+	Cursor         string
+	Attempts       int
+	CreatedAt      time.Time
+}
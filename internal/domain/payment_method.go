@@ -0,0 +1,34 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Payment method types.
+const (
+	PaymentMethodCard        = "card"
+	PaymentMethodBankAccount = "bank_account"
+	PaymentMethodWallet      = "wallet"
+	PaymentMethodOther       = "other"
+)
+
+// PaymentMethod is a card or account subscriptions are paid with. Last4 holds
+// the last four digits of the number, if known. Expiry is the first day of
+// the last month the method can be charged in; nil for methods that do not
+// expire.
+type PaymentMethod struct {
+	ID        uuid.UUID
+	Label     string
+	Type      string
+	Last4     string
+	Expiry    *time.Time
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// ExpiredBy reports whether the method can no longer be charged in month.
+func (p PaymentMethod) ExpiredBy(month time.Time) bool {
+	return p.Expiry != nil && p.Expiry.Before(month)
+}
@@ -0,0 +1,33 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Reminder kinds.
+const (
+	ReminderRenewal      = "renewal"
+	ReminderEnding       = "ending"
+	ReminderSpendAnomaly = "spend_anomaly"
+)
+
+// Reminder warns a user ahead of a charge or of the end of a subscription.
+// DueDate is the day of the charge, or the first day the subscription is no
+// longer billed. Channel names the notifier the reminder is sent through.
+//
+// A spend anomaly reminder concerns all of a user's subscriptions, so it has
+// no SubscriptionID. DueDate is the month, Price the spend of that month and
+// Average the trailing average it jumped from.
+type Reminder struct {
+	ID             uuid.UUID
+	Kind           string
+	SubscriptionID uuid.UUID
+	UserID         uuid.UUID
+	ServiceName    string
+	Price          int
+	Average        int
+	DueDate        time.Time
+	Channel        string
+}
@@ -6,13 +6,222 @@ import (
 	"github.com/google/uuid"
 )
 
+// Subscription statuses, see Subscription.StatusAt.
+const (
+	StatusActive           = "active"
+	StatusCancelledPending = "cancelled_pending"
+	StatusEnded            = "ended"
+	StatusPaused           = "paused"
+	StatusUpcoming         = "upcoming"
+)
+
+// Split rules of shared subscriptions, see Subscription.Split.
+const (
+	SplitEqual      = "equal"
+	SplitPercentage = "percentage"
+	SplitFixed      = "fixed"
+)
+
+// Discount kinds, see Discount.
+const (
+	DiscountPercentage = "percentage"
+	DiscountFixed      = "fixed"
+)
+
+// CancelReasons lists the accepted cancellation reason codes.
+var CancelReasons = []string{
+	"too_expensive",
+	"not_using",
+	"switched_service",
+	"missing_features",
+	"technical_issues",
+	"temporary",
+	"other",
+}
+
+// Subscription is a service a user pays for monthly. Category is the name of
+// the category referenced by CategoryID and PaymentMethod the label of the
+// method referenced by PaymentMethodID; both are only set on reads. Months up
+// to and including TrialEndDate (a free trial) and months within Pauses are
+// not charged. Price is the list price in effect now and Prices the whole
+// series, starting at StartDate; Discounts lower what is charged.
+// CancelReason and CancelledAt are set by a cancellation. A subscription with
+// Members is shared: UserID pays it and each charge is divided by SplitRule.
+// TaxRate, in basis points, overrides the rate of the category; TaxInclusive
+// tells whether prices include the tax or have it added on top. Unused is set
+// by the user for a subscription they no longer use.
 type Subscription struct {
-	ID          uuid.UUID
-	ServiceName string
-	Price       int
-	UserID      uuid.UUID
-	StartDate   time.Time
-	EndDate     *time.Time
-	CreatedAt   time.Time
-	UpdatedAt   time.Time
+	ID              uuid.UUID
+	ServiceName     string
+	Price           int
+	UserID          uuid.UUID
+	StartDate       time.Time
+	EndDate         *time.Time
+	TrialEndDate    *time.Time
+	CategoryID      *uuid.UUID
+	Category        string
+	PaymentMethodID *uuid.UUID
+	PaymentMethod   string
+	Tags            []string
+	Prices          []PricePeriod
+	Pauses          []PausePeriod
+	Discounts       []Discount
+	TaxRate         *int
+	TaxInclusive    bool
+	Unused          bool
+	SplitRule       string
+	Members         []SubscriptionMember
+	CancelReason    string
+	CancelledAt     *time.Time
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+	DeletedAt       *time.Time
+}
+
+// FirstChargeDate is the first month the subscription is charged for.
+func (s Subscription) FirstChargeDate() time.Time {
+	if s.TrialEndDate != nil {
+		return s.TrialEndDate.AddDate(0, 1, 0)
+	}
+	return s.StartDate
+}
+
+// StatusAt returns the status of the subscription in month: ended once its
+// last month has passed, upcoming before its first month, paused within a
+// pause, cancelled_pending while the end date of a cancellation lies ahead and
+// active otherwise, including a fixed term that was never cancelled.
+func (s Subscription) StatusAt(month time.Time) string {
+	switch {
+	case s.EndDate != nil && s.EndDate.Before(month):
+		return StatusEnded
+	case s.StartDate.After(month):
+		return StatusUpcoming
+	case s.PausedIn(month):
+		return StatusPaused
+	case s.EndDate != nil && s.CancelledAt != nil:
+		return StatusCancelledPending
+	default:
+		return StatusActive
+	}
+}
+
+// PausedIn reports whether billing is paused in month.
+func (s Subscription) PausedIn(month time.Time) bool {
+	for _, p := range s.Pauses {
+		if p.Covers(month) {
+			return true
+		}
+	}
+	return false
+}
+
+// Discounted returns what month is charged given its list price, i.e. price
+// less the discount covering month, if any.
+func (s Subscription) Discounted(month time.Time, price int) int {
+	for _, d := range s.Discounts {
+		if d.Covers(month) {
+			return d.Apply(price)
+		}
+	}
+	return price
+}
+
+// Users returns the owner followed by the members.
+func (s Subscription) Users() []uuid.UUID {
+	users := []uuid.UUID{s.UserID}
+	for _, m := range s.Members {
+		users = append(users, m.UserID)
+	}
+	return users
+}
+
+// Split divides amount between the owner and the members and returns the
+// owner's share first, then one per member in order. The shares always add up
+// to amount: rounding remainders and unassigned percentages stay with the
+// owner, and fixed amounts exceeding amount are scaled down proportionally.
+func (s Subscription) Split(amount int) []Share {
+	shares := make([]Share, len(s.Members)+1)
+	shares[0] = Share{UserID: s.UserID, Amount: amount}
+	fixed := 0
+	for _, m := range s.Members {
+		fixed += m.Share
+	}
+	for i, m := range s.Members {
+		var part int
+		switch s.SplitRule {
+		case SplitEqual:
+			part = amount / (len(s.Members) + 1)
+		case SplitPercentage:
+			part = int(int64(amount) * int64(m.Share) / 100)
+		case SplitFixed:
+			part = m.Share
+			if fixed > amount {
+				part = int(int64(amount) * int64(m.Share) / int64(fixed))
+			}
+		}
+		shares[i+1] = Share{UserID: m.UserID, Amount: part}
+		shares[0].Amount -= part
+	}
+	return shares
+}
+
+// SubscriptionMember is a user sharing a subscription with its owner. Share is
+// a percentage under SplitPercentage, a monthly amount under SplitFixed and
+// unused under SplitEqual.
+type SubscriptionMember struct {
+	UserID uuid.UUID
+	Share  int
+}
+
+// Share is the part of a charge that falls on one user.
+type Share struct {
+	UserID uuid.UUID
+	Amount int
+}
+
+// PausePeriod is a break in billing from From through Until, both first days
+// of months. Until is nil while the pause runs until resumed.
+type PausePeriod struct {
+	From  time.Time
+	Until *time.Time
+}
+
+// Covers reports whether month falls within the pause.
+func (p PausePeriod) Covers(month time.Time) bool {
+	return !month.Before(p.From) && (p.Until == nil || !month.After(*p.Until))
+}
+
+// Discount lowers the price charged from From through Until, both first days
+// of months, by Value percent or by the amount Value. Until is nil for a
+// discount without end. The discounts of a subscription do not overlap.
+type Discount struct {
+	ID    uuid.UUID
+	Kind  string
+	Value int
+	From  time.Time
+	Until *time.Time
+}
+
+// Covers reports whether the discount applies in month.
+func (d Discount) Covers(month time.Time) bool {
+	return !month.Before(d.From) && (d.Until == nil || !month.After(*d.Until))
+}
+
+// Apply returns price less the discount, never below zero. Percentage
+// discounts are rounded down.
+func (d Discount) Apply(price int) int {
+	switch d.Kind {
+	case DiscountPercentage:
+		return price - int(int64(price)*int64(d.Value)/100)
+	case DiscountFixed:
+		return max(price-d.Value, 0)
+	}
+	return price
+}
+
+// PricePeriod is a price that applies from EffectiveFrom (first day of a
+// month) until the next period starts.
+type PricePeriod struct {
+	EffectiveFrom time.Time
+	Price         int
 }
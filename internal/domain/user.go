@@ -0,0 +1,20 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// User owns subscriptions and budgets. Currency is an ISO 4217 code and
+// Timezone an IANA zone name; users registered implicitly by a subscription
+// start with an empty profile.
+type User struct {
+	ID        uuid.UUID
+	Name      string
+	Email     string
+	Currency  string
+	Timezone  string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
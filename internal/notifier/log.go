@@ -0,0 +1,30 @@
+package notifier
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/always-tired/crud-subscriptions/internal/domain"
+)
+
+// Log writes reminders to the application log. It is useful in development
+// and as a trace next to the real channels.
+type Log struct {
+	log *slog.Logger
+}
+
+func NewLog(log *slog.Logger) *Log {
+	return &Log{log: log}
+}
+
+func (n *Log) Name() string { return "log" }
+
+func (n *Log) Notify(_ context.Context, r domain.Reminder) error {
+	n.log.Info("reminder",
+		"kind", r.Kind,
+		"subscription_id", r.SubscriptionID,
+		"user_id", r.UserID,
+		"message", subject(r),
+	)
+	return nil
+}
@@ -0,0 +1,41 @@
+package notifier
+
+import (
+	"fmt"
+
+	"github.com/always-tired/crud-subscriptions/internal/domain"
+)
+
+const (
+	dueDateLayout = "2006-01-02"
+	monthLayout   = "01-2006"
+)
+
+func subject(r domain.Reminder) string {
+	switch r.Kind {
+	case domain.ReminderEnding:
+		return fmt.Sprintf("%s ends on %s", r.ServiceName, r.DueDate.Format(dueDateLayout))
+	case domain.ReminderSpendAnomaly:
+		return fmt.Sprintf("Unusual spend in %s", r.DueDate.Format(monthLayout))
+	}
+	return fmt.Sprintf("%s renews on %s", r.ServiceName, r.DueDate.Format(dueDateLayout))
+}
+
+func body(r domain.Reminder) string {
+	switch r.Kind {
+	case domain.ReminderEnding:
+		return fmt.Sprintf(
+			"Your %s subscription ends on %s. If you want to keep it, renew it before then.\n",
+			r.ServiceName, r.DueDate.Format(dueDateLayout),
+		)
+	case domain.ReminderSpendAnomaly:
+		return fmt.Sprintf(
+			"Your subscriptions charge %d in %s, up from %d a month on average. Check for price increases or subscriptions you no longer need.\n",
+			r.Price, r.DueDate.Format(monthLayout), r.Average,
+		)
+	}
+	return fmt.Sprintf(
+		"Your %s subscription will be charged %d on %s. Cancel it before then if you no longer need it.\n",
+		r.ServiceName, r.Price, r.DueDate.Format(dueDateLayout),
+	)
+}
@@ -0,0 +1,163 @@
+package notifier
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"io"
+	"mime"
+	"net"
+	"net/mail"
+	"net/textproto"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/always-tired/crud-subscriptions/internal/domain"
+)
+
+// smtpSession is what the fake server received over one connection.
+type smtpSession struct {
+	auth string
+	from string
+	rcpt []string
+	data []byte
+}
+
+// fakeSMTP accepts a single connection on a local port and speaks just enough
+// SMTP for net/smtp: EHLO advertising AUTH PLAIN, MAIL, RCPT, DATA and QUIT.
+func fakeSMTP(t *testing.T) (string, <-chan smtpSession) {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	done := make(chan smtpSession, 1)
+	go func() {
+		c, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer c.Close()
+		_ = c.SetDeadline(time.Now().Add(5 * time.Second))
+
+		conn := textproto.NewConn(c)
+		var sess smtpSession
+		reply := func(s string) { _ = conn.PrintfLine("%s", s) }
+		reply("220 localhost ESMTP")
+		for {
+			line, err := conn.ReadLine()
+			if err != nil {
+				return
+			}
+			cmd, arg, _ := strings.Cut(line, " ")
+			switch strings.ToUpper(cmd) {
+			case "EHLO", "HELO":
+				reply("250-localhost")
+				reply("250 AUTH PLAIN")
+			case "AUTH":
+				sess.auth = strings.TrimPrefix(arg, "PLAIN ")
+				reply("235 2.7.0 Authentication successful")
+			case "MAIL":
+				sess.from = arg
+				reply("250 OK")
+			case "RCPT":
+				sess.rcpt = append(sess.rcpt, arg)
+				reply("250 OK")
+			case "DATA":
+				reply("354 End data with <CR><LF>.<CR><LF>")
+				if sess.data, err = conn.ReadDotBytes(); err != nil {
+					return
+				}
+				reply("250 OK")
+			case "QUIT":
+				reply("221 Bye")
+				done <- sess
+				return
+			default:
+				reply("502 Command not implemented")
+			}
+		}
+	}()
+	return ln.Addr().String(), done
+}
+
+func TestSMTPNotify(t *testing.T) {
+	addr, done := fakeSMTP(t)
+	n, err := NewSMTP(addr, "user", "pass", "reminders@example.com", []string{"a@example.com", "b@example.com"}, 5*time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := domain.Reminder{
+		ID:          uuid.New(),
+		Kind:        domain.ReminderRenewal,
+		ServiceName: "Яндекс Плюс",
+		Price:       400,
+		DueDate:     time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC),
+	}
+	if err := n.Notify(context.Background(), r); err != nil {
+		t.Fatalf("Notify: %v", err)
+	}
+
+	var sess smtpSession
+	select {
+	case sess = <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("server did not receive QUIT")
+	}
+
+	auth, err := base64.StdEncoding.DecodeString(sess.auth)
+	if err != nil || string(auth) != "\x00user\x00pass" {
+		t.Errorf("AUTH PLAIN = %q", auth)
+	}
+	if sess.from != "FROM:<reminders@example.com>" {
+		t.Errorf("MAIL %s", sess.from)
+	}
+	if want := []string{"TO:<a@example.com>", "TO:<b@example.com>"}; strings.Join(sess.rcpt, ",") != strings.Join(want, ",") {
+		t.Errorf("RCPT %v, want %v", sess.rcpt, want)
+	}
+
+	msg, err := mail.ReadMessage(bufio.NewReader(bytes.NewReader(sess.data)))
+	if err != nil {
+		t.Fatalf("parse message: %v", err)
+	}
+	subj, err := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+	if err != nil {
+		t.Fatalf("decode subject: %v", err)
+	}
+	headers := map[string]string{
+		"From":         "reminders@example.com",
+		"To":           "a@example.com, b@example.com",
+		"Subject":      "Яндекс Плюс renews on 2025-07-01",
+		"Message-ID":   "<" + r.ID.String() + "@127.0.0.1>",
+		"Content-Type": "text/plain; charset=utf-8",
+	}
+	for k, want := range headers {
+		got := msg.Header.Get(k)
+		if k == "Subject" {
+			got = subj
+		}
+		if got != want {
+			t.Errorf("%s = %q, want %q", k, got, want)
+		}
+	}
+	if _, err := msg.Header.Date(); err != nil {
+		t.Errorf("Date: %v", err)
+	}
+
+	b, err := io.ReadAll(msg.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantBody := "Your Яндекс Плюс subscription will be charged 400 on 2025-07-01. Cancel it before then if you no longer need it.\n"
+	if got := string(b); got != wantBody {
+		t.Errorf("body = %q, want %q", got, wantBody)
+	}
+}
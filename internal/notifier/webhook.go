@@ -0,0 +1,87 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/always-tired/crud-subscriptions/internal/domain"
+	"github.com/always-tired/crud-subscriptions/internal/publisher"
+)
+
+// Webhook POSTs reminders as JSON to a fixed URL. With a secret the request is
+// signed like integrator webhooks, see publisher.SignatureHeader.
+type Webhook struct {
+	url    string
+	secret string
+	client *http.Client
+}
+
+func NewWebhook(url, secret string, timeout time.Duration) *Webhook {
+	return &Webhook{url: url, secret: secret, client: &http.Client{Timeout: timeout}}
+}
+
+// webhookPayload omits subscription_id for reminders that concern no single
+// subscription; average is only set for spend anomalies.
+type webhookPayload struct {
+	ID             uuid.UUID  `json:"id"`
+	Kind           string     `json:"kind"`
+	SubscriptionID *uuid.UUID `json:"subscription_id,omitempty"`
+	UserID         uuid.UUID  `json:"user_id"`
+	ServiceName    string     `json:"service_name,omitempty"`
+	Price          int        `json:"price"`
+	Average        *int       `json:"average,omitempty"`
+	DueDate        string     `json:"due_date"`
+	Message        string     `json:"message"`
+}
+
+func (n *Webhook) Name() string { return "webhook" }
+
+func (n *Webhook) Notify(ctx context.Context, r domain.Reminder) error {
+	payload := webhookPayload{
+		ID:          r.ID,
+		Kind:        r.Kind,
+		UserID:      r.UserID,
+		ServiceName: r.ServiceName,
+		Price:       r.Price,
+		DueDate:     r.DueDate.Format(dueDateLayout),
+		Message:     subject(r),
+	}
+	if r.SubscriptionID != uuid.Nil {
+		payload.SubscriptionID = &r.SubscriptionID
+	}
+	if r.Kind == domain.ReminderSpendAnomaly {
+		payload.Average = &r.Average
+	}
+	b, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("marshal reminder: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, bytes.NewReader(b))
+	if err != nil {
+		return fmt.Errorf("build reminder request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if n.secret != "" {
+		req.Header.Set(publisher.SignatureHeader, publisher.Sign(n.secret, time.Now(), b))
+	}
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("send reminder: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("reminder webhook responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,30 @@
+package publisher
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/always-tired/crud-subscriptions/internal/domain"
+)
+
+// envelope is the wire format of an event shared by all publishers.
+type envelope struct {
+	ID             string          `json:"id"`
+	Type           string          `json:"type"`
+	SubscriptionID string          `json:"subscription_id"`
+	UserID         string          `json:"user_id"`
+	CreatedAt      string          `json:"created_at"`
+	Data           json.RawMessage `json:"data"`
+}
+
+// Marshal encodes an event in the wire format used by all publishers.
+func Marshal(e domain.Event) ([]byte, error) {
+	return json.Marshal(envelope{
+		ID:             e.ID.String(),
+		Type:           e.Type,
+		SubscriptionID: e.SubscriptionID.String(),
+		UserID:         e.UserID.String(),
+		CreatedAt:      e.CreatedAt.UTC().Format(time.RFC3339),
+		Data:           e.Payload,
+	})
+}
@@ -0,0 +1,21 @@
+package publisher
+
+import (
+	"context"
+
+	"github.com/always-tired/crud-subscriptions/internal/domain"
+	"github.com/always-tired/crud-subscriptions/internal/usecase"
+)
+
+// Multi publishes every event to all publishers and fails if any of them
+// fails. A retried event may therefore reach some sinks more than once.
+type Multi []usecase.EventPublisher
+
+func (m Multi) Publish(ctx context.Context, e domain.Event) error {
+	for _, p := range m {
+		if err := p.Publish(ctx, e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package publisher
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SignatureHeader carries "t=<unix seconds>,v1=<hex HMAC-SHA256>" where the
+// MAC is computed with the webhook secret over "<t>.<body>".
+const SignatureHeader = "X-Webhook-Signature"
+
+var ErrInvalidSignature = errors.New("invalid webhook signature")
+
+// Sign returns the SignatureHeader value for body sent at ts.
+func Sign(secret string, ts time.Time, body []byte) string {
+	t := strconv.FormatInt(ts.Unix(), 10)
+	return "t=" + t + ",v1=" + computeMAC(secret, t, body)
+}
+
+// VerifySignature checks a SignatureHeader value against body. Signatures
+// older than tolerance are rejected to limit replays; a zero tolerance
+// disables that check. Receivers can use it as-is.
+func VerifySignature(secret, header string, body []byte, tolerance time.Duration, now time.Time) error {
+	var t, mac string
+	for _, part := range strings.Split(header, ",") {
+		k, v, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch k {
+		case "t":
+			t = v
+		case "v1":
+			mac = v
+		}
+	}
+	if t == "" || mac == "" {
+		return ErrInvalidSignature
+	}
+
+	if tolerance > 0 {
+		sec, err := strconv.ParseInt(t, 10, 64)
+		if err != nil || now.Sub(time.Unix(sec, 0)).Abs() > tolerance {
+			return ErrInvalidSignature
+		}
+	}
+	if !hmac.Equal([]byte(mac), []byte(computeMAC(secret, t, body))) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+func computeMAC(secret, t string, body []byte) string {
+	h := hmac.New(sha256.New, []byte(secret))
+	h.Write([]byte(t))
+	h.Write([]byte("."))
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
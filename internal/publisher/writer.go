@@ -0,0 +1,50 @@
+package publisher
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/always-tired/crud-subscriptions/internal/domain"
+)
+
+// WriterPublisher writes every event as one JSON line to an io.Writer, such as
+// stdout or an append-only file.
+type WriterPublisher struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func NewWriterPublisher(w io.Writer) *WriterPublisher {
+	return &WriterPublisher{w: w}
+}
+
+// OpenFile returns a publisher appending to the file at path, or writing to
+// stdout when path is "stdout". The caller closes the returned closer.
+func OpenFile(path string) (*WriterPublisher, io.Closer, error) {
+	if path == "stdout" {
+		return NewWriterPublisher(os.Stdout), io.NopCloser(nil), nil
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, nil, fmt.Errorf("open events file: %w", err)
+	}
+	return NewWriterPublisher(f), f, nil
+}
+
+func (p *WriterPublisher) Publish(_ context.Context, e domain.Event) error {
+	b, err := Marshal(e)
+	if err != nil {
+		return err
+	}
+	b = append(b, '\n')
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if _, err := p.w.Write(b); err != nil {
+		return fmt.Errorf("write event: %w", err)
+	}
+	return nil
+}
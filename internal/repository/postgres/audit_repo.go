@@ -0,0 +1,88 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/always-tired/crud-subscriptions/internal/domain"
+	"github.com/always-tired/crud-subscriptions/internal/usecase"
+)
+
+type AuditRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewAuditRepository(pool *pgxpool.Pool) *AuditRepository {
+	return &AuditRepository{pool: pool}
+}
+
+func (r *AuditRepository) AppendAudit(ctx context.Context, e domain.AuditEntry) error {
+	query := `
+		INSERT INTO audit_log (id, subscription_id, action, actor, request_id, before, after)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+
+	if _, err := conn(ctx, r.pool).Exec(ctx, query,
+		e.ID, e.SubscriptionID, e.Action, e.Actor, e.RequestID, e.Before, e.After,
+	); err != nil {
+		return fmt.Errorf("repo AppendAudit: %w", err)
+	}
+	return nil
+}
+
+func (r *AuditRepository) ListAudit(ctx context.Context, filter usecase.AuditFilter) ([]domain.AuditEntry, error) {
+	query := `
+		SELECT id, subscription_id, action, actor, request_id, before, after, created_at
+		FROM audit_log
+		WHERE ($1::uuid IS NULL OR subscription_id = $1)
+		  AND ($2::text IS NULL OR actor = $2)
+		  AND ($3::timestamptz IS NULL OR created_at >= $3)
+		  AND ($4::timestamptz IS NULL OR created_at <= $4)
+		ORDER BY created_at, id
+		LIMIT $5 OFFSET $6
+	`
+
+	limit := filter.Limit
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+	offset := filter.Offset
+	if offset < 0 {
+		offset = 0
+	}
+
+	rows, err := conn(ctx, r.pool).Query(ctx, query,
+		filter.SubscriptionID, filter.Actor, filter.From, filter.To, limit, offset,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("repo ListAudit: %w", err)
+	}
+	defer rows.Close()
+
+	res := make([]domain.AuditEntry, 0)
+	for rows.Next() {
+		var e domain.AuditEntry
+		var before, after []byte
+		if err := rows.Scan(
+			&e.ID,
+			&e.SubscriptionID,
+			&e.Action,
+			&e.Actor,
+			&e.RequestID,
+			&before,
+			&after,
+			&e.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("repo ListAudit: %w", err)
+		}
+		e.Before = before
+		e.After = after
+		res = append(res, e)
+	}
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("repo ListAudit: %w", rows.Err())
+	}
+	return res, nil
+}
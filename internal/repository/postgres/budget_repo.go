@@ -0,0 +1,122 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/always-tired/crud-subscriptions/internal/domain"
+)
+
+const budgetColumns = `b.id, b.user_id, b.category_id, b.monthly_limit, b.created_at, b.updated_at`
+
+func scanBudget(row pgx.Row) (domain.Budget, error) {
+	var b domain.Budget
+	err := row.Scan(&b.ID, &b.UserID, &b.CategoryID, &b.MonthlyLimit, &b.CreatedAt, &b.UpdatedAt)
+	return b, err
+}
+
+type BudgetRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewBudgetRepository(pool *pgxpool.Pool) *BudgetRepository {
+	return &BudgetRepository{pool: pool}
+}
+
+func (r *BudgetRepository) CreateBudget(ctx context.Context, b domain.Budget) (domain.Budget, error) {
+	query := `
+		INSERT INTO budgets AS b (id, user_id, category_id, monthly_limit, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING ` + budgetColumns
+
+	created, err := scanBudget(conn(ctx, r.pool).QueryRow(ctx, query,
+		b.ID, b.UserID, b.CategoryID, b.MonthlyLimit, b.CreatedAt, b.UpdatedAt,
+	))
+	if err != nil {
+		if err := mapWriteError(err); err != nil {
+			return domain.Budget{}, err
+		}
+		return domain.Budget{}, fmt.Errorf("repo CreateBudget: %w", err)
+	}
+	return created, nil
+}
+
+func (r *BudgetRepository) GetBudget(ctx context.Context, id uuid.UUID) (domain.Budget, error) {
+	query := `SELECT ` + budgetColumns + ` FROM budgets b WHERE b.id = $1`
+
+	b, err := scanBudget(conn(ctx, r.pool).QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.Budget{}, domain.ErrNotFound
+		}
+		return domain.Budget{}, fmt.Errorf("repo GetBudget: %w", err)
+	}
+	return b, nil
+}
+
+func (r *BudgetRepository) UpdateBudget(ctx context.Context, b domain.Budget) (domain.Budget, error) {
+	query := `
+		UPDATE budgets AS b
+		SET user_id = $2,
+			category_id = $3,
+			monthly_limit = $4,
+			updated_at = $5
+		WHERE b.id = $1
+		RETURNING ` + budgetColumns
+
+	updated, err := scanBudget(conn(ctx, r.pool).QueryRow(ctx, query, b.ID, b.UserID, b.CategoryID, b.MonthlyLimit, b.UpdatedAt))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.Budget{}, domain.ErrNotFound
+		}
+		if err := mapWriteError(err); err != nil {
+			return domain.Budget{}, err
+		}
+		return domain.Budget{}, fmt.Errorf("repo UpdateBudget: %w", err)
+	}
+	return updated, nil
+}
+
+func (r *BudgetRepository) DeleteBudget(ctx context.Context, id uuid.UUID) error {
+	cmd, err := conn(ctx, r.pool).Exec(ctx, `DELETE FROM budgets WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("repo DeleteBudget: %w", err)
+	}
+	if cmd.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+func (r *BudgetRepository) ListBudgets(ctx context.Context, userID *uuid.UUID) ([]domain.Budget, error) {
+	query := `
+		SELECT ` + budgetColumns + `
+		FROM budgets b
+		WHERE ($1::uuid IS NULL OR b.user_id = $1)
+		ORDER BY b.category_id NULLS FIRST, b.created_at
+	`
+
+	rows, err := conn(ctx, r.pool).Query(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("repo ListBudgets: %w", err)
+	}
+	defer rows.Close()
+
+	res := make([]domain.Budget, 0)
+	for rows.Next() {
+		b, err := scanBudget(rows)
+		if err != nil {
+			return nil, fmt.Errorf("repo ListBudgets: %w", err)
+		}
+		res = append(res, b)
+	}
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("repo ListBudgets: %w", rows.Err())
+	}
+	return res, nil
+}
@@ -0,0 +1,59 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/always-tired/crud-subscriptions/internal/domain"
+)
+
+type CalendarTokenRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewCalendarTokenRepository(pool *pgxpool.Pool) *CalendarTokenRepository {
+	return &CalendarTokenRepository{pool: pool}
+}
+
+func (r *CalendarTokenRepository) SaveCalendarToken(ctx context.Context, userID uuid.UUID, tokenHash string) error {
+	query := `
+		INSERT INTO calendar_tokens (user_id, token_hash)
+		VALUES ($1, $2)
+		ON CONFLICT (user_id) DO UPDATE
+		SET token_hash = EXCLUDED.token_hash,
+			created_at = NOW()
+	`
+
+	if _, err := conn(ctx, r.pool).Exec(ctx, query, userID, tokenHash); err != nil {
+		return fmt.Errorf("repo SaveCalendarToken: %w", err)
+	}
+	return nil
+}
+
+func (r *CalendarTokenRepository) GetCalendarTokenHash(ctx context.Context, userID uuid.UUID) (string, error) {
+	var hash string
+	err := conn(ctx, r.pool).QueryRow(ctx, `SELECT token_hash FROM calendar_tokens WHERE user_id = $1`, userID).Scan(&hash)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", domain.ErrNotFound
+		}
+		return "", fmt.Errorf("repo GetCalendarTokenHash: %w", err)
+	}
+	return hash, nil
+}
+
+func (r *CalendarTokenRepository) DeleteCalendarToken(ctx context.Context, userID uuid.UUID) error {
+	cmd, err := conn(ctx, r.pool).Exec(ctx, `DELETE FROM calendar_tokens WHERE user_id = $1`, userID)
+	if err != nil {
+		return fmt.Errorf("repo DeleteCalendarToken: %w", err)
+	}
+	if cmd.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/always-tired/crud-subscriptions/internal/domain"
+)
+
+const categoryColumns = `c.id, c.name, c.tax_rate, c.created_at, c.updated_at`
+
+func scanCategory(row pgx.Row) (domain.Category, error) {
+	var c domain.Category
+	err := row.Scan(&c.ID, &c.Name, &c.TaxRate, &c.CreatedAt, &c.UpdatedAt)
+	return c, err
+}
+
+type CategoryRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewCategoryRepository(pool *pgxpool.Pool) *CategoryRepository {
+	return &CategoryRepository{pool: pool}
+}
+
+func (r *CategoryRepository) CreateCategory(ctx context.Context, c domain.Category) (domain.Category, error) {
+	query := `
+		INSERT INTO categories AS c (id, name, tax_rate)
+		VALUES ($1, $2, $3)
+		RETURNING ` + categoryColumns
+
+	created, err := scanCategory(conn(ctx, r.pool).QueryRow(ctx, query, c.ID, c.Name, c.TaxRate))
+	if err != nil {
+		if err := mapWriteError(err); err != nil {
+			return domain.Category{}, err
+		}
+		return domain.Category{}, fmt.Errorf("repo CreateCategory: %w", err)
+	}
+	return created, nil
+}
+
+func (r *CategoryRepository) GetCategory(ctx context.Context, id uuid.UUID) (domain.Category, error) {
+	query := `SELECT ` + categoryColumns + ` FROM categories c WHERE c.id = $1`
+
+	c, err := scanCategory(conn(ctx, r.pool).QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.Category{}, domain.ErrNotFound
+		}
+		return domain.Category{}, fmt.Errorf("repo GetCategory: %w", err)
+	}
+	return c, nil
+}
+
+func (r *CategoryRepository) ListCategories(ctx context.Context) ([]domain.Category, error) {
+	query := `SELECT ` + categoryColumns + ` FROM categories c ORDER BY lower(c.name)`
+
+	rows, err := conn(ctx, r.pool).Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("repo ListCategories: %w", err)
+	}
+	defer rows.Close()
+
+	res := make([]domain.Category, 0)
+	for rows.Next() {
+		c, err := scanCategory(rows)
+		if err != nil {
+			return nil, fmt.Errorf("repo ListCategories: %w", err)
+		}
+		res = append(res, c)
+	}
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("repo ListCategories: %w", rows.Err())
+	}
+	return res, nil
+}
+
+func (r *CategoryRepository) UpdateCategory(ctx context.Context, c domain.Category) (domain.Category, error) {
+	query := `
+		UPDATE categories AS c
+		SET name = $2,
+			tax_rate = $3,
+			updated_at = NOW()
+		WHERE c.id = $1
+		RETURNING ` + categoryColumns
+
+	updated, err := scanCategory(conn(ctx, r.pool).QueryRow(ctx, query, c.ID, c.Name, c.TaxRate))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.Category{}, domain.ErrNotFound
+		}
+		if err := mapWriteError(err); err != nil {
+			return domain.Category{}, err
+		}
+		return domain.Category{}, fmt.Errorf("repo UpdateCategory: %w", err)
+	}
+	return updated, nil
+}
+
+func (r *CategoryRepository) DeleteCategory(ctx context.Context, id uuid.UUID) error {
+	cmd, err := conn(ctx, r.pool).Exec(ctx, `DELETE FROM categories WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("repo DeleteCategory: %w", err)
+	}
+	if cmd.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package postgres
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// EventsChannel is notified by a trigger on every committed outbox insert with
+// the user id as payload.
+const EventsChannel = "subscription_events"
+
+// Notifier receives user ids of committed events.
+type Notifier interface {
+	Notify(userID uuid.UUID)
+	NotifyAll()
+}
+
+// Listener forwards Postgres notifications on EventsChannel to a Notifier so
+// that every API replica learns about changes made through any other one.
+type Listener struct {
+	pool     *pgxpool.Pool
+	notifier Notifier
+	log      *slog.Logger
+}
+
+func NewListener(pool *pgxpool.Pool, notifier Notifier, log *slog.Logger) *Listener {
+	return &Listener{pool: pool, notifier: notifier, log: log}
+}
+
+// Run listens until ctx is done, reconnecting after errors. Subscribers are
+// woken up after every reconnect because notifications may have been missed.
+func (l *Listener) Run(ctx context.Context) {
+	for {
+		err := l.listen(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		l.log.Warn("events listener", "error", err)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(time.Second):
+		}
+	}
+}
+
+func (l *Listener) listen(ctx context.Context) error {
+	c, err := l.pool.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer c.Release()
+
+	if _, err := c.Exec(ctx, "LISTEN "+EventsChannel); err != nil {
+		return err
+	}
+	l.notifier.NotifyAll()
+
+	for {
+		n, err := c.Conn().WaitForNotification(ctx)
+		if err != nil {
+			// the connection may still be listening, don't hand it back
+			_ = c.Conn().Close(context.Background())
+			return err
+		}
+		if id, err := uuid.Parse(n.Payload); err == nil {
+			l.notifier.Notify(id)
+		}
+	}
+}
@@ -0,0 +1,221 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/always-tired/crud-subscriptions/internal/domain"
+)
+
+type OutboxRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewOutboxRepository(pool *pgxpool.Pool) *OutboxRepository {
+	return &OutboxRepository{pool: pool}
+}
+
+func (r *OutboxRepository) AddEvents(ctx context.Context, events []domain.Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	query := `
+		INSERT INTO outbox (id, event_type, subscription_id, user_id, payload, dedupe_key)
+		VALUES ($1, $2, $3, $4, $5, NULLIF($6, ''))
+		ON CONFLICT (dedupe_key) DO NOTHING
+	`
+
+	batch := &pgx.Batch{}
+	for _, e := range events {
+		batch.Queue(query, e.ID, e.Type, e.SubscriptionID, e.UserID, e.Payload, e.DedupeKey)
+	}
+	if err := conn(ctx, r.pool).SendBatch(ctx, batch).Close(); err != nil {
+		return fmt.Errorf("repo AddEvents: %w", err)
+	}
+	return nil
+}
+
+func (r *OutboxRepository) FetchUnpublished(ctx context.Context, now time.Time, limit int) ([]domain.Event, error) {
+	query := `
+		SELECT id, event_type, subscription_id, user_id, payload, COALESCE(dedupe_key, ''), attempts, created_at
+		FROM outbox
+		WHERE published_at IS NULL
+		  AND parked_at IS NULL
+		  AND (locked_until IS NULL OR locked_until <= $1)
+		ORDER BY created_at, id
+		LIMIT $2
+		FOR UPDATE SKIP LOCKED
+	`
+
+	rows, err := conn(ctx, r.pool).Query(ctx, query, now, limit)
+	if err != nil {
+		return nil, fmt.Errorf("repo FetchUnpublished: %w", err)
+	}
+	defer rows.Close()
+
+	res := make([]domain.Event, 0)
+	for rows.Next() {
+		var e domain.Event
+		var payload []byte
+		if err := rows.Scan(
+			&e.ID,
+			&e.Type,
+			&e.SubscriptionID,
+			&e.UserID,
+			&payload,
+			&e.DedupeKey,
+			&e.Attempts,
+			&e.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("repo FetchUnpublished: %w", err)
+		}
+		e.Payload = payload
+		res = append(res, e)
+	}
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("repo FetchUnpublished: %w", rows.Err())
+	}
+	return res, nil
+}
+
+func (r *OutboxRepository) LeaseEvents(ctx context.Context, ids []uuid.UUID, until time.Time) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	if _, err := conn(ctx, r.pool).Exec(ctx,
+		`UPDATE outbox SET locked_until = $2 WHERE id = ANY($1)`, ids, until,
+	); err != nil {
+		return fmt.Errorf("repo LeaseEvents: %w", err)
+	}
+	return nil
+}
+
+func (r *OutboxRepository) ReleaseEvents(ctx context.Context, ids []uuid.UUID) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	if _, err := conn(ctx, r.pool).Exec(ctx,
+		`UPDATE outbox SET locked_until = NULL WHERE id = ANY($1)`, ids,
+	); err != nil {
+		return fmt.Errorf("repo ReleaseEvents: %w", err)
+	}
+	return nil
+}
+
+func (r *OutboxRepository) MarkPublished(ctx context.Context, id uuid.UUID) error {
+	query := `
+		UPDATE outbox
+		SET published_at = NOW(),
+			attempts = attempts + 1,
+			last_error = NULL,
+			locked_until = NULL
+		WHERE id = $1
+	`
+
+	if _, err := conn(ctx, r.pool).Exec(ctx, query, id); err != nil {
+		return fmt.Errorf("repo MarkPublished: %w", err)
+	}
+	return nil
+}
+
+func (r *OutboxRepository) MarkFailed(ctx context.Context, id uuid.UUID, reason string, park bool) error {
+	query := `
+		UPDATE outbox
+		SET attempts = attempts + 1,
+			last_error = $2,
+			locked_until = NULL,
+			parked_at = CASE WHEN $3 THEN NOW() END
+		WHERE id = $1
+	`
+
+	if _, err := conn(ctx, r.pool).Exec(ctx, query, id, reason, park); err != nil {
+		return fmt.Errorf("repo MarkFailed: %w", err)
+	}
+	return nil
+}
+
+// Stream cursors have the form "<xid>.<seq>". Events are read in (xid, seq)
+// order and only from transactions older than every running one, so an event
+// committed late can never appear behind a cursor that was already handed out.
+
+// HeadCursor returns a cursor positioned after every event visible now.
+func (r *OutboxRepository) HeadCursor(ctx context.Context) (string, error) {
+	var xmin string
+	if err := conn(ctx, r.pool).QueryRow(ctx,
+		`SELECT pg_snapshot_xmin(pg_current_snapshot())::text`,
+	).Scan(&xmin); err != nil {
+		return "", fmt.Errorf("repo HeadCursor: %w", err)
+	}
+	return xmin + ".0", nil
+}
+
+func (r *OutboxRepository) ListEventsAfter(ctx context.Context, cursor string, userID *uuid.UUID, limit int) ([]domain.Event, error) {
+	xid, seq, err := parseCursor(cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `
+		SELECT id, event_type, subscription_id, user_id, payload, COALESCE(dedupe_key, ''), created_at,
+			xid::text || '.' || seq::text
+		FROM outbox
+		WHERE (xid, seq) > ($1::text::xid8, $2::bigint)
+		  AND xid < pg_snapshot_xmin(pg_current_snapshot())
+		  AND ($3::uuid IS NULL OR user_id = $3)
+		ORDER BY xid, seq
+		LIMIT $4
+	`
+
+	rows, err := conn(ctx, r.pool).Query(ctx, query, xid, seq, userID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("repo ListEventsAfter: %w", err)
+	}
+	defer rows.Close()
+
+	res := make([]domain.Event, 0)
+	for rows.Next() {
+		var e domain.Event
+		var payload []byte
+		if err := rows.Scan(
+			&e.ID,
+			&e.Type,
+			&e.SubscriptionID,
+			&e.UserID,
+			&payload,
+			&e.DedupeKey,
+			&e.CreatedAt,
+			&e.Cursor,
+		); err != nil {
+			return nil, fmt.Errorf("repo ListEventsAfter: %w", err)
+		}
+		e.Payload = payload
+		res = append(res, e)
+	}
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("repo ListEventsAfter: %w", rows.Err())
+	}
+	return res, nil
+}
+
+func parseCursor(cursor string) (string, int64, error) {
+	x, s, ok := strings.Cut(cursor, ".")
+	if !ok {
+		return "", 0, fmt.Errorf("%w: invalid event cursor", domain.ErrInvalidArgument)
+	}
+	if _, err := strconv.ParseUint(x, 10, 64); err != nil {
+		return "", 0, fmt.Errorf("%w: invalid event cursor", domain.ErrInvalidArgument)
+	}
+	seq, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return "", 0, fmt.Errorf("%w: invalid event cursor", domain.ErrInvalidArgument)
+	}
+	return x, seq, nil
+}
@@ -0,0 +1,143 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/always-tired/crud-subscriptions/internal/domain"
+)
+
+const paymentMethodColumns = `p.id, p.label, p.type, COALESCE(p.last4, ''), p.expiry, p.created_at, p.updated_at`
+
+func scanPaymentMethod(row pgx.Row) (domain.PaymentMethod, error) {
+	var p domain.PaymentMethod
+	err := row.Scan(&p.ID, &p.Label, &p.Type, &p.Last4, &p.Expiry, &p.CreatedAt, &p.UpdatedAt)
+	return p, err
+}
+
+type PaymentMethodRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewPaymentMethodRepository(pool *pgxpool.Pool) *PaymentMethodRepository {
+	return &PaymentMethodRepository{pool: pool}
+}
+
+func (r *PaymentMethodRepository) CreatePaymentMethod(ctx context.Context, p domain.PaymentMethod) (domain.PaymentMethod, error) {
+	query := `
+		INSERT INTO payment_methods AS p (id, label, type, last4, expiry, created_at, updated_at)
+		VALUES ($1, $2, $3, NULLIF($4, ''), $5, $6, $7)
+		RETURNING ` + paymentMethodColumns
+
+	created, err := scanPaymentMethod(conn(ctx, r.pool).QueryRow(ctx, query,
+		p.ID, p.Label, p.Type, p.Last4, p.Expiry, p.CreatedAt, p.UpdatedAt,
+	))
+	if err != nil {
+		return domain.PaymentMethod{}, fmt.Errorf("repo CreatePaymentMethod: %w", err)
+	}
+	return created, nil
+}
+
+func (r *PaymentMethodRepository) GetPaymentMethod(ctx context.Context, id uuid.UUID) (domain.PaymentMethod, error) {
+	query := `SELECT ` + paymentMethodColumns + ` FROM payment_methods p WHERE p.id = $1`
+
+	p, err := scanPaymentMethod(conn(ctx, r.pool).QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.PaymentMethod{}, domain.ErrNotFound
+		}
+		return domain.PaymentMethod{}, fmt.Errorf("repo GetPaymentMethod: %w", err)
+	}
+	return p, nil
+}
+
+func (r *PaymentMethodRepository) ListPaymentMethods(ctx context.Context) ([]domain.PaymentMethod, error) {
+	query := `SELECT ` + paymentMethodColumns + ` FROM payment_methods p ORDER BY lower(p.label), p.id`
+
+	rows, err := conn(ctx, r.pool).Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("repo ListPaymentMethods: %w", err)
+	}
+	defer rows.Close()
+
+	res := make([]domain.PaymentMethod, 0)
+	for rows.Next() {
+		p, err := scanPaymentMethod(rows)
+		if err != nil {
+			return nil, fmt.Errorf("repo ListPaymentMethods: %w", err)
+		}
+		res = append(res, p)
+	}
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("repo ListPaymentMethods: %w", rows.Err())
+	}
+	return res, nil
+}
+
+// ListExpiringPaymentMethods returns the methods whose expiry month is not
+// after until, expired ones included, soonest first.
+func (r *PaymentMethodRepository) ListExpiringPaymentMethods(ctx context.Context, until time.Time) ([]domain.PaymentMethod, error) {
+	query := `
+		SELECT ` + paymentMethodColumns + `
+		FROM payment_methods p
+		WHERE p.expiry <= $1
+		ORDER BY p.expiry, lower(p.label), p.id
+	`
+
+	rows, err := conn(ctx, r.pool).Query(ctx, query, until)
+	if err != nil {
+		return nil, fmt.Errorf("repo ListExpiringPaymentMethods: %w", err)
+	}
+	defer rows.Close()
+
+	res := make([]domain.PaymentMethod, 0)
+	for rows.Next() {
+		p, err := scanPaymentMethod(rows)
+		if err != nil {
+			return nil, fmt.Errorf("repo ListExpiringPaymentMethods: %w", err)
+		}
+		res = append(res, p)
+	}
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("repo ListExpiringPaymentMethods: %w", rows.Err())
+	}
+	return res, nil
+}
+
+func (r *PaymentMethodRepository) UpdatePaymentMethod(ctx context.Context, p domain.PaymentMethod) (domain.PaymentMethod, error) {
+	query := `
+		UPDATE payment_methods AS p
+		SET label = $2,
+			type = $3,
+			last4 = NULLIF($4, ''),
+			expiry = $5,
+			updated_at = $6
+		WHERE p.id = $1
+		RETURNING ` + paymentMethodColumns
+
+	updated, err := scanPaymentMethod(conn(ctx, r.pool).QueryRow(ctx, query, p.ID, p.Label, p.Type, p.Last4, p.Expiry, p.UpdatedAt))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.PaymentMethod{}, domain.ErrNotFound
+		}
+		return domain.PaymentMethod{}, fmt.Errorf("repo UpdatePaymentMethod: %w", err)
+	}
+	return updated, nil
+}
+
+func (r *PaymentMethodRepository) DeletePaymentMethod(ctx context.Context, id uuid.UUID) error {
+	cmd, err := conn(ctx, r.pool).Exec(ctx, `DELETE FROM payment_methods WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("repo DeletePaymentMethod: %w", err)
+	}
+	if cmd.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/always-tired/crud-subscriptions/internal/domain"
+)
+
+type ReminderRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewReminderRepository(pool *pgxpool.Pool) *ReminderRepository {
+	return &ReminderRepository{pool: pool}
+}
+
+// ClaimReminder inserts the reminder unless it exists. An unsent claim left by
+// a crashed worker is taken over once it is older than ten minutes. Reminders
+// without a subscription, such as spend anomalies, are unique per user.
+func (r *ReminderRepository) ClaimReminder(ctx context.Context, rem domain.Reminder) (bool, error) {
+	var subscriptionID *uuid.UUID
+	target := "(subscription_id, kind, due_date, channel)"
+	if rem.SubscriptionID != uuid.Nil {
+		subscriptionID = &rem.SubscriptionID
+	} else {
+		target = "(user_id, kind, due_date, channel) WHERE subscription_id IS NULL"
+	}
+
+	query := `
+		INSERT INTO reminders (id, kind, subscription_id, user_id, due_date, channel)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT ` + target + ` DO UPDATE
+		SET id = EXCLUDED.id,
+			claimed_at = NOW()
+		WHERE reminders.sent_at IS NULL
+		  AND reminders.claimed_at < NOW() - interval '10 minutes'
+		RETURNING id
+	`
+
+	var id uuid.UUID
+	err := conn(ctx, r.pool).QueryRow(ctx, query,
+		rem.ID, rem.Kind, subscriptionID, rem.UserID, rem.DueDate, rem.Channel,
+	).Scan(&id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return false, nil
+		}
+		return false, fmt.Errorf("repo ClaimReminder: %w", err)
+	}
+	return true, nil
+}
+
+func (r *ReminderRepository) MarkReminderSent(ctx context.Context, id uuid.UUID) error {
+	if _, err := conn(ctx, r.pool).Exec(ctx, `UPDATE reminders SET sent_at = NOW() WHERE id = $1`, id); err != nil {
+		return fmt.Errorf("repo MarkReminderSent: %w", err)
+	}
+	return nil
+}
+
+func (r *ReminderRepository) ReleaseReminder(ctx context.Context, id uuid.UUID) error {
+	if _, err := conn(ctx, r.pool).Exec(ctx, `DELETE FROM reminders WHERE id = $1 AND sent_at IS NULL`, id); err != nil {
+		return fmt.Errorf("repo ReleaseReminder: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,205 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/always-tired/crud-subscriptions/internal/domain"
+)
+
+// maxLevenshteinLength is the longest string fuzzystrmatch compares.
+const maxLevenshteinLength = 255
+
+const catalogServiceColumns = `s.id, s.name, s.aliases, s.default_price, s.yearly_price, s.family_price, s.vendor_url, s.category_id, s.created_at, s.updated_at`
+
+func scanCatalogService(row pgx.Row) (domain.CatalogService, error) {
+	var svc domain.CatalogService
+	err := row.Scan(&svc.ID, &svc.Name, &svc.Aliases, &svc.DefaultPrice, &svc.YearlyPrice, &svc.FamilyPrice, &svc.VendorURL, &svc.CategoryID, &svc.CreatedAt, &svc.UpdatedAt)
+	return svc, err
+}
+
+type ServiceCatalogRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewServiceCatalogRepository(pool *pgxpool.Pool) *ServiceCatalogRepository {
+	return &ServiceCatalogRepository{pool: pool}
+}
+
+func (r *ServiceCatalogRepository) CreateService(ctx context.Context, svc domain.CatalogService) (domain.CatalogService, error) {
+	query := `
+		INSERT INTO services AS s (id, name, aliases, default_price, vendor_url, category_id, yearly_price, family_price)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING ` + catalogServiceColumns
+
+	created, err := scanCatalogService(conn(ctx, r.pool).QueryRow(ctx, query,
+		svc.ID, svc.Name, svc.Aliases, svc.DefaultPrice, svc.VendorURL, svc.CategoryID, svc.YearlyPrice, svc.FamilyPrice,
+	))
+	if err != nil {
+		if err := mapWriteError(err); err != nil {
+			return domain.CatalogService{}, err
+		}
+		return domain.CatalogService{}, fmt.Errorf("repo CreateService: %w", err)
+	}
+	return created, nil
+}
+
+func (r *ServiceCatalogRepository) GetService(ctx context.Context, id uuid.UUID) (domain.CatalogService, error) {
+	query := `SELECT ` + catalogServiceColumns + ` FROM services s WHERE s.id = $1`
+
+	svc, err := scanCatalogService(conn(ctx, r.pool).QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.CatalogService{}, domain.ErrNotFound
+		}
+		return domain.CatalogService{}, fmt.Errorf("repo GetService: %w", err)
+	}
+	return svc, nil
+}
+
+func (r *ServiceCatalogRepository) ListServices(ctx context.Context) ([]domain.CatalogService, error) {
+	query := `SELECT ` + catalogServiceColumns + ` FROM services s ORDER BY lower(s.name)`
+
+	rows, err := conn(ctx, r.pool).Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("repo ListServices: %w", err)
+	}
+	defer rows.Close()
+
+	res := make([]domain.CatalogService, 0)
+	for rows.Next() {
+		svc, err := scanCatalogService(rows)
+		if err != nil {
+			return nil, fmt.Errorf("repo ListServices: %w", err)
+		}
+		res = append(res, svc)
+	}
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("repo ListServices: %w", rows.Err())
+	}
+	return res, nil
+}
+
+// SetServiceNames replaces the normalized names the service is found by. A
+// name already taken by another service fails with domain.ErrDuplicate.
+func (r *ServiceCatalogRepository) SetServiceNames(ctx context.Context, id uuid.UUID, names []string) error {
+	if _, err := conn(ctx, r.pool).Exec(ctx, `DELETE FROM service_names WHERE service_id = $1`, id); err != nil {
+		return fmt.Errorf("repo SetServiceNames: %w", err)
+	}
+	if _, err := conn(ctx, r.pool).Exec(ctx,
+		`INSERT INTO service_names (name, service_id) SELECT unnest($2::text[]), $1::uuid`, id, names,
+	); err != nil {
+		if err := mapWriteError(err); err != nil {
+			return err
+		}
+		return fmt.Errorf("repo SetServiceNames: %w", err)
+	}
+	return nil
+}
+
+// FindService returns the service with the normalized name or alias name.
+// Failing that, it returns the single service with a name or alias within
+// maxDist edits of name; two services equally close match neither.
+func (r *ServiceCatalogRepository) FindService(ctx context.Context, name string, maxDist int) (domain.CatalogService, bool, error) {
+	query := `
+		SELECT ` + catalogServiceColumns + `
+		FROM services s
+		JOIN service_names n ON n.service_id = s.id
+		WHERE n.name = $1
+	`
+
+	svc, err := scanCatalogService(conn(ctx, r.pool).QueryRow(ctx, query, name))
+	if err == nil {
+		return svc, true, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return domain.CatalogService{}, false, fmt.Errorf("repo FindService: %w", err)
+	}
+	if maxDist <= 0 || len([]rune(name)) > maxLevenshteinLength {
+		return domain.CatalogService{}, false, nil
+	}
+
+	query = `
+		SELECT ` + catalogServiceColumns + `, d.dist
+		FROM (
+			SELECT n.service_id, MIN(levenshtein_less_equal(n.name, $1, $2)) AS dist
+			FROM service_names n
+			WHERE char_length(n.name) <= ` + fmt.Sprint(maxLevenshteinLength) + `
+			GROUP BY n.service_id
+		) d
+		JOIN services s ON s.id = d.service_id
+		WHERE d.dist <= $2
+		ORDER BY d.dist
+		LIMIT 2
+	`
+
+	rows, err := conn(ctx, r.pool).Query(ctx, query, name, maxDist)
+	if err != nil {
+		return domain.CatalogService{}, false, fmt.Errorf("repo FindService: %w", err)
+	}
+	defer rows.Close()
+
+	var found []domain.CatalogService
+	var dists []int
+	for rows.Next() {
+		var svc domain.CatalogService
+		var dist int
+		if err := rows.Scan(&svc.ID, &svc.Name, &svc.Aliases, &svc.DefaultPrice, &svc.YearlyPrice, &svc.FamilyPrice, &svc.VendorURL, &svc.CategoryID, &svc.CreatedAt, &svc.UpdatedAt, &dist); err != nil {
+			return domain.CatalogService{}, false, fmt.Errorf("repo FindService: %w", err)
+		}
+		found = append(found, svc)
+		dists = append(dists, dist)
+	}
+	if rows.Err() != nil {
+		return domain.CatalogService{}, false, fmt.Errorf("repo FindService: %w", rows.Err())
+	}
+	if len(found) == 0 || (len(found) == 2 && dists[0] == dists[1]) {
+		return domain.CatalogService{}, false, nil
+	}
+	return found[0], true, nil
+}
+
+func (r *ServiceCatalogRepository) UpdateService(ctx context.Context, svc domain.CatalogService) (domain.CatalogService, error) {
+	query := `
+		UPDATE services AS s
+		SET name = $2,
+			aliases = $3,
+			default_price = $4,
+			vendor_url = $5,
+			category_id = $6,
+			yearly_price = $7,
+			family_price = $8,
+			updated_at = NOW()
+		WHERE s.id = $1
+		RETURNING ` + catalogServiceColumns
+
+	updated, err := scanCatalogService(conn(ctx, r.pool).QueryRow(ctx, query,
+		svc.ID, svc.Name, svc.Aliases, svc.DefaultPrice, svc.VendorURL, svc.CategoryID, svc.YearlyPrice, svc.FamilyPrice,
+	))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.CatalogService{}, domain.ErrNotFound
+		}
+		if err := mapWriteError(err); err != nil {
+			return domain.CatalogService{}, err
+		}
+		return domain.CatalogService{}, fmt.Errorf("repo UpdateService: %w", err)
+	}
+	return updated, nil
+}
+
+func (r *ServiceCatalogRepository) DeleteService(ctx context.Context, id uuid.UUID) error {
+	cmd, err := conn(ctx, r.pool).Exec(ctx, `DELETE FROM services WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("repo DeleteService: %w", err)
+	}
+	if cmd.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,37 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/always-tired/crud-subscriptions/internal/domain"
+)
+
+// AddDiscount stores a discount of a subscription. Overlaps with other
+// discounts are checked by the caller.
+func (r *SubscriptionRepository) AddDiscount(ctx context.Context, id uuid.UUID, d domain.Discount) error {
+	query := `
+		INSERT INTO subscription_discounts (id, subscription_id, kind, value, valid_from, valid_until)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+
+	if _, err := conn(ctx, r.pool).Exec(ctx, query, d.ID, id, d.Kind, d.Value, d.From, d.Until); err != nil {
+		return fmt.Errorf("repo AddDiscount: %w", err)
+	}
+	return nil
+}
+
+func (r *SubscriptionRepository) DeleteDiscount(ctx context.Context, id, discountID uuid.UUID) error {
+	cmd, err := conn(ctx, r.pool).Exec(ctx,
+		`DELETE FROM subscription_discounts WHERE subscription_id = $1 AND id = $2`, id, discountID,
+	)
+	if err != nil {
+		return fmt.Errorf("repo DeleteDiscount: %w", err)
+	}
+	if cmd.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
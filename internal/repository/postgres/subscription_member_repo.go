@@ -0,0 +1,38 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/always-tired/crud-subscriptions/internal/domain"
+)
+
+// SetMembers replaces the members of a subscription.
+func (r *SubscriptionRepository) SetMembers(ctx context.Context, id uuid.UUID, members []domain.SubscriptionMember) error {
+	if _, err := conn(ctx, r.pool).Exec(ctx,
+		`DELETE FROM subscription_members WHERE subscription_id = $1`, id,
+	); err != nil {
+		return fmt.Errorf("repo SetMembers: %w", err)
+	}
+	if len(members) == 0 {
+		return nil
+	}
+
+	userIDs := make([]uuid.UUID, 0, len(members))
+	shares := make([]int, 0, len(members))
+	for _, m := range members {
+		userIDs = append(userIDs, m.UserID)
+		shares = append(shares, m.Share)
+	}
+	query := `
+		INSERT INTO subscription_members (subscription_id, user_id, share)
+		SELECT $1, u.user_id, u.share
+		FROM unnest($2::uuid[], $3::int[]) AS u(user_id, share)
+	`
+	if _, err := conn(ctx, r.pool).Exec(ctx, query, id, userIDs, shares); err != nil {
+		return fmt.Errorf("repo SetMembers: %w", err)
+	}
+	return nil
+}
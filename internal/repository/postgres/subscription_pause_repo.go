@@ -0,0 +1,56 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/always-tired/crud-subscriptions/internal/domain"
+)
+
+// AddPause stores a pause of a subscription. Overlaps with other pauses are
+// checked by the caller while it holds the lock of GetForUpdate.
+func (r *SubscriptionRepository) AddPause(ctx context.Context, id uuid.UUID, p domain.PausePeriod) error {
+	query := `
+		INSERT INTO subscription_pauses (subscription_id, paused_from, paused_until)
+		VALUES ($1, $2, $3)
+	`
+
+	if _, err := conn(ctx, r.pool).Exec(ctx, query, id, p.From, p.Until); err != nil {
+		if err := mapWriteError(err); err != nil {
+			return err
+		}
+		return fmt.Errorf("repo AddPause: %w", err)
+	}
+	return nil
+}
+
+// EndPause sets the last paused month of the pause starting at from.
+func (r *SubscriptionRepository) EndPause(ctx context.Context, id uuid.UUID, from, until time.Time) error {
+	cmd, err := conn(ctx, r.pool).Exec(ctx,
+		`UPDATE subscription_pauses SET paused_until = $3 WHERE subscription_id = $1 AND paused_from = $2`,
+		id, from, until,
+	)
+	if err != nil {
+		return fmt.Errorf("repo EndPause: %w", err)
+	}
+	if cmd.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+func (r *SubscriptionRepository) DeletePause(ctx context.Context, id uuid.UUID, from time.Time) error {
+	cmd, err := conn(ctx, r.pool).Exec(ctx,
+		`DELETE FROM subscription_pauses WHERE subscription_id = $1 AND paused_from = $2`, id, from,
+	)
+	if err != nil {
+		return fmt.Errorf("repo DeletePause: %w", err)
+	}
+	if cmd.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
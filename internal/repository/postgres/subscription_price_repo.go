@@ -0,0 +1,85 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/always-tired/crud-subscriptions/internal/domain"
+)
+
+// ListPrices returns the price periods of a subscription ordered by month.
+// The first period is the initial price from the subscription row itself.
+func (r *SubscriptionRepository) ListPrices(ctx context.Context, id uuid.UUID) ([]domain.PricePeriod, error) {
+	var first domain.PricePeriod
+	err := conn(ctx, r.pool).QueryRow(ctx,
+		`SELECT start_date, price FROM subscriptions WHERE id = $1 AND deleted_at IS NULL`, id,
+	).Scan(&first.EffectiveFrom, &first.Price)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("repo ListPrices: %w", err)
+	}
+
+	query := `
+		SELECT effective_from, price
+		FROM subscription_prices
+		WHERE subscription_id = $1
+		  AND effective_from > $2
+		ORDER BY effective_from
+	`
+
+	rows, err := conn(ctx, r.pool).Query(ctx, query, id, first.EffectiveFrom)
+	if err != nil {
+		return nil, fmt.Errorf("repo ListPrices: %w", err)
+	}
+	defer rows.Close()
+
+	res := []domain.PricePeriod{first}
+	for rows.Next() {
+		var p domain.PricePeriod
+		if err := rows.Scan(&p.EffectiveFrom, &p.Price); err != nil {
+			return nil, fmt.Errorf("repo ListPrices: %w", err)
+		}
+		res = append(res, p)
+	}
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("repo ListPrices: %w", rows.Err())
+	}
+	return res, nil
+}
+
+// SetPrice records the price that applies from the given month, replacing a
+// change already scheduled for that month.
+func (r *SubscriptionRepository) SetPrice(ctx context.Context, id uuid.UUID, from time.Time, price int) error {
+	query := `
+		INSERT INTO subscription_prices (subscription_id, effective_from, price)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (subscription_id, effective_from) DO UPDATE
+		SET price = EXCLUDED.price,
+			created_at = NOW()
+	`
+
+	if _, err := conn(ctx, r.pool).Exec(ctx, query, id, from, price); err != nil {
+		return fmt.Errorf("repo SetPrice: %w", err)
+	}
+	return nil
+}
+
+func (r *SubscriptionRepository) DeletePrice(ctx context.Context, id uuid.UUID, from time.Time) error {
+	cmd, err := conn(ctx, r.pool).Exec(ctx,
+		`DELETE FROM subscription_prices WHERE subscription_id = $1 AND effective_from = $2`, id, from,
+	)
+	if err != nil {
+		return fmt.Errorf("repo DeletePrice: %w", err)
+	}
+	if cmd.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
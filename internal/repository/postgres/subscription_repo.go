@@ -15,119 +15,303 @@ import (
 	"github.com/always-tired/crud-subscriptions/internal/usecase"
 )
 
-const uniqueViolation = "23505"
+const (
+	uniqueViolation     = "23505"
+	foreignKeyViolation = "23503"
+)
+
+// subscriptionColumns selects a subscription aliased as s. The price is the one
+// in effect in the month given by the parameter, falling back to the initial
+// price. Price changes not after start_date are ignored, see ListPrices. The
+// price series and the pauses come as pairs of arrays ordered by month, the
+// discounts as arrays ordered by month and the members as a pair ordered by
+// user.
+func subscriptionColumns(month string) string {
+	return `
+	s.id, s.service_name,
+	COALESCE((
+		SELECT sp.price FROM subscription_prices sp
+		WHERE sp.subscription_id = s.id
+		  AND sp.effective_from > s.start_date
+		  AND sp.effective_from <= ` + month + `::date
+		ORDER BY sp.effective_from DESC
+		LIMIT 1
+	), s.price),
+	s.user_id, s.start_date, s.end_date, s.trial_end_date,
+	s.category_id, COALESCE((SELECT c.name FROM categories c WHERE c.id = s.category_id), ''), s.tags,
+	s.payment_method_id, COALESCE((SELECT pm.label FROM payment_methods pm WHERE pm.id = s.payment_method_id), ''),
+	ARRAY[s.start_date] || ARRAY(
+		SELECT sp.effective_from FROM subscription_prices sp
+		WHERE sp.subscription_id = s.id AND sp.effective_from > s.start_date
+		ORDER BY sp.effective_from
+	),
+	ARRAY[s.price] || ARRAY(
+		SELECT sp.price FROM subscription_prices sp
+		WHERE sp.subscription_id = s.id AND sp.effective_from > s.start_date
+		ORDER BY sp.effective_from
+	),
+	ARRAY(SELECT sp.paused_from FROM subscription_pauses sp WHERE sp.subscription_id = s.id ORDER BY sp.paused_from),
+	ARRAY(SELECT sp.paused_until FROM subscription_pauses sp WHERE sp.subscription_id = s.id ORDER BY sp.paused_from),
+	ARRAY(SELECT d.id FROM subscription_discounts d WHERE d.subscription_id = s.id ORDER BY d.valid_from),
+	ARRAY(SELECT d.kind FROM subscription_discounts d WHERE d.subscription_id = s.id ORDER BY d.valid_from),
+	ARRAY(SELECT d.value FROM subscription_discounts d WHERE d.subscription_id = s.id ORDER BY d.valid_from),
+	ARRAY(SELECT d.valid_from FROM subscription_discounts d WHERE d.subscription_id = s.id ORDER BY d.valid_from),
+	ARRAY(SELECT d.valid_until FROM subscription_discounts d WHERE d.subscription_id = s.id ORDER BY d.valid_from),
+	COALESCE(s.split_rule, ''),
+	ARRAY(SELECT sm.user_id FROM subscription_members sm WHERE sm.subscription_id = s.id ORDER BY sm.user_id),
+	ARRAY(SELECT sm.share FROM subscription_members sm WHERE sm.subscription_id = s.id ORDER BY sm.user_id),
+	s.tax_rate, s.tax_inclusive, s.unused,
+	COALESCE(s.cancel_reason, ''), s.cancelled_at,
+	s.created_at, s.updated_at, s.deleted_at`
+}
+
+// categoryMatches is true when the subscription aliased as s is in the
+// category given by ID or name in the parameter, or the parameter is NULL.
+func categoryMatches(param string) string {
+	return `(` + param + `::text IS NULL OR s.category_id IN (
+		SELECT c.id FROM categories c WHERE c.id::text = ` + param + ` OR lower(c.name) = lower(` + param + `)
+	))`
+}
 
+func scanSubscription(row pgx.Row) (domain.Subscription, error) {
+	var s domain.Subscription
+	var endDate, deletedAt *time.Time
+	var priceFrom, pausedFrom, discountFrom []time.Time
+	var prices, memberShares, discountValues []int
+	var pausedUntil, discountUntil []*time.Time
+	var memberIDs, discountIDs []uuid.UUID
+	var discountKinds []string
+	if err := row.Scan(
+		&s.ID,
+		&s.ServiceName,
+		&s.Price,
+		&s.UserID,
+		&s.StartDate,
+		&endDate,
+		&s.TrialEndDate,
+		&s.CategoryID,
+		&s.Category,
+		&s.Tags,
+		&s.PaymentMethodID,
+		&s.PaymentMethod,
+		&priceFrom,
+		&prices,
+		&pausedFrom,
+		&pausedUntil,
+		&discountIDs,
+		&discountKinds,
+		&discountValues,
+		&discountFrom,
+		&discountUntil,
+		&s.SplitRule,
+		&memberIDs,
+		&memberShares,
+		&s.TaxRate,
+		&s.TaxInclusive,
+		&s.Unused,
+		&s.CancelReason,
+		&s.CancelledAt,
+		&s.CreatedAt,
+		&s.UpdatedAt,
+		&deletedAt,
+	); err != nil {
+		return domain.Subscription{}, err
+	}
+	s.EndDate = endDate
+	s.DeletedAt = deletedAt
+	for i, from := range priceFrom {
+		s.Prices = append(s.Prices, domain.PricePeriod{EffectiveFrom: from, Price: prices[i]})
+	}
+	for i, from := range pausedFrom {
+		s.Pauses = append(s.Pauses, domain.PausePeriod{From: from, Until: pausedUntil[i]})
+	}
+	for i, id := range discountIDs {
+		s.Discounts = append(s.Discounts, domain.Discount{
+			ID:    id,
+			Kind:  discountKinds[i],
+			Value: discountValues[i],
+			From:  discountFrom[i],
+			Until: discountUntil[i],
+		})
+	}
+	for i, id := range memberIDs {
+		s.Members = append(s.Members, domain.SubscriptionMember{UserID: id, Share: memberShares[i]})
+	}
+	return s, nil
+}
+
+// SubscriptionRepository reads prices for the current month of clock, which
+// should be the service's, so a frozen clock freezes them too.
 type SubscriptionRepository struct {
-	pool *pgxpool.Pool
+	pool  *pgxpool.Pool
+	clock usecase.Clock
+}
+
+func NewSubscriptionRepository(pool *pgxpool.Pool, clock usecase.Clock) *SubscriptionRepository {
+	return &SubscriptionRepository{pool: pool, clock: clock}
 }
 
-func NewSubscriptionRepository(pool *pgxpool.Pool) *SubscriptionRepository {
-	return &SubscriptionRepository{pool: pool}
+// month is the current month of the clock, the one subscriptionColumns
+// reports the price for.
+func (r *SubscriptionRepository) month() time.Time {
+	return usecase.StartOfMonth(r.clock.Now())
 }
 
 func (r *SubscriptionRepository) Create(ctx context.Context, s domain.Subscription) (domain.Subscription, error) {
 	query := `
-		INSERT INTO subscriptions (id, service_name, price, user_id, start_date, end_date)
-		VALUES ($1, $2, $3, $4, $5, $6)
-		RETURNING id, service_name, price, user_id, start_date, end_date, created_at, updated_at
-	`
+		INSERT INTO subscriptions AS s (
+			id, service_name, price, user_id, start_date, end_date, category_id, tags, trial_end_date,
+			split_rule, tax_rate, tax_inclusive, payment_method_id, unused, created_at, updated_at
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, COALESCE($8::text[], '{}'), $9, NULLIF($10, ''), $11, $12, $13, $14, $15, $16)
+		RETURNING ` + subscriptionColumns("$17")
 
-	var endDate *time.Time
-	var created domain.Subscription
-	if err := r.pool.QueryRow(ctx, query,
-		s.ID, s.ServiceName, s.Price, s.UserID, s.StartDate, s.EndDate,
-	).Scan(
-		&created.ID,
-		&created.ServiceName,
-		&created.Price,
-		&created.UserID,
-		&created.StartDate,
-		&endDate,
-		&created.CreatedAt,
-		&created.UpdatedAt,
-	); err != nil {
-		var pgErr *pgconn.PgError
-		if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation {
-			return domain.Subscription{}, domain.ErrDuplicate
+	created, err := scanSubscription(conn(ctx, r.pool).QueryRow(ctx, query,
+		s.ID, s.ServiceName, s.Price, s.UserID, s.StartDate, s.EndDate, s.CategoryID, s.Tags, s.TrialEndDate,
+		s.SplitRule, s.TaxRate, s.TaxInclusive, s.PaymentMethodID, s.Unused, s.CreatedAt, s.UpdatedAt, r.month(),
+	))
+	if err != nil {
+		if err := mapWriteError(err); err != nil {
+			return domain.Subscription{}, err
 		}
 		return domain.Subscription{}, fmt.Errorf("repo CreateSubscription: %w", err)
 	}
-	created.EndDate = endDate
 	return created, nil
 }
 
 func (r *SubscriptionRepository) Get(ctx context.Context, id uuid.UUID) (domain.Subscription, error) {
 	query := `
-		SELECT id, service_name, price, user_id, start_date, end_date, created_at, updated_at
-		FROM subscriptions
-		WHERE id = $1
+		SELECT ` + subscriptionColumns("$2") + `
+		FROM subscriptions s
+		WHERE s.id = $1
+		  AND s.deleted_at IS NULL
 	`
 
-	var s domain.Subscription
-	var endDate *time.Time
-
-	err := r.pool.QueryRow(ctx, query, id).Scan(
-		&s.ID,
-		&s.ServiceName,
-		&s.Price,
-		&s.UserID,
-		&s.StartDate,
-		&endDate,
-		&s.CreatedAt,
-		&s.UpdatedAt,
-	)
+	s, err := scanSubscription(conn(ctx, r.pool).QueryRow(ctx, query, id, r.month()))
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return domain.Subscription{}, domain.ErrNotFound
 		}
 		return domain.Subscription{}, fmt.Errorf("repo GetSubscription: %w", err)
 	}
-	s.EndDate = endDate
 	return s, nil
 }
 
+// GetForUpdate is Get that also locks the subscription row until the
+// surrounding transaction ends, so that checks against its pauses, discounts
+// or dates cannot race with another change of the same subscription.
+func (r *SubscriptionRepository) GetForUpdate(ctx context.Context, id uuid.UUID) (domain.Subscription, error) {
+	query := `
+		SELECT ` + subscriptionColumns("$2") + `
+		FROM subscriptions s
+		WHERE s.id = $1
+		  AND s.deleted_at IS NULL
+		FOR UPDATE OF s
+	`
+
+	s, err := scanSubscription(conn(ctx, r.pool).QueryRow(ctx, query, id, r.month()))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.Subscription{}, domain.ErrNotFound
+		}
+		return domain.Subscription{}, fmt.Errorf("repo GetSubscriptionForUpdate: %w", err)
+	}
+	return s, nil
+}
+
+// LockUserService takes a transaction-scoped advisory lock on the user and
+// service key, serializing overlap checks of the same user and service until
+// the surrounding transaction ends.
+func (r *SubscriptionRepository) LockUserService(ctx context.Context, userID uuid.UUID, key string) error {
+	if _, err := conn(ctx, r.pool).Exec(ctx,
+		`SELECT pg_advisory_xact_lock(hashtext($1::text || '/' || $2))`, userID, key,
+	); err != nil {
+		return fmt.Errorf("repo LockUserService: %w", err)
+	}
+	return nil
+}
+
+// Update replaces the subscription row. s.Price is stored as the initial price;
+// later changes live in subscription_prices and are left untouched, as are the
+// members, see SetMembers.
 func (r *SubscriptionRepository) Update(ctx context.Context, s domain.Subscription) (domain.Subscription, error) {
 	query := `
-		UPDATE subscriptions
+		UPDATE subscriptions AS s
 		SET service_name = $2,
 			price = $3,
 			user_id = $4,
 			start_date = $5,
 			end_date = $6,
-			updated_at = NOW()
-		WHERE id = $1
-		RETURNING id, service_name, price, user_id, start_date, end_date, created_at, updated_at
-	`
+			category_id = $7,
+			tags = COALESCE($8::text[], '{}'),
+			trial_end_date = $9,
+			split_rule = NULLIF($11, ''),
+			tax_rate = $12,
+			tax_inclusive = $13,
+			payment_method_id = $14,
+			unused = $15,
+			cancel_reason = CASE WHEN $6::date IS NULL THEN NULL ELSE s.cancel_reason END,
+			cancelled_at = CASE WHEN $6::date IS NULL THEN NULL ELSE s.cancelled_at END,
+			updated_at = $10
+		WHERE s.id = $1
+		  AND s.deleted_at IS NULL
+		RETURNING ` + subscriptionColumns("$16")
 
-	var endDate *time.Time
-	var updated domain.Subscription
-	if err := r.pool.QueryRow(ctx, query,
-		s.ID, s.ServiceName, s.Price, s.UserID, s.StartDate, s.EndDate,
-	).Scan(
-		&updated.ID,
-		&updated.ServiceName,
-		&updated.Price,
-		&updated.UserID,
-		&updated.StartDate,
-		&endDate,
-		&updated.CreatedAt,
-		&updated.UpdatedAt,
-	); err != nil {
+	updated, err := scanSubscription(conn(ctx, r.pool).QueryRow(ctx, query,
+		s.ID, s.ServiceName, s.Price, s.UserID, s.StartDate, s.EndDate, s.CategoryID, s.Tags, s.TrialEndDate,
+		s.UpdatedAt, s.SplitRule, s.TaxRate, s.TaxInclusive, s.PaymentMethodID, s.Unused, r.month(),
+	))
+	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return domain.Subscription{}, domain.ErrNotFound
 		}
-		var pgErr *pgconn.PgError
-		if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation {
-			return domain.Subscription{}, domain.ErrDuplicate
+		if err := mapWriteError(err); err != nil {
+			return domain.Subscription{}, err
 		}
 		return domain.Subscription{}, fmt.Errorf("repo UpdateSubscription: %w", err)
 	}
-	updated.EndDate = endDate
 	return updated, nil
 }
 
-func (r *SubscriptionRepository) Delete(ctx context.Context, id uuid.UUID) error {
-	cmd, err := r.pool.Exec(ctx, `DELETE FROM subscriptions WHERE id = $1`, id)
+// foreignKeys names the field behind each foreign key writes can violate.
+var foreignKeys = map[string]string{
+	"subscriptions_category_id_fkey":       "category_id",
+	"services_category_id_fkey":            "category_id",
+	"budgets_category_id_fkey":             "category_id",
+	"subscriptions_user_id_fkey":           "user_id",
+	"subscriptions_payment_method_id_fkey": "payment_method_id",
+	"subscription_members_user_id_fkey":    "member user_id",
+}
+
+// mapWriteError maps constraint violations of writes to domain errors. It
+// returns nil for any other error.
+func mapWriteError(err error) error {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return nil
+	}
+	switch pgErr.Code {
+	case uniqueViolation:
+		return domain.ErrDuplicate
+	case foreignKeyViolation:
+		if field, ok := foreignKeys[pgErr.ConstraintName]; ok {
+			return fmt.Errorf("%w: unknown %s", domain.ErrInvalidArgument, field)
+		}
+		return fmt.Errorf("%w: unknown reference", domain.ErrInvalidArgument)
+	}
+	return nil
+}
+
+// Delete marks the subscription as deleted. The row stays until Purge.
+func (r *SubscriptionRepository) Delete(ctx context.Context, id uuid.UUID, at time.Time) error {
+	query := `
+		UPDATE subscriptions
+		SET deleted_at = $2
+		WHERE id = $1
+		  AND deleted_at IS NULL
+	`
+
+	cmd, err := conn(ctx, r.pool).Exec(ctx, query, id, at)
 	if err != nil {
 		return fmt.Errorf("repo DeleteSubscription: %w", err)
 	}
@@ -137,13 +321,101 @@ func (r *SubscriptionRepository) Delete(ctx context.Context, id uuid.UUID) error
 	return nil
 }
 
+// Restore clears the deletion mark. Restoring fails with ErrDuplicate when a
+// live subscription with the same user, service and start exists.
+func (r *SubscriptionRepository) Restore(ctx context.Context, id uuid.UUID, at time.Time) (domain.Subscription, error) {
+	query := `
+		UPDATE subscriptions AS s
+		SET deleted_at = NULL,
+			updated_at = $2
+		WHERE s.id = $1
+		  AND s.deleted_at IS NOT NULL
+		RETURNING ` + subscriptionColumns("$3")
+
+	restored, err := scanSubscription(conn(ctx, r.pool).QueryRow(ctx, query, id, at, r.month()))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.Subscription{}, domain.ErrNotFound
+		}
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation {
+			return domain.Subscription{}, domain.ErrDuplicate
+		}
+		return domain.Subscription{}, fmt.Errorf("repo RestoreSubscription: %w", err)
+	}
+	return restored, nil
+}
+
+// Cancel sets the last billed month of the subscription and records why and
+// when it was cancelled.
+func (r *SubscriptionRepository) Cancel(ctx context.Context, id uuid.UUID, end time.Time, reason string, at time.Time) (domain.Subscription, error) {
+	query := `
+		UPDATE subscriptions AS s
+		SET end_date = $2,
+			cancel_reason = NULLIF($3, ''),
+			cancelled_at = $4,
+			updated_at = $4
+		WHERE s.id = $1
+		  AND s.deleted_at IS NULL
+		RETURNING ` + subscriptionColumns("$5")
+
+	cancelled, err := scanSubscription(conn(ctx, r.pool).QueryRow(ctx, query, id, end, reason, at, r.month()))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.Subscription{}, domain.ErrNotFound
+		}
+		return domain.Subscription{}, fmt.Errorf("repo CancelSubscription: %w", err)
+	}
+	return cancelled, nil
+}
+
+// Reactivate clears the end date and the cancellation of the subscription.
+func (r *SubscriptionRepository) Reactivate(ctx context.Context, id uuid.UUID, at time.Time) (domain.Subscription, error) {
+	query := `
+		UPDATE subscriptions AS s
+		SET end_date = NULL,
+			cancel_reason = NULL,
+			cancelled_at = NULL,
+			updated_at = $2
+		WHERE s.id = $1
+		  AND s.deleted_at IS NULL
+		RETURNING ` + subscriptionColumns("$3")
+
+	reactivated, err := scanSubscription(conn(ctx, r.pool).QueryRow(ctx, query, id, at, r.month()))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.Subscription{}, domain.ErrNotFound
+		}
+		return domain.Subscription{}, fmt.Errorf("repo ReactivateSubscription: %w", err)
+	}
+	return reactivated, nil
+}
+
+// Purge permanently removes subscriptions deleted before the given time.
+func (r *SubscriptionRepository) Purge(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	cmd, err := conn(ctx, r.pool).Exec(ctx,
+		`DELETE FROM subscriptions WHERE deleted_at IS NOT NULL AND deleted_at < $1`, deletedBefore,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("repo PurgeSubscriptions: %w", err)
+	}
+	return cmd.RowsAffected(), nil
+}
+
 func (r *SubscriptionRepository) List(ctx context.Context, filter usecase.ListFilter) ([]domain.Subscription, error) {
 	query := `
-		SELECT id, service_name, price, user_id, start_date, end_date, created_at, updated_at
-		FROM subscriptions
-		WHERE ($1::uuid IS NULL OR user_id = $1)
-		  AND ($2::text IS NULL OR service_name = $2)
-		ORDER BY created_at DESC
+		SELECT ` + subscriptionColumns("$10") + `
+		FROM subscriptions s
+		WHERE ($1::uuid IS NULL OR s.user_id = $1)
+		  AND ($2::text IS NULL OR s.service_name = $2)
+		  AND ($5::boolean OR s.deleted_at IS NULL)
+		  AND ` + categoryMatches("$6") + `
+		  AND ($7::text[] IS NULL OR s.tags @> $7)
+		  AND ($8::int IS NULL OR (
+			s.trial_end_date + interval '1 month' >= $9::date
+			AND s.trial_end_date + interval '1 month' <= $9::date + $8::int
+		  ))
+		ORDER BY s.created_at DESC
 		LIMIT $3 OFFSET $4
 	`
 
@@ -156,7 +428,10 @@ func (r *SubscriptionRepository) List(ctx context.Context, filter usecase.ListFi
 		offset = 0
 	}
 
-	rows, err := r.pool.Query(ctx, query, filter.UserID, filter.ServiceName, limit, offset)
+	rows, err := conn(ctx, r.pool).Query(ctx, query,
+		filter.UserID, filter.ServiceName, limit, offset, filter.IncludeDeleted, filter.Category, filter.Tags,
+		filter.TrialEndingWithin, filter.Today, r.month(),
+	)
 	if err != nil {
 		return nil, fmt.Errorf("repo ListSubscriptions: %w", err)
 	}
@@ -164,21 +439,10 @@ func (r *SubscriptionRepository) List(ctx context.Context, filter usecase.ListFi
 
 	res := make([]domain.Subscription, 0)
 	for rows.Next() {
-		var s domain.Subscription
-		var endDate *time.Time
-		if err := rows.Scan(
-			&s.ID,
-			&s.ServiceName,
-			&s.Price,
-			&s.UserID,
-			&s.StartDate,
-			&endDate,
-			&s.CreatedAt,
-			&s.UpdatedAt,
-		); err != nil {
+		s, err := scanSubscription(rows)
+		if err != nil {
 			return nil, fmt.Errorf("repo ListSubscriptions: %w", err)
 		}
-		s.EndDate = endDate
 		res = append(res, s)
 	}
 	if rows.Err() != nil {
@@ -187,23 +451,388 @@ func (r *SubscriptionRepository) List(ctx context.Context, filter usecase.ListFi
 	return res, nil
 }
 
-func (r *SubscriptionRepository) Summary(ctx context.Context, filter usecase.SummaryFilter) (int64, error) {
+// ListActive returns every subscription of the user that has not ended before
+// the month of at. A nil userID matches all users.
+func (r *SubscriptionRepository) ListActive(ctx context.Context, userID *uuid.UUID, at time.Time) ([]domain.Subscription, error) {
 	query := `
-		WITH months AS (
-			SELECT generate_series($1::date, $2::date, interval '1 month') AS m
-		)
-		SELECT COALESCE(SUM(s.price), 0)
-		FROM months m
+		SELECT ` + subscriptionColumns("$3") + `
+		FROM subscriptions s
+		WHERE ($1::uuid IS NULL OR s.user_id = $1)
+		  AND s.deleted_at IS NULL
+		  AND (s.end_date IS NULL OR s.end_date >= date_trunc('month', $2::date))
+		ORDER BY s.start_date, s.service_name
+	`
+
+	rows, err := conn(ctx, r.pool).Query(ctx, query, userID, at, r.month())
+	if err != nil {
+		return nil, fmt.Errorf("repo ListActiveSubscriptions: %w", err)
+	}
+	defer rows.Close()
+
+	res := make([]domain.Subscription, 0)
+	for rows.Next() {
+		s, err := scanSubscription(rows)
+		if err != nil {
+			return nil, fmt.Errorf("repo ListActiveSubscriptions: %w", err)
+		}
+		res = append(res, s)
+	}
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("repo ListActiveSubscriptions: %w", rows.Err())
+	}
+	return res, nil
+}
+
+// ListActiveOfUser returns every subscription the user owns or is a member of
+// that has not ended before the month of at.
+func (r *SubscriptionRepository) ListActiveOfUser(ctx context.Context, userID uuid.UUID, at time.Time) ([]domain.Subscription, error) {
+	query := `
+		SELECT ` + subscriptionColumns("$3") + `
+		FROM subscriptions s
+		WHERE (s.user_id = $1 OR EXISTS (
+			SELECT 1 FROM subscription_members sm WHERE sm.subscription_id = s.id AND sm.user_id = $1
+		  ))
+		  AND s.deleted_at IS NULL
+		  AND (s.end_date IS NULL OR s.end_date >= date_trunc('month', $2::date))
+		ORDER BY s.start_date, s.service_name
+	`
+
+	rows, err := conn(ctx, r.pool).Query(ctx, query, userID, at, r.month())
+	if err != nil {
+		return nil, fmt.Errorf("repo ListActiveSubscriptionsOfUser: %w", err)
+	}
+	defer rows.Close()
+
+	res := make([]domain.Subscription, 0)
+	for rows.Next() {
+		s, err := scanSubscription(rows)
+		if err != nil {
+			return nil, fmt.Errorf("repo ListActiveSubscriptionsOfUser: %w", err)
+		}
+		res = append(res, s)
+	}
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("repo ListActiveSubscriptionsOfUser: %w", rows.Err())
+	}
+	return res, nil
+}
+
+// ListByPaymentMethods returns every subscription paid with one of the
+// methods that has not ended before the month of at.
+func (r *SubscriptionRepository) ListByPaymentMethods(ctx context.Context, ids []uuid.UUID, at time.Time) ([]domain.Subscription, error) {
+	query := `
+		SELECT ` + subscriptionColumns("$3") + `
+		FROM subscriptions s
+		WHERE s.payment_method_id = ANY($1)
+		  AND s.deleted_at IS NULL
+		  AND (s.end_date IS NULL OR s.end_date >= date_trunc('month', $2::date))
+		ORDER BY s.start_date, s.service_name
+	`
+
+	rows, err := conn(ctx, r.pool).Query(ctx, query, ids, at, r.month())
+	if err != nil {
+		return nil, fmt.Errorf("repo ListSubscriptionsByPaymentMethods: %w", err)
+	}
+	defer rows.Close()
+
+	res := make([]domain.Subscription, 0)
+	for rows.Next() {
+		s, err := scanSubscription(rows)
+		if err != nil {
+			return nil, fmt.Errorf("repo ListSubscriptionsByPaymentMethods: %w", err)
+		}
+		res = append(res, s)
+	}
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("repo ListSubscriptionsByPaymentMethods: %w", rows.Err())
+	}
+	return res, nil
+}
+
+// ListEnded returns live subscriptions whose end date lies before the given
+// month, i.e. whose last billed month is over.
+func (r *SubscriptionRepository) ListEnded(ctx context.Context, before time.Time) ([]domain.Subscription, error) {
+	query := `
+		SELECT ` + subscriptionColumns("$2") + `
+		FROM subscriptions s
+		WHERE s.deleted_at IS NULL
+		  AND s.end_date IS NOT NULL
+		  AND s.end_date < $1
+		ORDER BY s.end_date
+	`
+
+	rows, err := conn(ctx, r.pool).Query(ctx, query, before, r.month())
+	if err != nil {
+		return nil, fmt.Errorf("repo ListEndedSubscriptions: %w", err)
+	}
+	defer rows.Close()
+
+	res := make([]domain.Subscription, 0)
+	for rows.Next() {
+		s, err := scanSubscription(rows)
+		if err != nil {
+			return nil, fmt.Errorf("repo ListEndedSubscriptions: %w", err)
+		}
+		res = append(res, s)
+	}
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("repo ListEndedSubscriptions: %w", rows.Err())
+	}
+	return res, nil
+}
+
+// monthlyCharges expands live subscriptions into one row per billed month in
+// [$1, $2] with the price in effect in that month less its discount, as
+// columns month, amount, user_id, service_name, category_id and
+// payment_method_id. user_id is whoever the row is charged to. Trial and paused
+// months are not billed. Charges of shared subscriptions are split into one
+// row per user like domain.Subscription.Split does, so that the rows of a
+// charge add up to its price and the user filter $3 sees only that user's
+// shares. The remaining parameters are the filters of summaryArgs.
+//
+// Columns net and tax split each row by the tax rate of the subscription or
+// else its category, in basis points. Tax added on top of a net price is
+// rounded half up; for tax-inclusive prices the net amount is rounded half up
+// and the tax is the rest, so that net plus tax is the gross amount either way.
+var monthlyCharges = `
+	WITH charges AS (
+		SELECT m.m AS month, s.id, s.user_id, s.service_name, s.split_rule, s.category_id, s.payment_method_id,
+			COALESCE(s.tax_rate, tc.tax_rate, 0) AS tax_rate, s.tax_inclusive,
+			CASE d.kind
+				WHEN 'percentage' THEN lp.price - lp.price::bigint * d.value / 100
+				WHEN 'fixed' THEN GREATEST(lp.price - d.value, 0)
+				ELSE lp.price
+			END AS amount
+		FROM generate_series($1::date, $2::date, interval '1 month') AS m(m)
 		JOIN subscriptions s
 		  ON s.start_date <= m.m
 		 AND (s.end_date IS NULL OR s.end_date >= m.m)
-		WHERE ($3::uuid IS NULL OR s.user_id = $3)
-		  AND ($4::text IS NULL OR s.service_name = $4)
+		 AND (s.trial_end_date IS NULL OR s.trial_end_date < m.m)
+		 AND NOT EXISTS (
+			SELECT 1 FROM subscription_pauses sp
+			WHERE sp.subscription_id = s.id
+			  AND sp.paused_from <= m.m
+			  AND (sp.paused_until IS NULL OR sp.paused_until >= m.m)
+		 )
+		 AND s.deleted_at IS NULL
+		LEFT JOIN LATERAL (
+			SELECT sp.price FROM subscription_prices sp
+			WHERE sp.subscription_id = s.id
+			  AND sp.effective_from > s.start_date
+			  AND sp.effective_from <= m.m
+			ORDER BY sp.effective_from DESC
+			LIMIT 1
+		) p ON TRUE
+		CROSS JOIN LATERAL (SELECT COALESCE(p.price, s.price) AS price) lp
+		LEFT JOIN categories tc ON tc.id = s.category_id
+		LEFT JOIN LATERAL (
+			SELECT d.kind, d.value FROM subscription_discounts d
+			WHERE d.subscription_id = s.id
+			  AND d.valid_from <= m.m
+			  AND (d.valid_until IS NULL OR d.valid_until >= m.m)
+			LIMIT 1
+		) d ON TRUE
+		WHERE ($4::text IS NULL OR s.service_name = $4)
+		  AND ` + categoryMatches("$5") + `
+		  AND ($6::text[] IS NULL OR s.tags @> $6)
+	),
+	member_shares AS (
+		SELECT c.month, c.id, sm.user_id, c.service_name, c.category_id, c.payment_method_id, c.tax_rate, c.tax_inclusive,
+			CASE c.split_rule
+				WHEN 'equal' THEN c.amount / (t.members + 1)
+				WHEN 'percentage' THEN c.amount::bigint * sm.share / 100
+				WHEN 'fixed' THEN CASE WHEN t.fixed > c.amount THEN c.amount::bigint * sm.share / t.fixed ELSE sm.share END
+				ELSE 0
+			END AS amount
+		FROM charges c
+		JOIN subscription_members sm ON sm.subscription_id = c.id
+		CROSS JOIN LATERAL (
+			SELECT COUNT(*) AS members, SUM(x.share) AS fixed
+			FROM subscription_members x
+			WHERE x.subscription_id = c.id
+		) t
+	),
+	shares AS (
+		SELECT c.month, c.user_id, c.service_name, c.category_id, c.payment_method_id, c.tax_rate, c.tax_inclusive,
+			c.amount - COALESCE((
+				SELECT SUM(ms.amount) FROM member_shares ms WHERE ms.id = c.id AND ms.month = c.month
+			), 0) AS amount
+		FROM charges c
+		UNION ALL
+		SELECT ms.month, ms.user_id, ms.service_name, ms.category_id, ms.payment_method_id, ms.tax_rate, ms.tax_inclusive, ms.amount
+		FROM member_shares ms
+	)
+	SELECT sh.month, sh.amount::int AS amount, sh.user_id, sh.service_name, sh.category_id, sh.payment_method_id, n.net, t.tax
+	FROM shares sh
+	CROSS JOIN LATERAL (
+		SELECT CASE
+			WHEN sh.tax_inclusive THEN (sh.amount::bigint * 20000 + 10000 + sh.tax_rate) / (20000 + 2 * sh.tax_rate)
+			ELSE sh.amount::bigint
+		END AS net
+	) n
+	CROSS JOIN LATERAL (
+		SELECT CASE
+			WHEN sh.tax_inclusive THEN sh.amount::bigint - n.net
+			ELSE (sh.amount::bigint * sh.tax_rate + 5000) / 10000
+		END AS tax
+	) t
+	WHERE $3::uuid IS NULL OR sh.user_id = $3`
+
+func summaryArgs(filter usecase.SummaryFilter) []any {
+	return []any{filter.Start, filter.End, filter.UserID, filter.ServiceName, filter.Category, filter.Tags}
+}
+
+// Summary charges every month in the range with the price that was in effect
+// in that month.
+func (r *SubscriptionRepository) Summary(ctx context.Context, filter usecase.SummaryFilter) (usecase.SummaryTotals, error) {
+	query := `
+		SELECT COALESCE(SUM(c.amount), 0), COALESCE(SUM(c.net), 0)::bigint, COALESCE(SUM(c.tax), 0)::bigint
+		FROM (` + monthlyCharges + `) c`
+
+	var t usecase.SummaryTotals
+	if err := conn(ctx, r.pool).QueryRow(ctx, query, summaryArgs(filter)...).Scan(&t.Total, &t.Net, &t.Tax); err != nil {
+		return usecase.SummaryTotals{}, fmt.Errorf("repo SummarySubscriptions: %w", err)
+	}
+	t.Gross = t.Net + t.Tax
+	return t, nil
+}
+
+// MonthlySummary is Summary broken down by month. Every month of the range is
+// returned, months without charges with a zero total.
+func (r *SubscriptionRepository) MonthlySummary(ctx context.Context, filter usecase.SummaryFilter) ([]usecase.MonthlyTotal, error) {
+	query := `
+		SELECT m.m, COALESCE(SUM(c.amount), 0)
+		FROM generate_series($1::date, $2::date, interval '1 month') AS m(m)
+		LEFT JOIN (` + monthlyCharges + `) c ON c.month = m.m
+		GROUP BY m.m
+		ORDER BY m.m
+	`
+
+	rows, err := conn(ctx, r.pool).Query(ctx, query, summaryArgs(filter)...)
+	if err != nil {
+		return nil, fmt.Errorf("repo MonthlySummary: %w", err)
+	}
+	defer rows.Close()
+
+	res := make([]usecase.MonthlyTotal, 0)
+	for rows.Next() {
+		var t usecase.MonthlyTotal
+		if err := rows.Scan(&t.Month, &t.Total); err != nil {
+			return nil, fmt.Errorf("repo MonthlySummary: %w", err)
+		}
+		res = append(res, t)
+	}
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("repo MonthlySummary: %w", rows.Err())
+	}
+	return res, nil
+}
+
+// MonthlySummaryByUser is MonthlySummary broken down by the user charged.
+// Only months with charges are returned, ordered by user and month.
+func (r *SubscriptionRepository) MonthlySummaryByUser(ctx context.Context, filter usecase.SummaryFilter) ([]usecase.MonthlyGroupTotal, error) {
+	query := `
+		SELECT c.user_id::text, c.month, SUM(c.amount)
+		FROM (` + monthlyCharges + `) c
+		GROUP BY c.user_id, c.month
+		ORDER BY c.user_id, c.month
+	`
+	return r.monthlyGroups(ctx, "MonthlySummaryByUser", query, filter)
+}
+
+// MonthlySummaryByService is MonthlySummary broken down by service name. Only
+// months with charges are returned, ordered by service and month.
+func (r *SubscriptionRepository) MonthlySummaryByService(ctx context.Context, filter usecase.SummaryFilter) ([]usecase.MonthlyGroupTotal, error) {
+	query := `
+		SELECT c.service_name, c.month, SUM(c.amount)
+		FROM (` + monthlyCharges + `) c
+		GROUP BY c.service_name, c.month
+		ORDER BY c.service_name, c.month
+	`
+	return r.monthlyGroups(ctx, "MonthlySummaryByService", query, filter)
+}
+
+func (r *SubscriptionRepository) monthlyGroups(ctx context.Context, op, query string, filter usecase.SummaryFilter) ([]usecase.MonthlyGroupTotal, error) {
+	rows, err := conn(ctx, r.pool).Query(ctx, query, summaryArgs(filter)...)
+	if err != nil {
+		return nil, fmt.Errorf("repo %s: %w", op, err)
+	}
+	defer rows.Close()
+
+	res := make([]usecase.MonthlyGroupTotal, 0)
+	for rows.Next() {
+		var t usecase.MonthlyGroupTotal
+		if err := rows.Scan(&t.Key, &t.Month, &t.Total); err != nil {
+			return nil, fmt.Errorf("repo %s: %w", op, err)
+		}
+		res = append(res, t)
+	}
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("repo %s: %w", op, rows.Err())
+	}
+	return res, nil
+}
+
+// SummaryByCategory is Summary broken down by category. Subscriptions without
+// a category form a group with a nil ID.
+func (r *SubscriptionRepository) SummaryByCategory(ctx context.Context, filter usecase.SummaryFilter) ([]usecase.SummaryGroup, error) {
+	query := `
+		SELECT c.category_id, COALESCE(cat.name, ''), SUM(c.amount) AS total, SUM(c.net)::bigint, SUM(c.tax)::bigint
+		FROM (` + monthlyCharges + `) c
+		LEFT JOIN categories cat ON cat.id = c.category_id
+		GROUP BY c.category_id, cat.name
+		ORDER BY total DESC, cat.name
+	`
+
+	rows, err := conn(ctx, r.pool).Query(ctx, query, summaryArgs(filter)...)
+	if err != nil {
+		return nil, fmt.Errorf("repo SummaryByCategory: %w", err)
+	}
+	defer rows.Close()
+
+	res := make([]usecase.SummaryGroup, 0)
+	for rows.Next() {
+		var g usecase.SummaryGroup
+		if err := rows.Scan(&g.ID, &g.Name, &g.Total, &g.Net, &g.Tax); err != nil {
+			return nil, fmt.Errorf("repo SummaryByCategory: %w", err)
+		}
+		g.Gross = g.Net + g.Tax
+		res = append(res, g)
+	}
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("repo SummaryByCategory: %w", rows.Err())
+	}
+	return res, nil
+}
+
+// SummaryByPaymentMethod is Summary broken down by payment method, named by
+// label. Subscriptions without a payment method form a group with a nil ID.
+func (r *SubscriptionRepository) SummaryByPaymentMethod(ctx context.Context, filter usecase.SummaryFilter) ([]usecase.SummaryGroup, error) {
+	query := `
+		SELECT c.payment_method_id, COALESCE(pm.label, ''), SUM(c.amount) AS total, SUM(c.net)::bigint, SUM(c.tax)::bigint
+		FROM (` + monthlyCharges + `) c
+		LEFT JOIN payment_methods pm ON pm.id = c.payment_method_id
+		GROUP BY c.payment_method_id, pm.label
+		ORDER BY total DESC, pm.label
 	`
 
-	var total int64
-	if err := r.pool.QueryRow(ctx, query, filter.Start, filter.End, filter.UserID, filter.ServiceName).Scan(&total); err != nil {
-		return 0, fmt.Errorf("repo SummarySubscriptions: %w", err)
+	rows, err := conn(ctx, r.pool).Query(ctx, query, summaryArgs(filter)...)
+	if err != nil {
+		return nil, fmt.Errorf("repo SummaryByPaymentMethod: %w", err)
+	}
+	defer rows.Close()
+
+	res := make([]usecase.SummaryGroup, 0)
+	for rows.Next() {
+		var g usecase.SummaryGroup
+		if err := rows.Scan(&g.ID, &g.Name, &g.Total, &g.Net, &g.Tax); err != nil {
+			return nil, fmt.Errorf("repo SummaryByPaymentMethod: %w", err)
+		}
+		g.Gross = g.Net + g.Tax
+		res = append(res, g)
+	}
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("repo SummaryByPaymentMethod: %w", rows.Err())
 	}
-	return total, nil
+	return res, nil
 }
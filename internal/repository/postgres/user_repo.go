@@ -0,0 +1,155 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/always-tired/crud-subscriptions/internal/domain"
+	"github.com/always-tired/crud-subscriptions/internal/usecase"
+)
+
+const userColumns = `u.id, u.name, COALESCE(u.email, ''), u.currency, u.timezone, u.created_at, u.updated_at`
+
+func scanUser(row pgx.Row) (domain.User, error) {
+	var u domain.User
+	err := row.Scan(&u.ID, &u.Name, &u.Email, &u.Currency, &u.Timezone, &u.CreatedAt, &u.UpdatedAt)
+	return u, err
+}
+
+type UserRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewUserRepository(pool *pgxpool.Pool) *UserRepository {
+	return &UserRepository{pool: pool}
+}
+
+func (r *UserRepository) CreateUser(ctx context.Context, u domain.User) (domain.User, error) {
+	query := `
+		INSERT INTO users AS u (id, name, email, currency, timezone, created_at, updated_at)
+		VALUES ($1, $2, NULLIF($3, ''), $4, $5, $6, $7)
+		RETURNING ` + userColumns
+
+	created, err := scanUser(conn(ctx, r.pool).QueryRow(ctx, query,
+		u.ID, u.Name, u.Email, u.Currency, u.Timezone, u.CreatedAt, u.UpdatedAt,
+	))
+	if err != nil {
+		if err := mapWriteError(err); err != nil {
+			return domain.User{}, err
+		}
+		return domain.User{}, fmt.Errorf("repo CreateUser: %w", err)
+	}
+	return created, nil
+}
+
+func (r *UserRepository) GetUser(ctx context.Context, id uuid.UUID) (domain.User, error) {
+	query := `SELECT ` + userColumns + ` FROM users u WHERE u.id = $1`
+
+	u, err := scanUser(conn(ctx, r.pool).QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.User{}, domain.ErrNotFound
+		}
+		return domain.User{}, fmt.Errorf("repo GetUser: %w", err)
+	}
+	return u, nil
+}
+
+func (r *UserRepository) ListUsers(ctx context.Context, filter usecase.UserFilter) ([]domain.User, error) {
+	query := `
+		SELECT ` + userColumns + `
+		FROM users u
+		WHERE ($1::text IS NULL OR lower(u.email) = lower($1))
+		ORDER BY u.created_at DESC, u.id
+		LIMIT $2 OFFSET $3
+	`
+
+	limit := filter.Limit
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+	offset := filter.Offset
+	if offset < 0 {
+		offset = 0
+	}
+
+	rows, err := conn(ctx, r.pool).Query(ctx, query, filter.Email, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("repo ListUsers: %w", err)
+	}
+	defer rows.Close()
+
+	res := make([]domain.User, 0)
+	for rows.Next() {
+		u, err := scanUser(rows)
+		if err != nil {
+			return nil, fmt.Errorf("repo ListUsers: %w", err)
+		}
+		res = append(res, u)
+	}
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("repo ListUsers: %w", rows.Err())
+	}
+	return res, nil
+}
+
+func (r *UserRepository) UpdateUser(ctx context.Context, u domain.User) (domain.User, error) {
+	query := `
+		UPDATE users AS u
+		SET name = $2,
+			email = NULLIF($3, ''),
+			currency = $4,
+			timezone = $5,
+			updated_at = $6
+		WHERE u.id = $1
+		RETURNING ` + userColumns
+
+	updated, err := scanUser(conn(ctx, r.pool).QueryRow(ctx, query, u.ID, u.Name, u.Email, u.Currency, u.Timezone, u.UpdatedAt))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.User{}, domain.ErrNotFound
+		}
+		if err := mapWriteError(err); err != nil {
+			return domain.User{}, err
+		}
+		return domain.User{}, fmt.Errorf("repo UpdateUser: %w", err)
+	}
+	return updated, nil
+}
+
+// DeleteUser removes the user. Subscriptions keep their owner, so it fails
+// with ErrConflict while the user owns any.
+func (r *UserRepository) DeleteUser(ctx context.Context, id uuid.UUID) error {
+	cmd, err := conn(ctx, r.pool).Exec(ctx, `DELETE FROM users WHERE id = $1`, id)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == foreignKeyViolation {
+			return fmt.Errorf("%w: user owns subscriptions", domain.ErrConflict)
+		}
+		return fmt.Errorf("repo DeleteUser: %w", err)
+	}
+	if cmd.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+func (r *UserRepository) EnsureUsers(ctx context.Context, ids []uuid.UUID, at time.Time) error {
+	query := `
+		INSERT INTO users (id, created_at, updated_at)
+		SELECT unnest($1::uuid[]), $2, $2
+		ON CONFLICT (id) DO NOTHING
+	`
+
+	if _, err := conn(ctx, r.pool).Exec(ctx, query, ids, at); err != nil {
+		return fmt.Errorf("repo EnsureUsers: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,369 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/always-tired/crud-subscriptions/internal/domain"
+	"github.com/always-tired/crud-subscriptions/internal/usecase"
+)
+
+const webhookColumns = `w.id, w.url, w.secret, w.events, w.active, w.created_at, w.updated_at`
+
+const deliveryColumns = `
+	d.id, d.webhook_id, d.event_id, o.event_type, d.status, d.attempts, d.next_attempt_at,
+	d.last_status_code, d.last_error, d.created_at, d.updated_at`
+
+func scanWebhook(row pgx.Row) (domain.Webhook, error) {
+	var w domain.Webhook
+	err := row.Scan(&w.ID, &w.URL, &w.Secret, &w.Events, &w.Active, &w.CreatedAt, &w.UpdatedAt)
+	return w, err
+}
+
+func scanDelivery(row pgx.Row) (domain.WebhookDelivery, error) {
+	var d domain.WebhookDelivery
+	err := row.Scan(
+		&d.ID,
+		&d.WebhookID,
+		&d.EventID,
+		&d.EventType,
+		&d.Status,
+		&d.Attempts,
+		&d.NextAttemptAt,
+		&d.LastStatusCode,
+		&d.LastError,
+		&d.CreatedAt,
+		&d.UpdatedAt,
+	)
+	return d, err
+}
+
+type WebhookRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewWebhookRepository(pool *pgxpool.Pool) *WebhookRepository {
+	return &WebhookRepository{pool: pool}
+}
+
+func (r *WebhookRepository) CreateWebhook(ctx context.Context, w domain.Webhook) (domain.Webhook, error) {
+	query := `
+		INSERT INTO webhooks AS w (id, url, secret, events, active)
+		VALUES ($1, $2, $3, COALESCE($4::text[], '{}'), $5)
+		RETURNING ` + webhookColumns
+
+	created, err := scanWebhook(conn(ctx, r.pool).QueryRow(ctx, query, w.ID, w.URL, w.Secret, w.Events, w.Active))
+	if err != nil {
+		return domain.Webhook{}, fmt.Errorf("repo CreateWebhook: %w", err)
+	}
+	return created, nil
+}
+
+func (r *WebhookRepository) GetWebhook(ctx context.Context, id uuid.UUID) (domain.Webhook, error) {
+	query := `SELECT ` + webhookColumns + ` FROM webhooks w WHERE w.id = $1`
+
+	w, err := scanWebhook(conn(ctx, r.pool).QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.Webhook{}, domain.ErrNotFound
+		}
+		return domain.Webhook{}, fmt.Errorf("repo GetWebhook: %w", err)
+	}
+	return w, nil
+}
+
+func (r *WebhookRepository) ListWebhooks(ctx context.Context) ([]domain.Webhook, error) {
+	return r.listWebhooks(ctx, `SELECT `+webhookColumns+` FROM webhooks w ORDER BY w.created_at`)
+}
+
+func (r *WebhookRepository) ListWebhooksForEvent(ctx context.Context, eventType string) ([]domain.Webhook, error) {
+	query := `
+		SELECT ` + webhookColumns + `
+		FROM webhooks w
+		WHERE w.active
+		  AND (cardinality(w.events) = 0 OR $1 = ANY(w.events))
+		ORDER BY w.created_at
+	`
+	return r.listWebhooks(ctx, query, eventType)
+}
+
+func (r *WebhookRepository) listWebhooks(ctx context.Context, query string, args ...any) ([]domain.Webhook, error) {
+	rows, err := conn(ctx, r.pool).Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("repo ListWebhooks: %w", err)
+	}
+	defer rows.Close()
+
+	res := make([]domain.Webhook, 0)
+	for rows.Next() {
+		w, err := scanWebhook(rows)
+		if err != nil {
+			return nil, fmt.Errorf("repo ListWebhooks: %w", err)
+		}
+		res = append(res, w)
+	}
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("repo ListWebhooks: %w", rows.Err())
+	}
+	return res, nil
+}
+
+func (r *WebhookRepository) UpdateWebhook(ctx context.Context, w domain.Webhook) (domain.Webhook, error) {
+	query := `
+		UPDATE webhooks AS w
+		SET url = $2,
+			secret = $3,
+			events = COALESCE($4::text[], '{}'),
+			active = $5,
+			updated_at = NOW()
+		WHERE w.id = $1
+		RETURNING ` + webhookColumns
+
+	updated, err := scanWebhook(conn(ctx, r.pool).QueryRow(ctx, query, w.ID, w.URL, w.Secret, w.Events, w.Active))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.Webhook{}, domain.ErrNotFound
+		}
+		return domain.Webhook{}, fmt.Errorf("repo UpdateWebhook: %w", err)
+	}
+	return updated, nil
+}
+
+func (r *WebhookRepository) DeleteWebhook(ctx context.Context, id uuid.UUID) error {
+	cmd, err := conn(ctx, r.pool).Exec(ctx, `DELETE FROM webhooks WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("repo DeleteWebhook: %w", err)
+	}
+	if cmd.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+func (r *WebhookRepository) CreateDeliveries(ctx context.Context, deliveries []domain.WebhookDelivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+
+	query := `
+		INSERT INTO webhook_deliveries (id, webhook_id, event_id, status, next_attempt_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (webhook_id, event_id) DO NOTHING
+	`
+
+	batch := &pgx.Batch{}
+	for _, d := range deliveries {
+		batch.Queue(query, d.ID, d.WebhookID, d.EventID, d.Status, d.NextAttemptAt)
+	}
+	if err := conn(ctx, r.pool).SendBatch(ctx, batch).Close(); err != nil {
+		return fmt.Errorf("repo CreateDeliveries: %w", err)
+	}
+	return nil
+}
+
+func (r *WebhookRepository) FetchDueDeliveries(ctx context.Context, now time.Time, limit int) ([]usecase.DueDelivery, error) {
+	query := `
+		SELECT ` + deliveryColumns + `, ` + webhookColumns + `,
+			o.id, o.event_type, o.subscription_id, o.user_id, o.payload, COALESCE(o.dedupe_key, ''), o.created_at
+		FROM webhook_deliveries d
+		JOIN webhooks w ON w.id = d.webhook_id
+		JOIN outbox o ON o.id = d.event_id
+		WHERE d.status IN ('pending', 'retrying')
+		  AND d.next_attempt_at <= $1
+		  AND w.active
+		ORDER BY d.next_attempt_at, o.created_at
+		LIMIT $2
+		FOR UPDATE OF d SKIP LOCKED
+	`
+
+	rows, err := conn(ctx, r.pool).Query(ctx, query, now, limit)
+	if err != nil {
+		return nil, fmt.Errorf("repo FetchDueDeliveries: %w", err)
+	}
+	defer rows.Close()
+
+	res := make([]usecase.DueDelivery, 0)
+	for rows.Next() {
+		var dd usecase.DueDelivery
+		d, w, e := &dd.Delivery, &dd.Webhook, &dd.Event
+		var payload []byte
+		if err := rows.Scan(
+			&d.ID, &d.WebhookID, &d.EventID, &d.EventType, &d.Status, &d.Attempts, &d.NextAttemptAt,
+			&d.LastStatusCode, &d.LastError, &d.CreatedAt, &d.UpdatedAt,
+			&w.ID, &w.URL, &w.Secret, &w.Events, &w.Active, &w.CreatedAt, &w.UpdatedAt,
+			&e.ID, &e.Type, &e.SubscriptionID, &e.UserID, &payload, &e.DedupeKey, &e.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("repo FetchDueDeliveries: %w", err)
+		}
+		e.Payload = payload
+		res = append(res, dd)
+	}
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("repo FetchDueDeliveries: %w", rows.Err())
+	}
+	return res, nil
+}
+
+func (r *WebhookRepository) LeaseDeliveries(ctx context.Context, ids []uuid.UUID, until time.Time) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	if _, err := conn(ctx, r.pool).Exec(ctx,
+		`UPDATE webhook_deliveries SET next_attempt_at = $2 WHERE id = ANY($1)`, ids, until,
+	); err != nil {
+		return fmt.Errorf("repo LeaseDeliveries: %w", err)
+	}
+	return nil
+}
+
+func (r *WebhookRepository) SaveAttempt(ctx context.Context, d domain.WebhookDelivery, a domain.WebhookAttempt) error {
+	attemptQuery := `
+		INSERT INTO webhook_attempts (id, delivery_id, attempted_at, status_code, error, duration_ms)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+	deliveryQuery := `
+		UPDATE webhook_deliveries
+		SET status = $2,
+			attempts = $3,
+			next_attempt_at = $4,
+			last_status_code = $5,
+			last_error = $6,
+			updated_at = NOW()
+		WHERE id = $1
+	`
+
+	q := conn(ctx, r.pool)
+	if _, err := q.Exec(ctx, attemptQuery,
+		a.ID, a.DeliveryID, a.AttemptedAt, a.StatusCode, a.Error, a.Duration.Milliseconds(),
+	); err != nil {
+		return fmt.Errorf("repo SaveAttempt: %w", err)
+	}
+	if _, err := q.Exec(ctx, deliveryQuery,
+		d.ID, d.Status, d.Attempts, d.NextAttemptAt, d.LastStatusCode, d.LastError,
+	); err != nil {
+		return fmt.Errorf("repo SaveAttempt: %w", err)
+	}
+	return nil
+}
+
+func (r *WebhookRepository) ListDeliveries(ctx context.Context, filter usecase.DeliveryFilter) ([]domain.WebhookDelivery, error) {
+	query := `
+		SELECT ` + deliveryColumns + `
+		FROM webhook_deliveries d
+		JOIN outbox o ON o.id = d.event_id
+		WHERE d.webhook_id = $1
+		  AND ($2::text IS NULL OR d.status = $2)
+		ORDER BY d.created_at DESC
+		LIMIT $3 OFFSET $4
+	`
+
+	limit := filter.Limit
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+	offset := filter.Offset
+	if offset < 0 {
+		offset = 0
+	}
+
+	rows, err := conn(ctx, r.pool).Query(ctx, query, filter.WebhookID, filter.Status, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("repo ListDeliveries: %w", err)
+	}
+	defer rows.Close()
+
+	res := make([]domain.WebhookDelivery, 0)
+	for rows.Next() {
+		d, err := scanDelivery(rows)
+		if err != nil {
+			return nil, fmt.Errorf("repo ListDeliveries: %w", err)
+		}
+		res = append(res, d)
+	}
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("repo ListDeliveries: %w", rows.Err())
+	}
+	return res, nil
+}
+
+func (r *WebhookRepository) GetDelivery(ctx context.Context, webhookID, id uuid.UUID) (domain.WebhookDelivery, error) {
+	query := `
+		SELECT ` + deliveryColumns + `
+		FROM webhook_deliveries d
+		JOIN outbox o ON o.id = d.event_id
+		WHERE d.webhook_id = $1
+		  AND d.id = $2
+	`
+
+	d, err := scanDelivery(conn(ctx, r.pool).QueryRow(ctx, query, webhookID, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.WebhookDelivery{}, domain.ErrNotFound
+		}
+		return domain.WebhookDelivery{}, fmt.Errorf("repo GetDelivery: %w", err)
+	}
+	return d, nil
+}
+
+func (r *WebhookRepository) ListAttempts(ctx context.Context, deliveryID uuid.UUID) ([]domain.WebhookAttempt, error) {
+	query := `
+		SELECT id, delivery_id, attempted_at, status_code, error, duration_ms
+		FROM webhook_attempts
+		WHERE delivery_id = $1
+		ORDER BY attempted_at
+	`
+
+	rows, err := conn(ctx, r.pool).Query(ctx, query, deliveryID)
+	if err != nil {
+		return nil, fmt.Errorf("repo ListAttempts: %w", err)
+	}
+	defer rows.Close()
+
+	res := make([]domain.WebhookAttempt, 0)
+	for rows.Next() {
+		var a domain.WebhookAttempt
+		var ms int64
+		if err := rows.Scan(&a.ID, &a.DeliveryID, &a.AttemptedAt, &a.StatusCode, &a.Error, &ms); err != nil {
+			return nil, fmt.Errorf("repo ListAttempts: %w", err)
+		}
+		a.Duration = time.Duration(ms) * time.Millisecond
+		res = append(res, a)
+	}
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("repo ListAttempts: %w", rows.Err())
+	}
+	return res, nil
+}
+
+func (r *WebhookRepository) ResetDelivery(ctx context.Context, webhookID, id uuid.UUID, at time.Time) (domain.WebhookDelivery, error) {
+	query := `
+		WITH d AS (
+			UPDATE webhook_deliveries
+			SET status = 'pending',
+				attempts = 0,
+				next_attempt_at = $3,
+				updated_at = NOW()
+			WHERE webhook_id = $1
+			  AND id = $2
+			RETURNING *
+		)
+		SELECT ` + deliveryColumns + `
+		FROM d
+		JOIN outbox o ON o.id = d.event_id
+	`
+
+	d, err := scanDelivery(conn(ctx, r.pool).QueryRow(ctx, query, webhookID, id, at))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.WebhookDelivery{}, domain.ErrNotFound
+		}
+		return domain.WebhookDelivery{}, fmt.Errorf("repo ResetDelivery: %w", err)
+	}
+	return d, nil
+}
@@ -0,0 +1,114 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/always-tired/crud-subscriptions/internal/domain"
+	"github.com/always-tired/crud-subscriptions/internal/usecase"
+)
+
+// @Summary Subscription change history
+// @Tags audit
+// @Produce json
+// @Param id path string true "subscription id" format(uuid)
+// @Param limit query int false "limit"
+// @Param offset query int false "offset"
+// @Success 200 {array} auditEntryResponse
+// @Failure 400 {object} errorResponse
+// @Router /subscriptions/{id}/history [get]
+func (h *Handler) subscriptionHistory(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	limit, offset := parsePage(r)
+	list, err := h.service.History(r.Context(), id, limit, offset)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, auditToResponse(list))
+}
+
+// @Summary Query audit log
+// @Tags audit
+// @Produce json
+// @Param actor query string false "actor"
+// @Param from query string false "from (RFC 3339)" format(date-time)
+// @Param to query string false "to (RFC 3339)" format(date-time)
+// @Param limit query int false "limit"
+// @Param offset query int false "offset"
+// @Success 200 {array} auditEntryResponse
+// @Failure 400 {object} errorResponse
+// @Router /audit [get]
+func (h *Handler) listAudit(w http.ResponseWriter, r *http.Request) {
+	var filter usecase.AuditFilter
+
+	if v := r.URL.Query().Get("actor"); v != "" {
+		filter.Actor = &v
+	}
+	if v := r.URL.Query().Get("from"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid from")
+			return
+		}
+		filter.From = &t
+	}
+	if v := r.URL.Query().Get("to"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid to")
+			return
+		}
+		filter.To = &t
+	}
+	filter.Limit, filter.Offset = parsePage(r)
+
+	list, err := h.service.Audit(r.Context(), filter)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, auditToResponse(list))
+}
+
+func parsePage(r *http.Request) (limit, offset int) {
+	if v := r.URL.Query().Get("limit"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil {
+			limit = n
+		}
+	}
+	if v := r.URL.Query().Get("offset"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil {
+			offset = n
+		}
+	}
+	return limit, offset
+}
+
+func auditToResponse(list []domain.AuditEntry) []auditEntryResponse {
+	resp := make([]auditEntryResponse, 0, len(list))
+	for _, e := range list {
+		resp = append(resp, auditEntryResponse{
+			ID:             e.ID.String(),
+			SubscriptionID: e.SubscriptionID.String(),
+			Action:         e.Action,
+			Actor:          e.Actor,
+			RequestID:      e.RequestID,
+			Before:         e.Before,
+			After:          e.After,
+			CreatedAt:      e.CreatedAt.UTC().Format(time.RFC3339),
+		})
+	}
+	return resp
+}
@@ -0,0 +1,222 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/always-tired/crud-subscriptions/internal/domain"
+	"github.com/always-tired/crud-subscriptions/internal/usecase"
+)
+
+// @Summary Create budget
+// @Tags budgets
+// @Accept json
+// @Produce json
+// @Param budget body budgetRequest true "budget"
+// @Success 201 {object} budgetResponse
+// @Failure 400 {object} errorResponse
+// @Failure 409 {object} errorResponse
+// @Router /budgets [post]
+func (h *Handler) createBudget(w http.ResponseWriter, r *http.Request) {
+	var req budgetRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid json")
+		return
+	}
+
+	b, err := h.service.CreateBudget(r.Context(), req.toInput())
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, budgetToResponse(b))
+}
+
+// @Summary List budgets
+// @Tags budgets
+// @Produce json
+// @Param user_id query string false "user id" format(uuid)
+// @Success 200 {array} budgetResponse
+// @Failure 400 {object} errorResponse
+// @Router /budgets [get]
+func (h *Handler) listBudgets(w http.ResponseWriter, r *http.Request) {
+	var userID *uuid.UUID
+	if v := r.URL.Query().Get("user_id"); v != "" {
+		uid, err := uuid.Parse(v)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid user_id")
+			return
+		}
+		userID = &uid
+	}
+
+	list, err := h.service.ListBudgets(r.Context(), userID)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	resp := make([]budgetResponse, 0, len(list))
+	for _, b := range list {
+		resp = append(resp, budgetToResponse(b))
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// @Summary Get budget
+// @Tags budgets
+// @Produce json
+// @Param id path string true "budget id" format(uuid)
+// @Success 200 {object} budgetResponse
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Router /budgets/{id} [get]
+func (h *Handler) getBudget(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	b, err := h.service.GetBudget(r.Context(), id)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, budgetToResponse(b))
+}
+
+// @Summary Update budget
+// @Tags budgets
+// @Accept json
+// @Produce json
+// @Param id path string true "budget id" format(uuid)
+// @Param budget body budgetRequest true "budget"
+// @Success 200 {object} budgetResponse
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 409 {object} errorResponse
+// @Router /budgets/{id} [put]
+func (h *Handler) updateBudget(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	var req budgetRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid json")
+		return
+	}
+
+	b, err := h.service.UpdateBudget(r.Context(), id, req.toInput())
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, budgetToResponse(b))
+}
+
+// @Summary Delete budget
+// @Tags budgets
+// @Param id path string true "budget id" format(uuid)
+// @Success 204
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Router /budgets/{id} [delete]
+func (h *Handler) deleteBudget(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	if err := h.service.DeleteBudget(r.Context(), id); err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// @Summary Budget status
+// @Description Spend vs. limit of every budget of the user for every month from start to end, both defaulting to the current month.
+// @Tags budgets
+// @Produce json
+// @Param user_id path string true "user id" format(uuid)
+// @Param start query string false "MM-YYYY"
+// @Param end query string false "MM-YYYY"
+// @Success 200 {array} budgetStatusResponse
+// @Failure 400 {object} errorResponse
+// @Router /users/{user_id}/budget-status [get]
+func (h *Handler) budgetStatus(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(chi.URLParam(r, "user_id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid user_id")
+		return
+	}
+
+	var start, end time.Time
+	if v := r.URL.Query().Get("start"); v != "" {
+		if start, err = usecase.ParseMonthDate(v); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+	if v := r.URL.Query().Get("end"); v != "" {
+		if end, err = usecase.ParseMonthDate(v); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	list, err := h.service.BudgetStatus(r.Context(), userID, start, end)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	resp := make([]budgetStatusResponse, 0, len(list))
+	for _, st := range list {
+		months := make([]budgetMonthResponse, 0, len(st.Months))
+		for _, m := range st.Months {
+			months = append(months, budgetMonthResponse{
+				Month:     usecase.FormatMonthDate(m.Month),
+				Spent:     m.Spent,
+				Limit:     m.Limit,
+				Remaining: int64(m.Limit) - m.Spent,
+				Exceeded:  m.Exceeded,
+			})
+		}
+		resp = append(resp, budgetStatusResponse{
+			BudgetID:     st.Budget.ID.String(),
+			CategoryID:   uuidString(st.Budget.CategoryID),
+			MonthlyLimit: st.Budget.MonthlyLimit,
+			Months:       months,
+		})
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (req budgetRequest) toInput() usecase.BudgetInput {
+	return usecase.BudgetInput{UserID: req.UserID, CategoryID: req.CategoryID, MonthlyLimit: req.MonthlyLimit}
+}
+
+func budgetToResponse(b domain.Budget) budgetResponse {
+	return budgetResponse{
+		ID:           b.ID.String(),
+		UserID:       b.UserID.String(),
+		CategoryID:   uuidString(b.CategoryID),
+		MonthlyLimit: b.MonthlyLimit,
+		CreatedAt:    b.CreatedAt.UTC().Format(time.RFC3339),
+		UpdatedAt:    b.UpdatedAt.UTC().Format(time.RFC3339),
+	}
+}
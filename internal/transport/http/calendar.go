@@ -0,0 +1,103 @@
+package http
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// @Summary Issue renewal calendar token
+// @Description Creates a new feed token for the user and revokes the previous one.
+// @Tags calendar
+// @Produce json
+// @Param user_id path string true "user id" format(uuid)
+// @Success 201 {object} calendarTokenResponse
+// @Failure 400 {object} errorResponse
+// @Router /users/{user_id}/calendar-token [post]
+func (h *Handler) issueCalendarToken(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(chi.URLParam(r, "user_id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid user_id")
+		return
+	}
+
+	token, err := h.service.IssueCalendarToken(r.Context(), userID)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	feed := url.URL{
+		Scheme:   requestScheme(r),
+		Host:     r.Host,
+		Path:     "/users/" + userID.String() + "/renewals.ics",
+		RawQuery: url.Values{"token": {token}}.Encode(),
+	}
+	writeJSON(w, http.StatusCreated, calendarTokenResponse{Token: token, URL: feed.String()})
+}
+
+// @Summary Revoke renewal calendar token
+// @Tags calendar
+// @Param user_id path string true "user id" format(uuid)
+// @Success 204
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Router /users/{user_id}/calendar-token [delete]
+func (h *Handler) revokeCalendarToken(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(chi.URLParam(r, "user_id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid user_id")
+		return
+	}
+
+	if err := h.service.RevokeCalendarToken(r.Context(), userID); err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// @Summary iCalendar feed of renewals
+// @Tags calendar
+// @Produce text/calendar
+// @Param user_id path string true "user id" format(uuid)
+// @Param token query string true "feed token"
+// @Success 200 {string} string
+// @Failure 400 {object} errorResponse
+// @Failure 403 {object} errorResponse
+// @Router /users/{user_id}/renewals.ics [get]
+func (h *Handler) renewalCalendar(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(chi.URLParam(r, "user_id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid user_id")
+		return
+	}
+
+	subs, err := h.service.RenewalFeed(r.Context(), userID, r.URL.Query().Get("token"))
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	var b strings.Builder
+	writeRenewalCalendar(&b, subs)
+
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Content-Disposition", `inline; filename="renewals.ics"`)
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write([]byte(b.String()))
+}
+
+func requestScheme(r *http.Request) string {
+	if v := r.Header.Get("X-Forwarded-Proto"); v != "" {
+		return v
+	}
+	if r.TLS != nil {
+		return "https"
+	}
+	return "http"
+}
@@ -0,0 +1,69 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/always-tired/crud-subscriptions/internal/usecase"
+)
+
+// @Summary Cancel subscription
+// @Description `when` is `end_of_period` (default: the current month or the running trial is the last one), `immediately` (the previous month is the last one) or the last billed month as MM-YYYY.
+// @Tags subscriptions
+// @Accept json
+// @Produce json
+// @Param id path string true "subscription id" format(uuid)
+// @Param cancellation body cancelRequest true "cancellation"
+// @Success 200 {object} subscriptionResponse
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Router /subscriptions/{id}:cancel [post]
+func (h *Handler) cancelSubscription(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	var req cancelRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid json")
+		return
+	}
+
+	sub, err := h.service.Cancel(r.Context(), id, usecase.CancelInput{When: req.When, Reason: req.Reason})
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, domainToResponse(sub, h.service.Describe(sub)))
+}
+
+// @Summary Reactivate subscription
+// @Description Undoes a cancellation that has not taken effect yet.
+// @Tags subscriptions
+// @Produce json
+// @Param id path string true "subscription id" format(uuid)
+// @Success 200 {object} subscriptionResponse
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Router /subscriptions/{id}:reactivate [post]
+func (h *Handler) reactivateSubscription(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	sub, err := h.service.Reactivate(r.Context(), id)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, domainToResponse(sub, h.service.Describe(sub)))
+}
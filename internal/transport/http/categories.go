@@ -0,0 +1,152 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/always-tired/crud-subscriptions/internal/domain"
+	"github.com/always-tired/crud-subscriptions/internal/usecase"
+)
+
+// @Summary Create category
+// @Tags categories
+// @Accept json
+// @Produce json
+// @Param category body categoryRequest true "category"
+// @Success 201 {object} categoryResponse
+// @Failure 400 {object} errorResponse
+// @Failure 409 {object} errorResponse
+// @Router /categories [post]
+func (h *Handler) createCategory(w http.ResponseWriter, r *http.Request) {
+	var req categoryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid json")
+		return
+	}
+
+	c, err := h.service.CreateCategory(r.Context(), req.toInput())
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, categoryToResponse(c))
+}
+
+// @Summary List categories
+// @Tags categories
+// @Produce json
+// @Success 200 {array} categoryResponse
+// @Router /categories [get]
+func (h *Handler) listCategories(w http.ResponseWriter, r *http.Request) {
+	list, err := h.service.ListCategories(r.Context())
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	resp := make([]categoryResponse, 0, len(list))
+	for _, c := range list {
+		resp = append(resp, categoryToResponse(c))
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// @Summary Get category
+// @Tags categories
+// @Produce json
+// @Param id path string true "category id" format(uuid)
+// @Success 200 {object} categoryResponse
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Router /categories/{id} [get]
+func (h *Handler) getCategory(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	c, err := h.service.GetCategory(r.Context(), id)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, categoryToResponse(c))
+}
+
+// @Summary Update category
+// @Description Replaces the name and the tax rate; omitting tax_rate clears it.
+// @Tags categories
+// @Accept json
+// @Produce json
+// @Param id path string true "category id" format(uuid)
+// @Param category body categoryRequest true "category"
+// @Success 200 {object} categoryResponse
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 409 {object} errorResponse
+// @Router /categories/{id} [put]
+func (h *Handler) updateCategory(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	var req categoryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid json")
+		return
+	}
+
+	c, err := h.service.UpdateCategory(r.Context(), id, req.toInput())
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, categoryToResponse(c))
+}
+
+// @Summary Delete category
+// @Description Subscriptions in the category become uncategorized; budgets for it are deleted.
+// @Tags categories
+// @Param id path string true "category id" format(uuid)
+// @Success 204
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Router /categories/{id} [delete]
+func (h *Handler) deleteCategory(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	if err := h.service.DeleteCategory(r.Context(), id); err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (req categoryRequest) toInput() usecase.CategoryInput {
+	return usecase.CategoryInput{Name: req.Name, TaxRate: req.TaxRate}
+}
+
+func categoryToResponse(c domain.Category) categoryResponse {
+	return categoryResponse{
+		ID:        c.ID.String(),
+		Name:      c.Name,
+		TaxRate:   c.TaxRate,
+		CreatedAt: c.CreatedAt.UTC().Format(time.RFC3339),
+		UpdatedAt: c.UpdatedAt.UTC().Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,125 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/always-tired/crud-subscriptions/internal/domain"
+	"github.com/always-tired/crud-subscriptions/internal/usecase"
+)
+
+// @Summary List discounts
+// @Tags subscriptions
+// @Produce json
+// @Param id path string true "subscription id" format(uuid)
+// @Success 200 {array} discountResponse
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Router /subscriptions/{id}/discounts [get]
+func (h *Handler) listDiscounts(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	discounts, err := h.service.ListDiscounts(r.Context(), id)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, discountsToResponse(discounts))
+}
+
+// @Summary Add discount
+// @Description Lowers the price by a percentage or a fixed amount from from through until, open-ended without until, or over the first_months charged months. Discounts must not overlap.
+// @Tags subscriptions
+// @Accept json
+// @Produce json
+// @Param id path string true "subscription id" format(uuid)
+// @Param discount body discountRequest true "discount"
+// @Success 201 {object} discountResponse
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Router /subscriptions/{id}/discounts [post]
+func (h *Handler) addDiscount(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	var req discountRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid json")
+		return
+	}
+
+	d, err := h.service.AddDiscount(r.Context(), id, usecase.DiscountInput{
+		Kind:        req.Kind,
+		Value:       req.Value,
+		From:        req.From,
+		Until:       req.Until,
+		FirstMonths: req.FirstMonths,
+	})
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, discountToResponse(d))
+}
+
+// @Summary Remove discount
+// @Tags subscriptions
+// @Param id path string true "subscription id" format(uuid)
+// @Param discount_id path string true "discount id" format(uuid)
+// @Success 204
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Router /subscriptions/{id}/discounts/{discount_id} [delete]
+func (h *Handler) removeDiscount(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid id")
+		return
+	}
+	discountID, err := uuid.Parse(chi.URLParam(r, "discount_id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid discount_id")
+		return
+	}
+
+	if err := h.service.RemoveDiscount(r.Context(), id, discountID); err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func discountToResponse(d domain.Discount) discountResponse {
+	resp := discountResponse{
+		ID:    d.ID.String(),
+		Kind:  d.Kind,
+		Value: d.Value,
+		From:  usecase.FormatMonthDate(d.From),
+	}
+	if d.Until != nil {
+		u := usecase.FormatMonthDate(*d.Until)
+		resp.Until = &u
+	}
+	return resp
+}
+
+func discountsToResponse(discounts []domain.Discount) []discountResponse {
+	resp := make([]discountResponse, 0, len(discounts))
+	for _, d := range discounts {
+		resp = append(resp, discountToResponse(d))
+	}
+	return resp
+}
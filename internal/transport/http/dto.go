@@ -1,22 +1,353 @@
 package http
 
+import "encoding/json"
+
 type subscriptionRequest struct {
-	ServiceName string  `json:"service_name"`
-	Price       int     `json:"price"`
-	UserID      string  `json:"user_id"`
-	StartDate   string  `json:"start_date"`
-	EndDate     *string `json:"end_date,omitempty"`
+	ServiceName     string          `json:"service_name"`
+	Price           int             `json:"price"`
+	UserID          string          `json:"user_id"`
+	StartDate       string          `json:"start_date"`
+	EndDate         *string         `json:"end_date,omitempty"`
+	TrialEndDate    *string         `json:"trial_end_date,omitempty"`
+	TrialMonths     *int            `json:"trial_months,omitempty"`
+	CategoryID      *string         `json:"category_id,omitempty"`
+	Tags            []string        `json:"tags,omitempty"`
+	SplitRule       string          `json:"split_rule,omitempty"`
+	Members         []memberRequest `json:"members,omitempty"`
+	TaxRate         *int            `json:"tax_rate,omitempty"`
+	TaxInclusive    bool            `json:"tax_inclusive,omitempty"`
+	PaymentMethodID *string         `json:"payment_method_id,omitempty"`
+	Unused          bool            `json:"unused,omitempty"`
 }
 
 type subscriptionResponse struct {
-	ID          string  `json:"id"`
-	ServiceName string  `json:"service_name"`
-	Price       int     `json:"price"`
-	UserID      string  `json:"user_id"`
-	StartDate   string  `json:"start_date"`
-	EndDate     *string `json:"end_date,omitempty"`
-	CreatedAt   string  `json:"created_at"`
-	UpdatedAt   string  `json:"updated_at"`
+	ID              string             `json:"id"`
+	ServiceName     string             `json:"service_name"`
+	Price           int                `json:"price"`
+	EffectivePrice  int                `json:"effective_price"`
+	UserID          string             `json:"user_id"`
+	StartDate       string             `json:"start_date"`
+	EndDate         *string            `json:"end_date,omitempty"`
+	TrialEndDate    *string            `json:"trial_end_date,omitempty"`
+	CategoryID      *string            `json:"category_id,omitempty"`
+	Category        *string            `json:"category,omitempty"`
+	PaymentMethodID *string            `json:"payment_method_id,omitempty"`
+	PaymentMethod   *string            `json:"payment_method,omitempty"`
+	Tags            []string           `json:"tags"`
+	Pauses          []pauseResponse    `json:"pauses,omitempty"`
+	Discounts       []discountResponse `json:"discounts,omitempty"`
+	SplitRule       string             `json:"split_rule,omitempty"`
+	Members         []memberResponse   `json:"members,omitempty"`
+	Shares          []shareResponse    `json:"shares,omitempty"`
+	TaxRate         *int               `json:"tax_rate,omitempty"`
+	TaxInclusive    bool               `json:"tax_inclusive"`
+	Unused          bool               `json:"unused"`
+	Status          string             `json:"status"`
+	NextBillingDate *string            `json:"next_billing_date,omitempty"`
+	MonthsActive    int                `json:"months_active"`
+	LifetimeCost    int64              `json:"lifetime_cost"`
+	CancelReason    *string            `json:"cancel_reason,omitempty"`
+	CancelledAt     *string            `json:"cancelled_at,omitempty"`
+	CreatedAt       string             `json:"created_at"`
+	UpdatedAt       string             `json:"updated_at"`
+	DeletedAt       *string            `json:"deleted_at,omitempty"`
+}
+
+type memberRequest struct {
+	UserID string `json:"user_id"`
+	Share  int    `json:"share,omitempty"`
+}
+
+type memberResponse struct {
+	UserID string `json:"user_id"`
+	Share  int    `json:"share,omitempty"`
+}
+
+type shareResponse struct {
+	UserID string `json:"user_id"`
+	Amount int    `json:"amount"`
+}
+
+type pauseRequest struct {
+	From  *string `json:"from,omitempty"`
+	Until *string `json:"until,omitempty"`
+}
+
+type cancelRequest struct {
+	When   string `json:"when"`
+	Reason string `json:"reason"`
+}
+
+type resumeRequest struct {
+	From *string `json:"from,omitempty"`
+}
+
+type pauseResponse struct {
+	From  string  `json:"from"`
+	Until *string `json:"until,omitempty"`
+}
+
+type summaryResponse struct {
+	Total  int64                  `json:"total"`
+	Net    int64                  `json:"net"`
+	Tax    int64                  `json:"tax"`
+	Gross  int64                  `json:"gross"`
+	Groups []summaryGroupResponse `json:"groups,omitempty"`
+}
+
+type summaryGroupResponse struct {
+	ID    *string `json:"id"`
+	Name  *string `json:"name"`
+	Total int64   `json:"total"`
+	Net   int64   `json:"net"`
+	Tax   int64   `json:"tax"`
+	Gross int64   `json:"gross"`
+}
+
+type categoryRequest struct {
+	Name    string `json:"name"`
+	TaxRate *int   `json:"tax_rate,omitempty"`
+}
+
+type categoryResponse struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	TaxRate   *int   `json:"tax_rate,omitempty"`
+	CreatedAt string `json:"created_at"`
+	UpdatedAt string `json:"updated_at"`
+}
+
+type paymentMethodRequest struct {
+	Label  string  `json:"label"`
+	Type   string  `json:"type"`
+	Last4  string  `json:"last4,omitempty"`
+	Expiry *string `json:"expiry,omitempty"`
+}
+
+type paymentMethodResponse struct {
+	ID        string  `json:"id"`
+	Label     string  `json:"label"`
+	Type      string  `json:"type"`
+	Last4     string  `json:"last4,omitempty"`
+	Expiry    *string `json:"expiry,omitempty"`
+	CreatedAt string  `json:"created_at"`
+	UpdatedAt string  `json:"updated_at"`
+}
+
+type expiringPaymentMethodResponse struct {
+	PaymentMethod paymentMethodResponse  `json:"payment_method"`
+	Expired       bool                   `json:"expired"`
+	Subscriptions []subscriptionResponse `json:"subscriptions"`
+}
+
+type duplicateResponse struct {
+	Kind          string                 `json:"kind"`
+	ServiceName   string                 `json:"service_name"`
+	From          string                 `json:"from"`
+	Until         *string                `json:"until,omitempty"`
+	Subscriptions []subscriptionResponse `json:"subscriptions"`
+}
+
+type recommendationResponse struct {
+	Kind           string                 `json:"kind"`
+	ServiceName    string                 `json:"service_name"`
+	MonthlySavings int                    `json:"monthly_savings"`
+	Subscriptions  []subscriptionResponse `json:"subscriptions"`
+}
+
+type anomalyResponse struct {
+	Month           string `json:"month"`
+	Total           int64  `json:"total"`
+	TrailingAverage int64  `json:"trailing_average"`
+	ChangePercent   int64  `json:"change_percent"`
+}
+
+type userRequest struct {
+	Name     string `json:"name"`
+	Email    string `json:"email,omitempty"`
+	Currency string `json:"currency,omitempty"`
+	Timezone string `json:"timezone,omitempty"`
+}
+
+type userResponse struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	Email     string `json:"email,omitempty"`
+	Currency  string `json:"currency"`
+	Timezone  string `json:"timezone"`
+	CreatedAt string `json:"created_at"`
+	UpdatedAt string `json:"updated_at"`
+}
+
+type userOverviewResponse struct {
+	User         userResponse            `json:"user"`
+	Statuses     map[string]int          `json:"statuses"`
+	MonthlySpend int64                   `json:"monthly_spend"`
+	ByCategory   []summaryGroupResponse  `json:"by_category"`
+	YearlySpend  int64                   `json:"yearly_spend"`
+	NextCharge   *upcomingChargeResponse `json:"next_charge,omitempty"`
+}
+
+type upcomingChargeResponse struct {
+	SubscriptionID string `json:"subscription_id"`
+	ServiceName    string `json:"service_name"`
+	Date           string `json:"date"`
+	Amount         int    `json:"amount"`
+}
+
+type serviceRequest struct {
+	Name         string   `json:"name"`
+	Aliases      []string `json:"aliases,omitempty"`
+	DefaultPrice *int     `json:"default_price,omitempty"`
+	YearlyPrice  *int     `json:"yearly_price,omitempty"`
+	FamilyPrice  *int     `json:"family_price,omitempty"`
+	VendorURL    string   `json:"vendor_url,omitempty"`
+	CategoryID   *string  `json:"category_id,omitempty"`
+}
+
+type serviceResponse struct {
+	ID           string   `json:"id"`
+	Name         string   `json:"name"`
+	Aliases      []string `json:"aliases"`
+	DefaultPrice *int     `json:"default_price,omitempty"`
+	YearlyPrice  *int     `json:"yearly_price,omitempty"`
+	FamilyPrice  *int     `json:"family_price,omitempty"`
+	VendorURL    string   `json:"vendor_url,omitempty"`
+	CategoryID   *string  `json:"category_id,omitempty"`
+	CreatedAt    string   `json:"created_at"`
+	UpdatedAt    string   `json:"updated_at"`
+}
+
+type forecastResponse struct {
+	Months []forecastMonthResponse `json:"months"`
+	Total  int64                   `json:"total"`
+	Groups []forecastGroupResponse `json:"groups,omitempty"`
+}
+
+// forecastGroupResponse has user_id set when grouped by user and
+// service_name when grouped by service.
+type forecastGroupResponse struct {
+	UserID      string                  `json:"user_id,omitempty"`
+	ServiceName string                  `json:"service_name,omitempty"`
+	Months      []forecastMonthResponse `json:"months"`
+	Total       int64                   `json:"total"`
+}
+
+type forecastMonthResponse struct {
+	Month      string `json:"month"`
+	Total      int64  `json:"total"`
+	Cumulative int64  `json:"cumulative"`
+}
+
+type discountRequest struct {
+	Kind        string  `json:"kind"`
+	Value       int     `json:"value"`
+	From        *string `json:"from,omitempty"`
+	Until       *string `json:"until,omitempty"`
+	FirstMonths *int    `json:"first_months,omitempty"`
+}
+
+type discountResponse struct {
+	ID    string  `json:"id"`
+	Kind  string  `json:"kind"`
+	Value int     `json:"value"`
+	From  string  `json:"from"`
+	Until *string `json:"until,omitempty"`
+}
+
+type priceChangeRequest struct {
+	Price         int    `json:"price"`
+	EffectiveFrom string `json:"effective_from"`
+}
+
+type pricePeriodResponse struct {
+	EffectiveFrom string `json:"effective_from"`
+	Price         int    `json:"price"`
+}
+
+type auditEntryResponse struct {
+	ID             string          `json:"id"`
+	SubscriptionID string          `json:"subscription_id"`
+	Action         string          `json:"action"`
+	Actor          string          `json:"actor"`
+	RequestID      string          `json:"request_id"`
+	Before         json.RawMessage `json:"before,omitempty"`
+	After          json.RawMessage `json:"after,omitempty"`
+	CreatedAt      string          `json:"created_at"`
+}
+
+type webhookRequest struct {
+	URL    string   `json:"url"`
+	Secret string   `json:"secret,omitempty"`
+	Events []string `json:"events"`
+	Active *bool    `json:"active,omitempty"`
+}
+
+type webhookResponse struct {
+	ID        string   `json:"id"`
+	URL       string   `json:"url"`
+	Secret    string   `json:"secret,omitempty"`
+	Events    []string `json:"events"`
+	Active    bool     `json:"active"`
+	CreatedAt string   `json:"created_at"`
+	UpdatedAt string   `json:"updated_at"`
+}
+
+type deliveryResponse struct {
+	ID             string            `json:"id"`
+	WebhookID      string            `json:"webhook_id"`
+	EventID        string            `json:"event_id"`
+	EventType      string            `json:"event_type"`
+	Status         string            `json:"status"`
+	Attempts       int               `json:"attempts"`
+	NextAttemptAt  string            `json:"next_attempt_at"`
+	LastStatusCode *int              `json:"last_status_code,omitempty"`
+	LastError      *string           `json:"last_error,omitempty"`
+	CreatedAt      string            `json:"created_at"`
+	UpdatedAt      string            `json:"updated_at"`
+	AttemptLog     []attemptResponse `json:"attempt_log,omitempty"`
+}
+
+type attemptResponse struct {
+	AttemptedAt string  `json:"attempted_at"`
+	StatusCode  *int    `json:"status_code,omitempty"`
+	Error       *string `json:"error,omitempty"`
+	DurationMS  int64   `json:"duration_ms"`
+}
+
+type budgetRequest struct {
+	UserID       string  `json:"user_id"`
+	CategoryID   *string `json:"category_id,omitempty"`
+	MonthlyLimit int     `json:"monthly_limit"`
+}
+
+type budgetResponse struct {
+	ID           string  `json:"id"`
+	UserID       string  `json:"user_id"`
+	CategoryID   *string `json:"category_id,omitempty"`
+	MonthlyLimit int     `json:"monthly_limit"`
+	CreatedAt    string  `json:"created_at"`
+	UpdatedAt    string  `json:"updated_at"`
+}
+
+type budgetStatusResponse struct {
+	BudgetID     string                `json:"budget_id"`
+	CategoryID   *string               `json:"category_id,omitempty"`
+	MonthlyLimit int                   `json:"monthly_limit"`
+	Months       []budgetMonthResponse `json:"months"`
+}
+
+type budgetMonthResponse struct {
+	Month     string `json:"month"`
+	Spent     int64  `json:"spent"`
+	Limit     int    `json:"limit"`
+	Remaining int64  `json:"remaining"`
+	Exceeded  bool   `json:"exceeded"`
+}
+
+type calendarTokenResponse struct {
+	Token string `json:"token"`
+	URL   string `json:"url"`
 }
 
 type errorResponse struct {
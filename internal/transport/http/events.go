@@ -0,0 +1,137 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/always-tired/crud-subscriptions/internal/domain"
+	"github.com/always-tired/crud-subscriptions/internal/publisher"
+)
+
+const (
+	sseBatchSize     = 100
+	ssePollInterval  = 3 * time.Second
+	sseKeepAlive     = 15 * time.Second
+	sseRetryInterval = 3 * time.Second
+)
+
+// @Summary Stream subscription events
+// @Description Server-Sent Events stream of subscription changes. Resume with the Last-Event-ID header
+// @Description (or last_event_id query parameter) set to the id of the last event received.
+// @Tags subscriptions
+// @Produce text/event-stream
+// @Param user_id query string false "user id" format(uuid)
+// @Param last_event_id query string false "resume after this event id"
+// @Success 200 {string} string
+// @Failure 400 {object} errorResponse
+// @Router /subscriptions/events [get]
+func (h *Handler) streamEvents(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var userID *uuid.UUID
+	if v := r.URL.Query().Get("user_id"); v != "" {
+		uid, err := uuid.Parse(v)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid user_id")
+			return
+		}
+		userID = &uid
+	}
+
+	cursor := r.Header.Get("Last-Event-ID")
+	if cursor == "" {
+		cursor = r.URL.Query().Get("last_event_id")
+	}
+	if cursor == "" {
+		head, err := h.service.EventsHead(ctx)
+		if err != nil {
+			h.handleError(w, err)
+			return
+		}
+		cursor = head
+	}
+
+	signals, unsubscribe := h.service.SubscribeEvents(userID)
+	defer unsubscribe()
+
+	events, err := h.service.EventsAfter(ctx, cursor, userID, sseBatchSize)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	rc := http.NewResponseController(w)
+	// the server write timeout would cut the stream
+	_ = rc.SetWriteDeadline(time.Time{})
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprintf(w, "retry: %d\n\n", sseRetryInterval.Milliseconds())
+
+	poll := time.NewTicker(ssePollInterval)
+	defer poll.Stop()
+	keepAlive := time.NewTicker(sseKeepAlive)
+	defer keepAlive.Stop()
+
+	for {
+		for _, e := range events {
+			if err := writeSSE(w, e); err != nil {
+				return
+			}
+			cursor = e.Cursor
+		}
+		if err := rc.Flush(); err != nil {
+			return
+		}
+
+		if len(events) < sseBatchSize {
+			if !waitForEvents(w, rc, r, signals, poll.C, keepAlive.C) {
+				return
+			}
+		}
+
+		if events, err = h.service.EventsAfter(ctx, cursor, userID, sseBatchSize); err != nil {
+			return
+		}
+	}
+}
+
+// waitForEvents blocks until new events may be available, sending keep-alive
+// comments meanwhile. It returns false once the client is gone or the server
+// is shutting down.
+func waitForEvents(w http.ResponseWriter, rc *http.ResponseController, r *http.Request,
+	signals <-chan struct{}, poll, keepAlive <-chan time.Time,
+) bool {
+	for {
+		select {
+		case <-r.Context().Done():
+			return false
+		case _, ok := <-signals:
+			return ok
+		case <-poll:
+			return true
+		case <-keepAlive:
+			if _, err := fmt.Fprint(w, ": keep-alive\n\n"); err != nil {
+				return false
+			}
+			if err := rc.Flush(); err != nil {
+				return false
+			}
+		}
+	}
+}
+
+func writeSSE(w http.ResponseWriter, e domain.Event) error {
+	data, err := publisher.Marshal(e)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", e.Cursor, e.Type, data)
+	return err
+}
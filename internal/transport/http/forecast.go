@@ -0,0 +1,76 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/google/uuid"
+
+	"github.com/always-tired/crud-subscriptions/internal/usecase"
+)
+
+// @Summary Forecast spend
+// @Description Projected spend per month starting with the current month, with a running total, optionally broken down per user or per service.
+// @Tags subscriptions
+// @Produce json
+// @Param months query int false "number of months (default 12, max 120)"
+// @Param user_id query string false "user id" format(uuid)
+// @Param service_name query string false "service name"
+// @Param group_by query string false "break the forecast down" Enums(user, service)
+// @Success 200 {object} forecastResponse
+// @Failure 400 {object} errorResponse
+// @Router /subscriptions/forecast [get]
+func (h *Handler) forecast(w http.ResponseWriter, r *http.Request) {
+	var filter usecase.ForecastFilter
+
+	if v := r.URL.Query().Get("months"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			writeError(w, http.StatusBadRequest, "invalid months")
+			return
+		}
+		filter.Months = n
+	}
+	if v := r.URL.Query().Get("user_id"); v != "" {
+		uid, err := uuid.Parse(v)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid user_id")
+			return
+		}
+		filter.UserID = &uid
+	}
+	if v := r.URL.Query().Get("service_name"); v != "" {
+		filter.ServiceName = &v
+	}
+	filter.GroupBy = r.URL.Query().Get("group_by")
+
+	f, err := h.service.Forecast(r.Context(), filter)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	resp := forecastResponse{Months: toForecastMonths(f.Months), Total: f.Total}
+	for _, g := range f.Groups {
+		gr := forecastGroupResponse{Months: toForecastMonths(g.Months), Total: g.Total}
+		if filter.GroupBy == usecase.ForecastGroupByUser {
+			gr.UserID = g.Key
+		} else {
+			gr.ServiceName = g.Key
+		}
+		resp.Groups = append(resp.Groups, gr)
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func toForecastMonths(months []usecase.ForecastMonth) []forecastMonthResponse {
+	res := make([]forecastMonthResponse, 0, len(months))
+	for _, m := range months {
+		res = append(res, forecastMonthResponse{
+			Month:      usecase.FormatMonthDate(m.Month),
+			Total:      m.Total,
+			Cumulative: m.Cumulative,
+		})
+	}
+	return res
+}
@@ -24,6 +24,7 @@ func NewHandler(service *usecase.Service, log *slog.Logger) *Handler {
 func (h *Handler) Router() chi.Router {
 	r := chi.NewRouter()
 
+	r.Use(requestContext)
 	r.Use(requestLogger(h.log))
 	r.Use(recoverer(h.log))
 
@@ -31,10 +32,102 @@ func (h *Handler) Router() chi.Router {
 		r.Post("/", h.createSubscription)
 		r.Get("/", h.listSubscriptions)
 		r.Get("/summary", h.summary)
+		r.Get("/forecast", h.forecast)
+		r.Get("/events", h.streamEvents)
+		r.Post("/{id}:restore", h.restoreSubscription)
+		r.Post("/{id}:pause", h.pauseSubscription)
+		r.Post("/{id}:resume", h.resumeSubscription)
+		r.Post("/{id}:cancel", h.cancelSubscription)
+		r.Post("/{id}:reactivate", h.reactivateSubscription)
 		r.Route("/{id}", func(r chi.Router) {
 			r.Get("/", h.getSubscription)
 			r.Put("/", h.updateSubscription)
 			r.Delete("/", h.deleteSubscription)
+			r.Get("/history", h.subscriptionHistory)
+			r.Get("/prices", h.listPrices)
+			r.Post("/prices", h.schedulePriceChange)
+			r.Delete("/prices/{month}", h.cancelPriceChange)
+			r.Get("/discounts", h.listDiscounts)
+			r.Post("/discounts", h.addDiscount)
+			r.Delete("/discounts/{discount_id}", h.removeDiscount)
+		})
+	})
+
+	r.Get("/audit", h.listAudit)
+
+	r.Route("/webhooks", func(r chi.Router) {
+		r.Post("/", h.createWebhook)
+		r.Get("/", h.listWebhooks)
+		r.Route("/{id}", func(r chi.Router) {
+			r.Get("/", h.getWebhook)
+			r.Put("/", h.updateWebhook)
+			r.Delete("/", h.deleteWebhook)
+			r.Get("/deliveries", h.listDeliveries)
+			r.Get("/deliveries/{delivery_id}", h.getDelivery)
+			r.Post("/deliveries/{delivery_id}:redeliver", h.redeliver)
+		})
+	})
+
+	r.Route("/categories", func(r chi.Router) {
+		r.Post("/", h.createCategory)
+		r.Get("/", h.listCategories)
+		r.Route("/{id}", func(r chi.Router) {
+			r.Get("/", h.getCategory)
+			r.Put("/", h.updateCategory)
+			r.Delete("/", h.deleteCategory)
+		})
+	})
+
+	r.Route("/payment-methods", func(r chi.Router) {
+		r.Post("/", h.createPaymentMethod)
+		r.Get("/", h.listPaymentMethods)
+		r.Get("/expiring", h.expiringPaymentMethods)
+		r.Route("/{id}", func(r chi.Router) {
+			r.Get("/", h.getPaymentMethod)
+			r.Put("/", h.updatePaymentMethod)
+			r.Delete("/", h.deletePaymentMethod)
+		})
+	})
+
+	r.Route("/services", func(r chi.Router) {
+		r.Post("/", h.createService)
+		r.Get("/", h.listServices)
+		r.Get("/resolve", h.resolveService)
+		r.Route("/{id}", func(r chi.Router) {
+			r.Get("/", h.getService)
+			r.Put("/", h.updateService)
+			r.Delete("/", h.deleteService)
+		})
+	})
+
+	r.Route("/budgets", func(r chi.Router) {
+		r.Post("/", h.createBudget)
+		r.Get("/", h.listBudgets)
+		r.Route("/{id}", func(r chi.Router) {
+			r.Get("/", h.getBudget)
+			r.Put("/", h.updateBudget)
+			r.Delete("/", h.deleteBudget)
+		})
+	})
+
+	r.Route("/insights", func(r chi.Router) {
+		r.Get("/duplicates", h.duplicates)
+		r.Get("/anomalies", h.anomalies)
+		r.Get("/recommendations", h.recommendations)
+	})
+
+	r.Route("/users", func(r chi.Router) {
+		r.Post("/", h.createUser)
+		r.Get("/", h.listUsers)
+		r.Route("/{user_id}", func(r chi.Router) {
+			r.Get("/", h.getUser)
+			r.Put("/", h.updateUser)
+			r.Delete("/", h.deleteUser)
+			r.Get("/overview", h.userOverview)
+			r.Post("/calendar-token", h.issueCalendarToken)
+			r.Delete("/calendar-token", h.revokeCalendarToken)
+			r.Get("/renewals.ics", h.renewalCalendar)
+			r.Get("/budget-status", h.budgetStatus)
 		})
 	})
 
@@ -52,6 +145,7 @@ func (h *Handler) Router() chi.Router {
 // @Param subscription body subscriptionRequest true "subscription"
 // @Success 201 {object} subscriptionResponse
 // @Failure 400 {object} errorResponse
+// @Failure 409 {object} errorResponse
 // @Router /subscriptions [post]
 func (h *Handler) createSubscription(w http.ResponseWriter, r *http.Request) {
 	var req subscriptionRequest
@@ -60,21 +154,13 @@ func (h *Handler) createSubscription(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	input := usecase.SubscriptionInput{
-		ServiceName: req.ServiceName,
-		Price:       req.Price,
-		UserID:      req.UserID,
-		StartDate:   req.StartDate,
-		EndDate:     req.EndDate,
-	}
-
-	created, err := h.service.Create(r.Context(), input)
+	created, err := h.service.Create(r.Context(), req.toInput())
 	if err != nil {
 		h.handleError(w, err)
 		return
 	}
 
-	writeJSON(w, http.StatusCreated, domainToResponse(created))
+	writeJSON(w, http.StatusCreated, domainToResponse(created, h.service.Describe(created)))
 }
 
 // @Summary Get subscription by id
@@ -98,7 +184,7 @@ func (h *Handler) getSubscription(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	writeJSON(w, http.StatusOK, domainToResponse(sub))
+	writeJSON(w, http.StatusOK, domainToResponse(sub, h.service.Describe(sub)))
 }
 
 // @Summary Update subscription
@@ -110,6 +196,7 @@ func (h *Handler) getSubscription(w http.ResponseWriter, r *http.Request) {
 // @Success 200 {object} subscriptionResponse
 // @Failure 400 {object} errorResponse
 // @Failure 404 {object} errorResponse
+// @Failure 409 {object} errorResponse
 // @Router /subscriptions/{id} [put]
 func (h *Handler) updateSubscription(w http.ResponseWriter, r *http.Request) {
 	id, err := uuid.Parse(chi.URLParam(r, "id"))
@@ -124,21 +211,13 @@ func (h *Handler) updateSubscription(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	input := usecase.SubscriptionInput{
-		ServiceName: req.ServiceName,
-		Price:       req.Price,
-		UserID:      req.UserID,
-		StartDate:   req.StartDate,
-		EndDate:     req.EndDate,
-	}
-
-	updated, err := h.service.Update(r.Context(), id, input)
+	updated, err := h.service.Update(r.Context(), id, req.toInput())
 	if err != nil {
 		h.handleError(w, err)
 		return
 	}
 
-	writeJSON(w, http.StatusOK, domainToResponse(updated))
+	writeJSON(w, http.StatusOK, domainToResponse(updated, h.service.Describe(updated)))
 }
 
 // @Summary Delete subscription
@@ -163,14 +242,45 @@ func (h *Handler) deleteSubscription(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusNoContent)
 }
 
+// @Summary Restore deleted subscription
+// @Tags subscriptions
+// @Produce json
+// @Param id path string true "subscription id" format(uuid)
+// @Success 200 {object} subscriptionResponse
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 409 {object} errorResponse
+// @Router /subscriptions/{id}:restore [post]
+func (h *Handler) restoreSubscription(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	sub, err := h.service.Restore(r.Context(), id)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, domainToResponse(sub, h.service.Describe(sub)))
+}
+
 // @Summary List subscriptions
 // @Tags subscriptions
 // @Produce json
 // @Param user_id query string false "user id" format(uuid)
 // @Param service_name query string false "service name"
+// @Param category query string false "category id or name"
+// @Param tag query []string false "tags, all must match" collectionFormat(multi)
+// @Param trial_ending_within query int false "only trials whose first charge is due within this many days"
 // @Param limit query int false "limit"
 // @Param offset query int false "offset"
+// @Param include_deleted query bool false "include soft-deleted subscriptions (admin)"
+// @Param X-Admin-Token header string false "admin token, required with include_deleted"
 // @Success 200 {array} subscriptionResponse
+// @Failure 403 {object} errorResponse
 // @Router /subscriptions [get]
 func (h *Handler) listSubscriptions(w http.ResponseWriter, r *http.Request) {
 	var filter usecase.ListFilter
@@ -186,6 +296,26 @@ func (h *Handler) listSubscriptions(w http.ResponseWriter, r *http.Request) {
 	if v := r.URL.Query().Get("service_name"); v != "" {
 		filter.ServiceName = &v
 	}
+	if v := r.URL.Query().Get("category"); v != "" {
+		filter.Category = &v
+	}
+	filter.Tags = r.URL.Query()["tag"]
+	if v := r.URL.Query().Get("trial_ending_within"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid trial_ending_within")
+			return
+		}
+		filter.TrialEndingWithin = &n
+	}
+	if v := r.URL.Query().Get("include_deleted"); v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid include_deleted")
+			return
+		}
+		filter.IncludeDeleted = b
+	}
 	if v := r.URL.Query().Get("limit"); v != "" {
 		if n, err := strconv.Atoi(v); err == nil {
 			filter.Limit = n
@@ -205,7 +335,7 @@ func (h *Handler) listSubscriptions(w http.ResponseWriter, r *http.Request) {
 
 	resp := make([]subscriptionResponse, 0, len(list))
 	for _, s := range list {
-		resp = append(resp, domainToResponse(s))
+		resp = append(resp, domainToResponse(s, h.service.Describe(s)))
 	}
 
 	writeJSON(w, http.StatusOK, resp)
@@ -216,9 +346,12 @@ func (h *Handler) listSubscriptions(w http.ResponseWriter, r *http.Request) {
 // @Produce json
 // @Param start query string true "start month" example(07-2025)
 // @Param end query string true "end month" example(12-2025)
-// @Param user_id query string false "user id" format(uuid)
+// @Param user_id query string false "user id, counting their shares of shared subscriptions" format(uuid)
 // @Param service_name query string false "service name"
-// @Success 200 {object} map[string]int64
+// @Param category query string false "category id or name"
+// @Param tag query []string false "tags, all must match" collectionFormat(multi)
+// @Param group_by query string false "break the total down" Enums(category, payment_method)
+// @Success 200 {object} summaryResponse
 // @Failure 400 {object} errorResponse
 // @Router /subscriptions/summary [get]
 func (h *Handler) summary(w http.ResponseWriter, r *http.Request) {
@@ -253,14 +386,54 @@ func (h *Handler) summary(w http.ResponseWriter, r *http.Request) {
 	if v := r.URL.Query().Get("service_name"); v != "" {
 		filter.ServiceName = &v
 	}
+	if v := r.URL.Query().Get("category"); v != "" {
+		filter.Category = &v
+	}
+	filter.Tags = r.URL.Query()["tag"]
+	filter.GroupBy = r.URL.Query().Get("group_by")
 
-	total, err := h.service.Summary(r.Context(), filter)
+	res, err := h.service.Summary(r.Context(), filter)
 	if err != nil {
 		h.handleError(w, err)
 		return
 	}
 
-	writeJSON(w, http.StatusOK, map[string]any{
-		"total": total,
-	})
+	resp := summaryResponse{Total: res.Total, Net: res.Net, Tax: res.Tax, Gross: res.Gross}
+	for _, g := range res.Groups {
+		resp.Groups = append(resp.Groups, summaryGroupToResponse(g))
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func summaryGroupToResponse(g usecase.SummaryGroup) summaryGroupResponse {
+	group := summaryGroupResponse{Total: g.Total, Net: g.Net, Tax: g.Tax, Gross: g.Gross}
+	if g.ID != nil {
+		id := g.ID.String()
+		name := g.Name
+		group.ID, group.Name = &id, &name
+	}
+	return group
+}
+
+func (req subscriptionRequest) toInput() usecase.SubscriptionInput {
+	input := usecase.SubscriptionInput{
+		ServiceName:     req.ServiceName,
+		Price:           req.Price,
+		UserID:          req.UserID,
+		StartDate:       req.StartDate,
+		EndDate:         req.EndDate,
+		TrialEndDate:    req.TrialEndDate,
+		TrialMonths:     req.TrialMonths,
+		CategoryID:      req.CategoryID,
+		Tags:            req.Tags,
+		SplitRule:       req.SplitRule,
+		TaxRate:         req.TaxRate,
+		TaxInclusive:    req.TaxInclusive,
+		PaymentMethodID: req.PaymentMethodID,
+		Unused:          req.Unused,
+	}
+	for _, m := range req.Members {
+		input.Members = append(input.Members, usecase.MemberInput{UserID: m.UserID, Share: m.Share})
+	}
+	return input
 }
@@ -0,0 +1,93 @@
+package http
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/always-tired/crud-subscriptions/internal/domain"
+)
+
+const (
+	icalDateLayout     = "20060102"
+	icalDateTimeLayout = "20060102T150405Z"
+	icalMaxLineOctets  = 75
+)
+
+// writeRenewalCalendar renders subscriptions as an RFC 5545 calendar with one
+// monthly recurring all-day event per subscription. Paused months are left
+// out; a pause without an end stops the series.
+func writeRenewalCalendar(b *strings.Builder, subs []domain.Subscription) {
+	writeICalLine(b, "BEGIN:VCALENDAR")
+	writeICalLine(b, "VERSION:2.0")
+	writeICalLine(b, "PRODID:-//always-tired//crud-subscriptions//EN")
+	writeICalLine(b, "CALSCALE:GREGORIAN")
+	writeICalLine(b, "METHOD:PUBLISH")
+	writeICalLine(b, "X-WR-CALNAME:Subscription renewals")
+
+	for _, s := range subs {
+		until := s.EndDate
+		var exdates []string
+		for _, p := range s.Pauses {
+			if p.Until == nil {
+				last := p.From.AddDate(0, -1, 0)
+				if until == nil || last.Before(*until) {
+					until = &last
+				}
+				continue
+			}
+			for m := p.From; !m.After(*p.Until); m = m.AddDate(0, 1, 0) {
+				exdates = append(exdates, m.UTC().Format(icalDateLayout))
+			}
+		}
+
+		rrule := "RRULE:FREQ=MONTHLY"
+		if until != nil {
+			rrule += ";UNTIL=" + until.UTC().Format(icalDateLayout)
+		}
+
+		writeICalLine(b, "BEGIN:VEVENT")
+		writeICalLine(b, "UID:"+s.ID.String()+"@crud-subscriptions")
+		writeICalLine(b, "DTSTAMP:"+s.UpdatedAt.UTC().Format(icalDateTimeLayout))
+		writeICalLine(b, "DTSTART;VALUE=DATE:"+s.FirstChargeDate().UTC().Format(icalDateLayout))
+		writeICalLine(b, rrule)
+		if len(exdates) > 0 {
+			writeICalLine(b, "EXDATE;VALUE=DATE:"+strings.Join(exdates, ","))
+		}
+		writeICalLine(b, "SUMMARY:"+escapeICalText(fmt.Sprintf("%s: %d", s.ServiceName, s.Price)))
+		writeICalLine(b, "TRANSP:TRANSPARENT")
+		writeICalLine(b, "END:VEVENT")
+	}
+
+	writeICalLine(b, "END:VCALENDAR")
+}
+
+// writeICalLine writes a content line terminated by CRLF, folding it so that
+// no physical line exceeds 75 octets and no UTF-8 sequence is split.
+func writeICalLine(b *strings.Builder, line string) {
+	limit := icalMaxLineOctets
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		b.WriteString(line[:cut])
+		b.WriteString("\r\n ")
+		line = line[cut:]
+		// continuation lines start with a space, which counts toward the limit
+		limit = icalMaxLineOctets - 1
+	}
+	b.WriteString(line)
+	b.WriteString("\r\n")
+}
+
+func escapeICalText(s string) string {
+	r := strings.NewReplacer(
+		`\`, `\\`,
+		`;`, `\;`,
+		`,`, `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+	)
+	return r.Replace(s)
+}
@@ -0,0 +1,174 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/google/uuid"
+
+	"github.com/always-tired/crud-subscriptions/internal/usecase"
+)
+
+// @Summary Duplicate subscriptions
+// @Description Pairs of subscriptions for the same service, aliases included, whose periods overlap. same_user pairs are paid twice by one user; separate_plans pairs by different users who could share one subscription.
+// @Tags insights
+// @Produce json
+// @Param user_id query string false "only pairs the user pays for" format(uuid)
+// @Success 200 {array} duplicateResponse
+// @Failure 400 {object} errorResponse
+// @Router /insights/duplicates [get]
+func (h *Handler) duplicates(w http.ResponseWriter, r *http.Request) {
+	var userID *uuid.UUID
+	if v := r.URL.Query().Get("user_id"); v != "" {
+		uid, err := uuid.Parse(v)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid user_id")
+			return
+		}
+		userID = &uid
+	}
+
+	list, err := h.service.Duplicates(r.Context(), userID)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	resp := make([]duplicateResponse, 0, len(list))
+	for _, d := range list {
+		item := duplicateResponse{
+			Kind:          d.Kind,
+			ServiceName:   d.ServiceName,
+			From:          usecase.FormatMonthDate(d.From),
+			Subscriptions: make([]subscriptionResponse, 0, len(d.Subscriptions)),
+		}
+		if d.Until != nil {
+			u := usecase.FormatMonthDate(*d.Until)
+			item.Until = &u
+		}
+		for _, sub := range d.Subscriptions {
+			item.Subscriptions = append(item.Subscriptions, domainToResponse(sub, h.service.Describe(sub)))
+		}
+		resp = append(resp, item)
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// @Summary Spend anomalies
+// @Description Months whose spend jumps compared with the average of the months before it, e.g. after a price increase or a forgotten trial. Spend is charged like the summary. The range defaults to the twelve months up to the current one.
+// @Tags insights
+// @Produce json
+// @Param start query string false "first month checked (MM-YYYY)"
+// @Param end query string false "last month checked (MM-YYYY, default current month)"
+// @Param user_id query string false "user id" format(uuid)
+// @Param service_name query string false "service name"
+// @Param threshold query int false "percent above the trailing average that flags a month (default ANOMALY_THRESHOLD, max 1000)"
+// @Param window query int false "months in the trailing average (default ANOMALY_WINDOW, max 12)"
+// @Success 200 {array} anomalyResponse
+// @Failure 400 {object} errorResponse
+// @Router /insights/anomalies [get]
+func (h *Handler) anomalies(w http.ResponseWriter, r *http.Request) {
+	var filter usecase.AnomalyFilter
+
+	if v := r.URL.Query().Get("start"); v != "" {
+		start, err := usecase.ParseMonthDate(v)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		filter.Start = start
+	}
+	if v := r.URL.Query().Get("end"); v != "" {
+		end, err := usecase.ParseMonthDate(v)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		filter.End = end
+	}
+	if v := r.URL.Query().Get("user_id"); v != "" {
+		uid, err := uuid.Parse(v)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid user_id")
+			return
+		}
+		filter.UserID = &uid
+	}
+	if v := r.URL.Query().Get("service_name"); v != "" {
+		filter.ServiceName = &v
+	}
+	if v := r.URL.Query().Get("threshold"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			writeError(w, http.StatusBadRequest, "invalid threshold")
+			return
+		}
+		filter.Threshold = n
+	}
+	if v := r.URL.Query().Get("window"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			writeError(w, http.StatusBadRequest, "invalid window")
+			return
+		}
+		filter.Window = n
+	}
+
+	list, err := h.service.Anomalies(r.Context(), filter)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	resp := make([]anomalyResponse, 0, len(list))
+	for _, a := range list {
+		resp = append(resp, anomalyResponse{
+			Month:           usecase.FormatMonthDate(a.Month),
+			Total:           a.Total,
+			TrailingAverage: a.Average,
+			ChangePercent:   a.Change,
+		})
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// @Summary Savings recommendations
+// @Description Suggestions to spend less, the largest savings first: cancel_unused for subscriptions flagged unused, switch_to_yearly when the catalog's yearly_price is below twelve monthly charges and family_plan when several users pay for one service separately. monthly_savings is estimated from the gross charges of the current month, after discounts and with tax.
+// @Tags insights
+// @Produce json
+// @Param user_id query string false "only suggestions for subscriptions the user pays for" format(uuid)
+// @Success 200 {array} recommendationResponse
+// @Failure 400 {object} errorResponse
+// @Router /insights/recommendations [get]
+func (h *Handler) recommendations(w http.ResponseWriter, r *http.Request) {
+	var userID *uuid.UUID
+	if v := r.URL.Query().Get("user_id"); v != "" {
+		uid, err := uuid.Parse(v)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid user_id")
+			return
+		}
+		userID = &uid
+	}
+
+	list, err := h.service.Recommendations(r.Context(), userID)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	resp := make([]recommendationResponse, 0, len(list))
+	for _, rec := range list {
+		item := recommendationResponse{
+			Kind:           rec.Kind,
+			ServiceName:    rec.ServiceName,
+			MonthlySavings: rec.MonthlySavings,
+			Subscriptions:  make([]subscriptionResponse, 0, len(rec.Subscriptions)),
+		}
+		for _, sub := range rec.Subscriptions {
+			item.Subscriptions = append(item.Subscriptions, domainToResponse(sub, h.service.Describe(sub)))
+		}
+		resp = append(resp, item)
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
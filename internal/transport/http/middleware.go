@@ -7,6 +7,8 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/google/uuid"
+
 	"github.com/always-tired/crud-subscriptions/internal/domain"
 	"github.com/always-tired/crud-subscriptions/internal/usecase"
 )
@@ -21,6 +23,36 @@ func (w *responseWriter) WriteHeader(status int) {
 	w.ResponseWriter.WriteHeader(status)
 }
 
+// Unwrap lets http.ResponseController reach the underlying writer, e.g. to
+// flush streamed responses.
+func (w *responseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+const (
+	requestIDHeader = "X-Request-ID"
+	actorHeader     = "X-Actor"
+	adminHeader     = "X-Admin-Token"
+)
+
+// requestContext propagates the request ID (generated when the client sends
+// none) and the actor header into the request context for auditing, and the
+// admin token for admin-only reads.
+func requestContext(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(requestIDHeader)
+		if id == "" {
+			id = uuid.NewString()
+		}
+		w.Header().Set(requestIDHeader, id)
+
+		ctx := usecase.ContextWithRequestID(r.Context(), id)
+		ctx = usecase.ContextWithActor(ctx, r.Header.Get(actorHeader))
+		ctx = usecase.ContextWithAdminToken(ctx, r.Header.Get(adminHeader))
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
 func requestLogger(log *slog.Logger) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -30,6 +62,7 @@ func requestLogger(log *slog.Logger) func(http.Handler) http.Handler {
 			log.Info("request",
 				slog.String("method", r.Method),
 				slog.String("path", r.URL.Path),
+				slog.String("request_id", usecase.RequestIDFrom(r.Context())),
 				slog.Int("status", rw.status),
 				slog.Duration("duration", time.Since(start)),
 			)
@@ -67,6 +100,10 @@ func (h *Handler) handleError(w http.ResponseWriter, err error) {
 		writeError(w, http.StatusBadRequest, "invalid argument")
 	case errors.Is(err, domain.ErrDuplicate):
 		writeError(w, http.StatusConflict, "already exists")
+	case errors.Is(err, domain.ErrConflict):
+		writeError(w, http.StatusConflict, "conflict")
+	case errors.Is(err, domain.ErrForbidden):
+		writeError(w, http.StatusForbidden, "forbidden")
 	case errors.Is(err, domain.ErrNotFound):
 		writeError(w, http.StatusNotFound, "not found")
 	default:
@@ -74,21 +111,112 @@ func (h *Handler) handleError(w http.ResponseWriter, err error) {
 	}
 }
 
-func domainToResponse(s domain.Subscription) subscriptionResponse {
+func domainToResponse(s domain.Subscription, info usecase.SubscriptionInfo) subscriptionResponse {
 	var end *string
 	if s.EndDate != nil {
 		e := usecase.FormatMonthDate(*s.EndDate)
 		end = &e
 	}
 
+	var trialEnd *string
+	if s.TrialEndDate != nil {
+		t := usecase.FormatMonthDate(*s.TrialEndDate)
+		trialEnd = &t
+	}
+
+	var pauses []pauseResponse
+	for _, p := range s.Pauses {
+		pr := pauseResponse{From: usecase.FormatMonthDate(p.From)}
+		if p.Until != nil {
+			u := usecase.FormatMonthDate(*p.Until)
+			pr.Until = &u
+		}
+		pauses = append(pauses, pr)
+	}
+
+	var members []memberResponse
+	for _, m := range s.Members {
+		members = append(members, memberResponse{UserID: m.UserID.String(), Share: m.Share})
+	}
+	var shares []shareResponse
+	for _, sh := range info.Shares {
+		shares = append(shares, shareResponse{UserID: sh.UserID.String(), Amount: sh.Amount})
+	}
+
+	var nextBilling *string
+	if info.NextBillingDate != nil {
+		n := info.NextBillingDate.Format(time.DateOnly)
+		nextBilling = &n
+	}
+
+	var cancelReason, cancelledAt *string
+	if s.CancelReason != "" {
+		cancelReason = &s.CancelReason
+	}
+	if s.CancelledAt != nil {
+		c := s.CancelledAt.UTC().Format(time.RFC3339)
+		cancelledAt = &c
+	}
+
+	var deleted *string
+	if s.DeletedAt != nil {
+		d := s.DeletedAt.UTC().Format(time.RFC3339)
+		deleted = &d
+	}
+
+	var category *string
+	if s.CategoryID != nil {
+		category = &s.Category
+	}
+
+	var paymentMethod *string
+	if s.PaymentMethodID != nil {
+		paymentMethod = &s.PaymentMethod
+	}
+
+	tags := s.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+
 	return subscriptionResponse{
-		ID:          s.ID.String(),
-		ServiceName: s.ServiceName,
-		Price:       s.Price,
-		UserID:      s.UserID.String(),
-		StartDate:   usecase.FormatMonthDate(s.StartDate),
-		EndDate:     end,
-		CreatedAt:   s.CreatedAt.UTC().Format(time.RFC3339),
-		UpdatedAt:   s.UpdatedAt.UTC().Format(time.RFC3339),
+		ID:              s.ID.String(),
+		ServiceName:     s.ServiceName,
+		Price:           info.Price,
+		EffectivePrice:  info.EffectivePrice,
+		UserID:          s.UserID.String(),
+		StartDate:       usecase.FormatMonthDate(s.StartDate),
+		EndDate:         end,
+		TrialEndDate:    trialEnd,
+		CategoryID:      uuidString(s.CategoryID),
+		Category:        category,
+		PaymentMethodID: uuidString(s.PaymentMethodID),
+		PaymentMethod:   paymentMethod,
+		Tags:            tags,
+		Pauses:          pauses,
+		Discounts:       discountsToResponse(s.Discounts),
+		SplitRule:       s.SplitRule,
+		Members:         members,
+		Shares:          shares,
+		TaxRate:         s.TaxRate,
+		TaxInclusive:    s.TaxInclusive,
+		Unused:          s.Unused,
+		Status:          info.Status,
+		NextBillingDate: nextBilling,
+		MonthsActive:    info.MonthsActive,
+		LifetimeCost:    info.LifetimeCost,
+		CancelReason:    cancelReason,
+		CancelledAt:     cancelledAt,
+		CreatedAt:       s.CreatedAt.UTC().Format(time.RFC3339),
+		UpdatedAt:       s.UpdatedAt.UTC().Format(time.RFC3339),
+		DeletedAt:       deleted,
+	}
+}
+
+func uuidString(id *uuid.UUID) *string {
+	if id == nil {
+		return nil
 	}
+	v := id.String()
+	return &v
 }
@@ -0,0 +1,79 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/always-tired/crud-subscriptions/internal/usecase"
+)
+
+// @Summary Pause subscription
+// @Description Stops billing from `from` (default: next month) through `until`, or until resumed. The body is optional.
+// @Tags subscriptions
+// @Accept json
+// @Produce json
+// @Param id path string true "subscription id" format(uuid)
+// @Param pause body pauseRequest false "pause"
+// @Success 200 {object} subscriptionResponse
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Router /subscriptions/{id}:pause [post]
+func (h *Handler) pauseSubscription(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	var req pauseRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		writeError(w, http.StatusBadRequest, "invalid json")
+		return
+	}
+
+	sub, err := h.service.Pause(r.Context(), id, usecase.PauseInput{From: req.From, Until: req.Until})
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, domainToResponse(sub, h.service.Describe(sub)))
+}
+
+// @Summary Resume subscription
+// @Description Restarts billing from `from` (default: next month), ending the pause that covers it. The body is optional.
+// @Tags subscriptions
+// @Accept json
+// @Produce json
+// @Param id path string true "subscription id" format(uuid)
+// @Param resume body resumeRequest false "resume"
+// @Success 200 {object} subscriptionResponse
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Router /subscriptions/{id}:resume [post]
+func (h *Handler) resumeSubscription(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	var req resumeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		writeError(w, http.StatusBadRequest, "invalid json")
+		return
+	}
+
+	sub, err := h.service.Resume(r.Context(), id, usecase.ResumeInput{From: req.From})
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, domainToResponse(sub, h.service.Describe(sub)))
+}
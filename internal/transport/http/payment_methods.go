@@ -0,0 +1,203 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/always-tired/crud-subscriptions/internal/domain"
+	"github.com/always-tired/crud-subscriptions/internal/usecase"
+)
+
+const defaultExpiryWindowMonths = 3
+
+// @Summary Create payment method
+// @Tags payment-methods
+// @Accept json
+// @Produce json
+// @Param payment_method body paymentMethodRequest true "payment method"
+// @Success 201 {object} paymentMethodResponse
+// @Failure 400 {object} errorResponse
+// @Router /payment-methods [post]
+func (h *Handler) createPaymentMethod(w http.ResponseWriter, r *http.Request) {
+	var req paymentMethodRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid json")
+		return
+	}
+
+	p, err := h.service.CreatePaymentMethod(r.Context(), req.toInput())
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, paymentMethodToResponse(p))
+}
+
+// @Summary List payment methods
+// @Tags payment-methods
+// @Produce json
+// @Success 200 {array} paymentMethodResponse
+// @Router /payment-methods [get]
+func (h *Handler) listPaymentMethods(w http.ResponseWriter, r *http.Request) {
+	list, err := h.service.ListPaymentMethods(r.Context())
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	resp := make([]paymentMethodResponse, 0, len(list))
+	for _, p := range list {
+		resp = append(resp, paymentMethodToResponse(p))
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// @Summary Payment methods expiring soon
+// @Description Payment methods whose last chargeable month is within within_months months, counting the current one, or has passed, with the subscriptions that still charge them.
+// @Tags payment-methods
+// @Produce json
+// @Param within_months query int false "months ahead, counting the current one (default 3)"
+// @Success 200 {array} expiringPaymentMethodResponse
+// @Failure 400 {object} errorResponse
+// @Router /payment-methods/expiring [get]
+func (h *Handler) expiringPaymentMethods(w http.ResponseWriter, r *http.Request) {
+	months := defaultExpiryWindowMonths
+	if v := r.URL.Query().Get("within_months"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid within_months")
+			return
+		}
+		months = n
+	}
+
+	list, err := h.service.ExpiringPaymentMethods(r.Context(), months)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	resp := make([]expiringPaymentMethodResponse, 0, len(list))
+	for _, e := range list {
+		item := expiringPaymentMethodResponse{
+			PaymentMethod: paymentMethodToResponse(e.PaymentMethod),
+			Expired:       e.Expired,
+			Subscriptions: make([]subscriptionResponse, 0, len(e.Subscriptions)),
+		}
+		for _, sub := range e.Subscriptions {
+			item.Subscriptions = append(item.Subscriptions, domainToResponse(sub, h.service.Describe(sub)))
+		}
+		resp = append(resp, item)
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// @Summary Get payment method
+// @Tags payment-methods
+// @Produce json
+// @Param id path string true "payment method id" format(uuid)
+// @Success 200 {object} paymentMethodResponse
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Router /payment-methods/{id} [get]
+func (h *Handler) getPaymentMethod(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	p, err := h.service.GetPaymentMethod(r.Context(), id)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, paymentMethodToResponse(p))
+}
+
+// @Summary Update payment method
+// @Tags payment-methods
+// @Accept json
+// @Produce json
+// @Param id path string true "payment method id" format(uuid)
+// @Param payment_method body paymentMethodRequest true "payment method"
+// @Success 200 {object} paymentMethodResponse
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Router /payment-methods/{id} [put]
+func (h *Handler) updatePaymentMethod(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	var req paymentMethodRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid json")
+		return
+	}
+
+	p, err := h.service.UpdatePaymentMethod(r.Context(), id, req.toInput())
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, paymentMethodToResponse(p))
+}
+
+// @Summary Delete payment method
+// @Description Subscriptions paid with the method are left without one.
+// @Tags payment-methods
+// @Param id path string true "payment method id" format(uuid)
+// @Success 204
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Router /payment-methods/{id} [delete]
+func (h *Handler) deletePaymentMethod(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	if err := h.service.DeletePaymentMethod(r.Context(), id); err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (req paymentMethodRequest) toInput() usecase.PaymentMethodInput {
+	return usecase.PaymentMethodInput{
+		Label:  req.Label,
+		Type:   req.Type,
+		Last4:  req.Last4,
+		Expiry: req.Expiry,
+	}
+}
+
+func paymentMethodToResponse(p domain.PaymentMethod) paymentMethodResponse {
+	resp := paymentMethodResponse{
+		ID:        p.ID.String(),
+		Label:     p.Label,
+		Type:      p.Type,
+		Last4:     p.Last4,
+		CreatedAt: p.CreatedAt.UTC().Format(time.RFC3339),
+		UpdatedAt: p.UpdatedAt.UTC().Format(time.RFC3339),
+	}
+	if p.Expiry != nil {
+		e := usecase.FormatMonthDate(*p.Expiry)
+		resp.Expiry = &e
+	}
+	return resp
+}
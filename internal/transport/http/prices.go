@@ -0,0 +1,106 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/always-tired/crud-subscriptions/internal/domain"
+	"github.com/always-tired/crud-subscriptions/internal/usecase"
+)
+
+// @Summary List price history
+// @Tags subscriptions
+// @Produce json
+// @Param id path string true "subscription id" format(uuid)
+// @Success 200 {array} pricePeriodResponse
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Router /subscriptions/{id}/prices [get]
+func (h *Handler) listPrices(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	prices, err := h.service.ListPrices(r.Context(), id)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, pricesToResponse(prices))
+}
+
+// @Summary Schedule price change
+// @Description Sets the price charged from effective_from on. Earlier months keep their price.
+// @Tags subscriptions
+// @Accept json
+// @Produce json
+// @Param id path string true "subscription id" format(uuid)
+// @Param change body priceChangeRequest true "price change"
+// @Success 200 {array} pricePeriodResponse
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Router /subscriptions/{id}/prices [post]
+func (h *Handler) schedulePriceChange(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	var req priceChangeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid json")
+		return
+	}
+
+	prices, err := h.service.SchedulePriceChange(r.Context(), id, usecase.PriceChangeInput{
+		Price:         req.Price,
+		EffectiveFrom: req.EffectiveFrom,
+	})
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, pricesToResponse(prices))
+}
+
+// @Summary Cancel price change
+// @Tags subscriptions
+// @Param id path string true "subscription id" format(uuid)
+// @Param month path string true "effective month" example(07-2025)
+// @Success 204
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Router /subscriptions/{id}/prices/{month} [delete]
+func (h *Handler) cancelPriceChange(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	if err := h.service.CancelPriceChange(r.Context(), id, chi.URLParam(r, "month")); err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func pricesToResponse(prices []domain.PricePeriod) []pricePeriodResponse {
+	resp := make([]pricePeriodResponse, 0, len(prices))
+	for _, p := range prices {
+		resp = append(resp, pricePeriodResponse{
+			EffectiveFrom: usecase.FormatMonthDate(p.EffectiveFrom),
+			Price:         p.Price,
+		})
+	}
+	return resp
+}
@@ -0,0 +1,194 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/always-tired/crud-subscriptions/internal/domain"
+	"github.com/always-tired/crud-subscriptions/internal/usecase"
+)
+
+// @Summary Create catalog service
+// @Description Subscriptions naming the service or one of its aliases are stored under the canonical name.
+// @Tags services
+// @Accept json
+// @Produce json
+// @Param service body serviceRequest true "service"
+// @Success 201 {object} serviceResponse
+// @Failure 400 {object} errorResponse
+// @Failure 409 {object} errorResponse
+// @Router /services [post]
+func (h *Handler) createService(w http.ResponseWriter, r *http.Request) {
+	var req serviceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid json")
+		return
+	}
+
+	svc, err := h.service.CreateService(r.Context(), req.toInput())
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, serviceToResponse(svc))
+}
+
+// @Summary List catalog services
+// @Tags services
+// @Produce json
+// @Success 200 {array} serviceResponse
+// @Router /services [get]
+func (h *Handler) listServices(w http.ResponseWriter, r *http.Request) {
+	list, err := h.service.ListServices(r.Context())
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	resp := make([]serviceResponse, 0, len(list))
+	for _, svc := range list {
+		resp = append(resp, serviceToResponse(svc))
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// @Summary Resolve service name
+// @Description Returns the catalog service a name resolves to, matching aliases and small typos.
+// @Tags services
+// @Produce json
+// @Param name query string true "service name"
+// @Success 200 {object} serviceResponse
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Router /services/resolve [get]
+func (h *Handler) resolveService(w http.ResponseWriter, r *http.Request) {
+	name := r.URL.Query().Get("name")
+	if name == "" {
+		writeError(w, http.StatusBadRequest, "name is required")
+		return
+	}
+
+	svc, err := h.service.ResolveService(r.Context(), name)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, serviceToResponse(svc))
+}
+
+// @Summary Get catalog service
+// @Tags services
+// @Produce json
+// @Param id path string true "service id" format(uuid)
+// @Success 200 {object} serviceResponse
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Router /services/{id} [get]
+func (h *Handler) getService(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	svc, err := h.service.GetService(r.Context(), id)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, serviceToResponse(svc))
+}
+
+// @Summary Update catalog service
+// @Description Existing subscriptions keep the name they were stored with.
+// @Tags services
+// @Accept json
+// @Produce json
+// @Param id path string true "service id" format(uuid)
+// @Param service body serviceRequest true "service"
+// @Success 200 {object} serviceResponse
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 409 {object} errorResponse
+// @Router /services/{id} [put]
+func (h *Handler) updateService(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	var req serviceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid json")
+		return
+	}
+
+	svc, err := h.service.UpdateService(r.Context(), id, req.toInput())
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, serviceToResponse(svc))
+}
+
+// @Summary Delete catalog service
+// @Tags services
+// @Param id path string true "service id" format(uuid)
+// @Success 204
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Router /services/{id} [delete]
+func (h *Handler) deleteService(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	if err := h.service.DeleteService(r.Context(), id); err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func serviceToResponse(svc domain.CatalogService) serviceResponse {
+	aliases := svc.Aliases
+	if aliases == nil {
+		aliases = []string{}
+	}
+	return serviceResponse{
+		ID:           svc.ID.String(),
+		Name:         svc.Name,
+		Aliases:      aliases,
+		DefaultPrice: svc.DefaultPrice,
+		YearlyPrice:  svc.YearlyPrice,
+		FamilyPrice:  svc.FamilyPrice,
+		VendorURL:    svc.VendorURL,
+		CategoryID:   uuidString(svc.CategoryID),
+		CreatedAt:    svc.CreatedAt.UTC().Format(time.RFC3339),
+		UpdatedAt:    svc.UpdatedAt.UTC().Format(time.RFC3339),
+	}
+}
+
+func (req serviceRequest) toInput() usecase.CatalogServiceInput {
+	return usecase.CatalogServiceInput{
+		Name:         req.Name,
+		Aliases:      req.Aliases,
+		DefaultPrice: req.DefaultPrice,
+		YearlyPrice:  req.YearlyPrice,
+		FamilyPrice:  req.FamilyPrice,
+		VendorURL:    req.VendorURL,
+		CategoryID:   req.CategoryID,
+	}
+}
@@ -0,0 +1,221 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/always-tired/crud-subscriptions/internal/domain"
+	"github.com/always-tired/crud-subscriptions/internal/usecase"
+)
+
+// @Summary Create user
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param user body userRequest true "user"
+// @Success 201 {object} userResponse
+// @Failure 400 {object} errorResponse
+// @Failure 409 {object} errorResponse
+// @Router /users [post]
+func (h *Handler) createUser(w http.ResponseWriter, r *http.Request) {
+	var req userRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid json")
+		return
+	}
+
+	u, err := h.service.CreateUser(r.Context(), req.toInput())
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, userToResponse(u))
+}
+
+// @Summary List users
+// @Tags users
+// @Produce json
+// @Param email query string false "email, ignoring case"
+// @Param limit query int false "limit"
+// @Param offset query int false "offset"
+// @Success 200 {array} userResponse
+// @Router /users [get]
+func (h *Handler) listUsers(w http.ResponseWriter, r *http.Request) {
+	var filter usecase.UserFilter
+	if v := r.URL.Query().Get("email"); v != "" {
+		filter.Email = &v
+	}
+	if v := r.URL.Query().Get("limit"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil {
+			filter.Limit = n
+		}
+	}
+	if v := r.URL.Query().Get("offset"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil {
+			filter.Offset = n
+		}
+	}
+
+	list, err := h.service.ListUsers(r.Context(), filter)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	resp := make([]userResponse, 0, len(list))
+	for _, u := range list {
+		resp = append(resp, userToResponse(u))
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// @Summary Get user
+// @Tags users
+// @Produce json
+// @Param user_id path string true "user id" format(uuid)
+// @Success 200 {object} userResponse
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Router /users/{user_id} [get]
+func (h *Handler) getUser(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "user_id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid user_id")
+		return
+	}
+
+	u, err := h.service.GetUser(r.Context(), id)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, userToResponse(u))
+}
+
+// @Summary Update user
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param user_id path string true "user id" format(uuid)
+// @Param user body userRequest true "user"
+// @Success 200 {object} userResponse
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 409 {object} errorResponse
+// @Router /users/{user_id} [put]
+func (h *Handler) updateUser(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "user_id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid user_id")
+		return
+	}
+
+	var req userRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid json")
+		return
+	}
+
+	u, err := h.service.UpdateUser(r.Context(), id, req.toInput())
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, userToResponse(u))
+}
+
+// @Summary Delete user
+// @Description Fails while the user owns subscriptions, deleted ones included; memberships in shared subscriptions are removed.
+// @Tags users
+// @Param user_id path string true "user id" format(uuid)
+// @Success 204
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 409 {object} errorResponse
+// @Router /users/{user_id} [delete]
+func (h *Handler) deleteUser(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "user_id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid user_id")
+		return
+	}
+
+	if err := h.service.DeleteUser(r.Context(), id); err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// @Summary User overview
+// @Description Subscriptions and spend of the user as of today in their timezone. Spend counts their shares of shared subscriptions.
+// @Tags users
+// @Produce json
+// @Param user_id path string true "user id" format(uuid)
+// @Success 200 {object} userOverviewResponse
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Router /users/{user_id}/overview [get]
+func (h *Handler) userOverview(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "user_id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid user_id")
+		return
+	}
+
+	o, err := h.service.UserOverview(r.Context(), id)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	resp := userOverviewResponse{
+		User:         userToResponse(o.User),
+		Statuses:     o.Statuses,
+		MonthlySpend: o.MonthlySpend,
+		ByCategory:   make([]summaryGroupResponse, 0, len(o.ByCategory)),
+		YearlySpend:  o.YearlySpend,
+	}
+	for _, g := range o.ByCategory {
+		resp.ByCategory = append(resp.ByCategory, summaryGroupToResponse(g))
+	}
+	if c := o.NextCharge; c != nil {
+		resp.NextCharge = &upcomingChargeResponse{
+			SubscriptionID: c.SubscriptionID.String(),
+			ServiceName:    c.ServiceName,
+			Date:           c.Date.Format(time.DateOnly),
+			Amount:         c.Amount,
+		}
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (req userRequest) toInput() usecase.UserInput {
+	return usecase.UserInput{
+		Name:     req.Name,
+		Email:    req.Email,
+		Currency: req.Currency,
+		Timezone: req.Timezone,
+	}
+}
+
+func userToResponse(u domain.User) userResponse {
+	return userResponse{
+		ID:        u.ID.String(),
+		Name:      u.Name,
+		Email:     u.Email,
+		Currency:  u.Currency,
+		Timezone:  u.Timezone,
+		CreatedAt: u.CreatedAt.UTC().Format(time.RFC3339),
+		UpdatedAt: u.UpdatedAt.UTC().Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,287 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/always-tired/crud-subscriptions/internal/domain"
+	"github.com/always-tired/crud-subscriptions/internal/usecase"
+)
+
+// @Summary Register webhook
+// @Description The secret is generated when omitted and is only returned here.
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param webhook body webhookRequest true "webhook"
+// @Success 201 {object} webhookResponse
+// @Failure 400 {object} errorResponse
+// @Router /webhooks [post]
+func (h *Handler) createWebhook(w http.ResponseWriter, r *http.Request) {
+	var req webhookRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid json")
+		return
+	}
+
+	hook, err := h.service.CreateWebhook(r.Context(), req.toInput())
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	resp := webhookToResponse(hook)
+	resp.Secret = hook.Secret
+	writeJSON(w, http.StatusCreated, resp)
+}
+
+// @Summary List webhooks
+// @Tags webhooks
+// @Produce json
+// @Success 200 {array} webhookResponse
+// @Router /webhooks [get]
+func (h *Handler) listWebhooks(w http.ResponseWriter, r *http.Request) {
+	list, err := h.service.ListWebhooks(r.Context())
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	resp := make([]webhookResponse, 0, len(list))
+	for _, hook := range list {
+		resp = append(resp, webhookToResponse(hook))
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// @Summary Get webhook
+// @Tags webhooks
+// @Produce json
+// @Param id path string true "webhook id" format(uuid)
+// @Success 200 {object} webhookResponse
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Router /webhooks/{id} [get]
+func (h *Handler) getWebhook(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	hook, err := h.service.GetWebhook(r.Context(), id)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, webhookToResponse(hook))
+}
+
+// @Summary Update webhook
+// @Description The secret is rotated only when a new one is sent.
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param id path string true "webhook id" format(uuid)
+// @Param webhook body webhookRequest true "webhook"
+// @Success 200 {object} webhookResponse
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Router /webhooks/{id} [put]
+func (h *Handler) updateWebhook(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	var req webhookRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid json")
+		return
+	}
+
+	hook, err := h.service.UpdateWebhook(r.Context(), id, req.toInput())
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, webhookToResponse(hook))
+}
+
+// @Summary Delete webhook
+// @Tags webhooks
+// @Param id path string true "webhook id" format(uuid)
+// @Success 204
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Router /webhooks/{id} [delete]
+func (h *Handler) deleteWebhook(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	if err := h.service.DeleteWebhook(r.Context(), id); err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// @Summary List webhook deliveries
+// @Tags webhooks
+// @Produce json
+// @Param id path string true "webhook id" format(uuid)
+// @Param status query string false "pending, retrying, succeeded or dead"
+// @Param limit query int false "limit"
+// @Param offset query int false "offset"
+// @Success 200 {array} deliveryResponse
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Router /webhooks/{id}/deliveries [get]
+func (h *Handler) listDeliveries(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	filter := usecase.DeliveryFilter{WebhookID: id}
+	if v := r.URL.Query().Get("status"); v != "" {
+		filter.Status = &v
+	}
+	filter.Limit, filter.Offset = parsePage(r)
+
+	list, err := h.service.ListDeliveries(r.Context(), filter)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	resp := make([]deliveryResponse, 0, len(list))
+	for _, d := range list {
+		resp = append(resp, deliveryToResponse(d, nil))
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// @Summary Get webhook delivery with attempts
+// @Tags webhooks
+// @Produce json
+// @Param id path string true "webhook id" format(uuid)
+// @Param delivery_id path string true "delivery id" format(uuid)
+// @Success 200 {object} deliveryResponse
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Router /webhooks/{id}/deliveries/{delivery_id} [get]
+func (h *Handler) getDelivery(w http.ResponseWriter, r *http.Request) {
+	id, deliveryID, ok := parseDeliveryPath(w, r)
+	if !ok {
+		return
+	}
+
+	d, attempts, err := h.service.GetDelivery(r.Context(), id, deliveryID)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, deliveryToResponse(d, attempts))
+}
+
+// @Summary Redeliver webhook delivery
+// @Description Queues the delivery again with a fresh attempt budget, also when it is dead.
+// @Tags webhooks
+// @Produce json
+// @Param id path string true "webhook id" format(uuid)
+// @Param delivery_id path string true "delivery id" format(uuid)
+// @Success 202 {object} deliveryResponse
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Router /webhooks/{id}/deliveries/{delivery_id}:redeliver [post]
+func (h *Handler) redeliver(w http.ResponseWriter, r *http.Request) {
+	id, deliveryID, ok := parseDeliveryPath(w, r)
+	if !ok {
+		return
+	}
+
+	d, err := h.service.Redeliver(r.Context(), id, deliveryID)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusAccepted, deliveryToResponse(d, nil))
+}
+
+func parseDeliveryPath(w http.ResponseWriter, r *http.Request) (uuid.UUID, uuid.UUID, bool) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid id")
+		return uuid.Nil, uuid.Nil, false
+	}
+	deliveryID, err := uuid.Parse(chi.URLParam(r, "delivery_id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid delivery_id")
+		return uuid.Nil, uuid.Nil, false
+	}
+	return id, deliveryID, true
+}
+
+func (req webhookRequest) toInput() usecase.WebhookInput {
+	return usecase.WebhookInput{
+		URL:    req.URL,
+		Secret: req.Secret,
+		Events: req.Events,
+		Active: req.Active,
+	}
+}
+
+func webhookToResponse(hook domain.Webhook) webhookResponse {
+	events := hook.Events
+	if events == nil {
+		events = []string{}
+	}
+	return webhookResponse{
+		ID:        hook.ID.String(),
+		URL:       hook.URL,
+		Events:    events,
+		Active:    hook.Active,
+		CreatedAt: hook.CreatedAt.UTC().Format(time.RFC3339),
+		UpdatedAt: hook.UpdatedAt.UTC().Format(time.RFC3339),
+	}
+}
+
+func deliveryToResponse(d domain.WebhookDelivery, attempts []domain.WebhookAttempt) deliveryResponse {
+	resp := deliveryResponse{
+		ID:             d.ID.String(),
+		WebhookID:      d.WebhookID.String(),
+		EventID:        d.EventID.String(),
+		EventType:      d.EventType,
+		Status:         d.Status,
+		Attempts:       d.Attempts,
+		NextAttemptAt:  d.NextAttemptAt.UTC().Format(time.RFC3339),
+		LastStatusCode: d.LastStatusCode,
+		LastError:      d.LastError,
+		CreatedAt:      d.CreatedAt.UTC().Format(time.RFC3339),
+		UpdatedAt:      d.UpdatedAt.UTC().Format(time.RFC3339),
+	}
+	for _, a := range attempts {
+		resp.AttemptLog = append(resp.AttemptLog, attemptResponse{
+			AttemptedAt: a.AttemptedAt.UTC().Format(time.RFC3339),
+			StatusCode:  a.StatusCode,
+			Error:       a.Error,
+			DurationMS:  a.Duration.Milliseconds(),
+		})
+	}
+	return resp
+}
@@ -0,0 +1,168 @@
+package usecase
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/always-tired/crud-subscriptions/internal/domain"
+)
+
+const (
+	defaultAnomalyThreshold = 50
+	maxAnomalyThreshold     = 1000
+	defaultAnomalyWindow    = 3
+	maxAnomalyWindow        = 12
+	defaultAnomalyMonths    = 12
+	maxAnomalyMonths        = 120
+)
+
+// WithAnomalyDetection sets the defaults for spend anomalies: the threshold in
+// percent above the trailing average and the number of trailing months
+// averaged. With alerts SendReminders also notifies users whose spend of the
+// current month is an anomaly. Zero values keep the defaults of 50% and 3
+// months.
+func WithAnomalyDetection(threshold, window int, alerts bool) Option {
+	return func(s *Service) {
+		s.anomalyThreshold = threshold
+		s.anomalyWindow = window
+		s.anomalyAlerts = alerts
+	}
+}
+
+// Anomaly is a month whose spend exceeds the average of the months before it
+// by at least the threshold. Change is the increase in percent.
+type Anomaly struct {
+	Month   time.Time
+	Total   int64
+	Average int64
+	Change  int64
+}
+
+// Anomalies flags the months from filter.Start through filter.End whose spend
+// jumps compared with the trailing average, e.g. after a price increase or a
+// trial that was not cancelled. Spend is charged like Summary, so a user's
+// anomalies count their shares of shared subscriptions. Months after a window
+// without any spend are not flagged, as there is nothing to compare with. The
+// range defaults to the twelve months up to the current one.
+func (s *Service) Anomalies(ctx context.Context, filter AnomalyFilter) ([]Anomaly, error) {
+	threshold, window, err := s.anomalySettings(filter.Threshold, filter.Window)
+	if err != nil {
+		return nil, err
+	}
+
+	end := filter.End
+	if end.IsZero() {
+		end = StartOfMonth(s.now())
+	}
+	start := filter.Start
+	if start.IsZero() {
+		start = end.AddDate(0, 1-defaultAnomalyMonths, 0)
+	}
+	if end.Before(start) {
+		return nil, fmt.Errorf("%w: end must be after start", domain.ErrInvalidArgument)
+	}
+	if end.After(start.AddDate(0, maxAnomalyMonths-1, 0)) {
+		return nil, fmt.Errorf("%w: at most %d months can be checked", domain.ErrInvalidArgument, maxAnomalyMonths)
+	}
+
+	serviceName, err := s.canonicalServiceName(ctx, filter.ServiceName)
+	if err != nil {
+		return nil, err
+	}
+	totals, err := s.repo.MonthlySummary(ctx, SummaryFilter{
+		UserID:      filter.UserID,
+		ServiceName: serviceName,
+		Start:       start.AddDate(0, -window, 0),
+		End:         end,
+	})
+	if err != nil {
+		s.log.Error("monthly summary for anomalies", "error", err)
+		return nil, err
+	}
+	return detectAnomalies(totals, window, threshold), nil
+}
+
+func (s *Service) anomalySettings(threshold, window int) (int, int, error) {
+	if threshold == 0 {
+		threshold = cmp.Or(s.anomalyThreshold, defaultAnomalyThreshold)
+	}
+	if threshold < 0 || threshold > maxAnomalyThreshold {
+		return 0, 0, fmt.Errorf("%w: threshold must be between 1 and %d percent", domain.ErrInvalidArgument, maxAnomalyThreshold)
+	}
+	if window == 0 {
+		window = cmp.Or(s.anomalyWindow, defaultAnomalyWindow)
+	}
+	if window < 0 || window > maxAnomalyWindow {
+		return 0, 0, fmt.Errorf("%w: window must be between 1 and %d months", domain.ErrInvalidArgument, maxAnomalyWindow)
+	}
+	return threshold, window, nil
+}
+
+// detectAnomalies compares every month of totals after the first window ones
+// with the average of the window months before it. totals must have every
+// month, including those without spend. Months whose window has no spend are
+// skipped: the first subscription of a user is not an anomaly.
+func detectAnomalies(totals []MonthlyTotal, window, threshold int) []Anomaly {
+	res := make([]Anomaly, 0)
+	for i := window; i < len(totals); i++ {
+		var sum int64
+		for _, t := range totals[i-window : i] {
+			sum += t.Total
+		}
+		if sum == 0 {
+			continue
+		}
+		total := totals[i].Total
+		// Compare total with sum/window without rounding the average.
+		increase := total*int64(window) - sum
+		if increase*100 < sum*int64(threshold) {
+			continue
+		}
+		res = append(res, Anomaly{
+			Month:   totals[i].Month,
+			Total:   total,
+			Average: sum / int64(window),
+			Change:  increase * 100 / sum,
+		})
+	}
+	return res
+}
+
+// anomalyReminders returns a reminder for every user whose spend of month is
+// an anomaly. The spend of all users is read in one query.
+func (s *Service) anomalyReminders(ctx context.Context, month time.Time) ([]domain.Reminder, error) {
+	if !s.anomalyAlerts {
+		return nil, nil
+	}
+	threshold, window, err := s.anomalySettings(0, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	start := month.AddDate(0, -window, 0)
+	grouped, err := s.repo.MonthlySummaryByUser(ctx, SummaryFilter{Start: start, End: month})
+	if err != nil {
+		return nil, err
+	}
+	var res []domain.Reminder
+	for _, series := range groupSeries(grouped, start, window+1) {
+		userID, err := uuid.Parse(series.key)
+		if err != nil {
+			return nil, err
+		}
+		for _, a := range detectAnomalies(series.totals, window, threshold) {
+			res = append(res, domain.Reminder{
+				Kind:    domain.ReminderSpendAnomaly,
+				UserID:  userID,
+				Price:   int(a.Total),
+				Average: int(a.Average),
+				DueDate: a.Month,
+			})
+		}
+	}
+	return res, nil
+}
@@ -0,0 +1,173 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/always-tired/crud-subscriptions/internal/domain"
+)
+
+type AuditRepository interface {
+	AppendAudit(ctx context.Context, entry domain.AuditEntry) error
+	ListAudit(ctx context.Context, filter AuditFilter) ([]domain.AuditEntry, error)
+}
+
+// subscriptionSnapshot is the JSON form of a subscription stored in the audit
+// log. It mirrors the API representation so entries read like requests.
+type subscriptionSnapshot struct {
+	ID              string             `json:"id"`
+	ServiceName     string             `json:"service_name"`
+	Price           int                `json:"price"`
+	UserID          string             `json:"user_id"`
+	StartDate       string             `json:"start_date"`
+	EndDate         *string            `json:"end_date,omitempty"`
+	TrialEndDate    *string            `json:"trial_end_date,omitempty"`
+	CategoryID      *string            `json:"category_id,omitempty"`
+	Tags            []string           `json:"tags,omitempty"`
+	Pauses          []pauseSnapshot    `json:"pauses,omitempty"`
+	Discounts       []discountSnapshot `json:"discounts,omitempty"`
+	SplitRule       string             `json:"split_rule,omitempty"`
+	Members         []memberSnapshot   `json:"members,omitempty"`
+	PaymentMethodID *string            `json:"payment_method_id,omitempty"`
+	TaxRate         *int               `json:"tax_rate,omitempty"`
+	TaxInclusive    bool               `json:"tax_inclusive,omitempty"`
+	Unused          bool               `json:"unused,omitempty"`
+	CancelReason    string             `json:"cancel_reason,omitempty"`
+}
+
+type pauseSnapshot struct {
+	From  string  `json:"from"`
+	Until *string `json:"until,omitempty"`
+}
+
+type discountSnapshot struct {
+	ID    string  `json:"id"`
+	Kind  string  `json:"kind"`
+	Value int     `json:"value"`
+	From  string  `json:"from"`
+	Until *string `json:"until,omitempty"`
+}
+
+type memberSnapshot struct {
+	UserID string `json:"user_id"`
+	Share  int    `json:"share,omitempty"`
+}
+
+type priceSnapshot struct {
+	EffectiveFrom string `json:"effective_from"`
+	Price         int    `json:"price"`
+}
+
+func snapshotSubscription(sub domain.Subscription) subscriptionSnapshot {
+	snap := subscriptionSnapshot{
+		ID:          sub.ID.String(),
+		ServiceName: sub.ServiceName,
+		Price:       sub.Price,
+		UserID:      sub.UserID.String(),
+		StartDate:   FormatMonthDate(sub.StartDate),
+	}
+	if sub.EndDate != nil {
+		e := FormatMonthDate(*sub.EndDate)
+		snap.EndDate = &e
+	}
+	if sub.TrialEndDate != nil {
+		t := FormatMonthDate(*sub.TrialEndDate)
+		snap.TrialEndDate = &t
+	}
+	if sub.CategoryID != nil {
+		c := sub.CategoryID.String()
+		snap.CategoryID = &c
+	}
+	snap.Tags = sub.Tags
+	if sub.PaymentMethodID != nil {
+		p := sub.PaymentMethodID.String()
+		snap.PaymentMethodID = &p
+	}
+	snap.TaxRate = sub.TaxRate
+	snap.TaxInclusive = sub.TaxInclusive
+	snap.Unused = sub.Unused
+	snap.CancelReason = sub.CancelReason
+	for _, p := range sub.Pauses {
+		ps := pauseSnapshot{From: FormatMonthDate(p.From)}
+		if p.Until != nil {
+			u := FormatMonthDate(*p.Until)
+			ps.Until = &u
+		}
+		snap.Pauses = append(snap.Pauses, ps)
+	}
+	for _, d := range sub.Discounts {
+		ds := discountSnapshot{ID: d.ID.String(), Kind: d.Kind, Value: d.Value, From: FormatMonthDate(d.From)}
+		if d.Until != nil {
+			u := FormatMonthDate(*d.Until)
+			ds.Until = &u
+		}
+		snap.Discounts = append(snap.Discounts, ds)
+	}
+	snap.SplitRule = sub.SplitRule
+	for _, m := range sub.Members {
+		snap.Members = append(snap.Members, memberSnapshot{UserID: m.UserID.String(), Share: m.Share})
+	}
+	return snap
+}
+
+func snapshotPrices(prices []domain.PricePeriod) []priceSnapshot {
+	res := make([]priceSnapshot, 0, len(prices))
+	for _, p := range prices {
+		res = append(res, priceSnapshot{EffectiveFrom: FormatMonthDate(p.EffectiveFrom), Price: p.Price})
+	}
+	return res
+}
+
+// recordAudit appends an audit entry using the actor and request ID found in
+// ctx. It must be called inside the transaction of the change it describes.
+// A nil before or after is stored as SQL NULL.
+func (s *Service) recordAudit(ctx context.Context, subID uuid.UUID, action string, before, after any) error {
+	if s.audit == nil {
+		return nil
+	}
+
+	entry := domain.AuditEntry{
+		ID:             uuid.New(),
+		SubscriptionID: subID,
+		Action:         action,
+		Actor:          ActorFrom(ctx),
+		RequestID:      RequestIDFrom(ctx),
+	}
+	var err error
+	if before != nil {
+		if entry.Before, err = json.Marshal(before); err != nil {
+			return fmt.Errorf("marshal audit snapshot: %w", err)
+		}
+	}
+	if after != nil {
+		if entry.After, err = json.Marshal(after); err != nil {
+			return fmt.Errorf("marshal audit snapshot: %w", err)
+		}
+	}
+	return s.audit.AppendAudit(ctx, entry)
+}
+
+// History returns the audit trail of one subscription, oldest first. It also
+// works for deleted subscriptions.
+func (s *Service) History(ctx context.Context, id uuid.UUID, limit, offset int) ([]domain.AuditEntry, error) {
+	return s.Audit(ctx, AuditFilter{SubscriptionID: &id, Limit: limit, Offset: offset})
+}
+
+func (s *Service) Audit(ctx context.Context, filter AuditFilter) ([]domain.AuditEntry, error) {
+	if s.audit == nil {
+		return []domain.AuditEntry{}, nil
+	}
+	if filter.From != nil && filter.To != nil && filter.To.Before(*filter.From) {
+		return nil, fmt.Errorf("%w: to must be after from", domain.ErrInvalidArgument)
+	}
+
+	list, err := s.audit.ListAudit(ctx, filter)
+	if err != nil {
+		s.log.Error("list audit", "error", err)
+		return nil, err
+	}
+	return list, nil
+}
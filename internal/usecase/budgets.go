@@ -0,0 +1,314 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/always-tired/crud-subscriptions/internal/domain"
+)
+
+// budgetHorizon is how many months ahead open-ended subscriptions are checked
+// against budgets when they change.
+const budgetHorizon = 12
+
+type BudgetRepository interface {
+	CreateBudget(ctx context.Context, b domain.Budget) (domain.Budget, error)
+	GetBudget(ctx context.Context, id uuid.UUID) (domain.Budget, error)
+	UpdateBudget(ctx context.Context, b domain.Budget) (domain.Budget, error)
+	DeleteBudget(ctx context.Context, id uuid.UUID) error
+	// ListBudgets returns the budgets of a user, or all budgets when userID
+	// is nil.
+	ListBudgets(ctx context.Context, userID *uuid.UUID) ([]domain.Budget, error)
+}
+
+// BudgetStatus compares a budget with the spend of every month in a range.
+type BudgetStatus struct {
+	Budget domain.Budget
+	Months []BudgetMonth
+}
+
+type BudgetMonth struct {
+	Month    time.Time
+	Spent    int64
+	Limit    int
+	Exceeded bool
+}
+
+type budgetExceededPayload struct {
+	BudgetID     uuid.UUID            `json:"budget_id"`
+	CategoryID   *uuid.UUID           `json:"category_id,omitempty"`
+	Month        string               `json:"month"`
+	MonthlyLimit int                  `json:"monthly_limit"`
+	Spent        int64                `json:"spent"`
+	Subscription subscriptionSnapshot `json:"subscription"`
+}
+
+var errBudgetsDisabled = errors.New("budgets are not configured")
+
+// WithBudgets enables budgets. Together with WithOutbox, creates and updates
+// that push a month over a budget emit BudgetExceeded events.
+func WithBudgets(repo BudgetRepository) Option {
+	return func(s *Service) {
+		s.budgets = repo
+	}
+}
+
+func (s *Service) CreateBudget(ctx context.Context, input BudgetInput) (domain.Budget, error) {
+	if s.budgets == nil {
+		return domain.Budget{}, errBudgetsDisabled
+	}
+	b, err := validateBudgetInput(input)
+	if err != nil {
+		return domain.Budget{}, err
+	}
+	b.ID = uuid.New()
+	b.CreatedAt = s.now()
+	b.UpdatedAt = b.CreatedAt
+
+	created, err := s.budgets.CreateBudget(ctx, b)
+	if err != nil {
+		s.log.Error("create budget", "error", err)
+		return domain.Budget{}, err
+	}
+	return created, nil
+}
+
+func (s *Service) GetBudget(ctx context.Context, id uuid.UUID) (domain.Budget, error) {
+	if s.budgets == nil {
+		return domain.Budget{}, errBudgetsDisabled
+	}
+	b, err := s.budgets.GetBudget(ctx, id)
+	if err != nil {
+		s.log.Error("get budget", "error", err)
+		return domain.Budget{}, err
+	}
+	return b, nil
+}
+
+func (s *Service) ListBudgets(ctx context.Context, userID *uuid.UUID) ([]domain.Budget, error) {
+	if s.budgets == nil {
+		return nil, errBudgetsDisabled
+	}
+	list, err := s.budgets.ListBudgets(ctx, userID)
+	if err != nil {
+		s.log.Error("list budgets", "error", err)
+		return nil, err
+	}
+	return list, nil
+}
+
+func (s *Service) UpdateBudget(ctx context.Context, id uuid.UUID, input BudgetInput) (domain.Budget, error) {
+	if s.budgets == nil {
+		return domain.Budget{}, errBudgetsDisabled
+	}
+	b, err := validateBudgetInput(input)
+	if err != nil {
+		return domain.Budget{}, err
+	}
+	b.ID = id
+	b.UpdatedAt = s.now()
+
+	updated, err := s.budgets.UpdateBudget(ctx, b)
+	if err != nil {
+		s.log.Error("update budget", "error", err)
+		return domain.Budget{}, err
+	}
+	return updated, nil
+}
+
+func (s *Service) DeleteBudget(ctx context.Context, id uuid.UUID) error {
+	if s.budgets == nil {
+		return errBudgetsDisabled
+	}
+	if err := s.budgets.DeleteBudget(ctx, id); err != nil {
+		s.log.Error("delete budget", "error", err)
+		return err
+	}
+	return nil
+}
+
+// BudgetStatus compares every budget of the user with the spend of each month
+// from start to end under it, charged like Summary. A zero start means the
+// current month and a zero end means start.
+func (s *Service) BudgetStatus(ctx context.Context, userID uuid.UUID, start, end time.Time) ([]BudgetStatus, error) {
+	if s.budgets == nil {
+		return nil, errBudgetsDisabled
+	}
+	if start.IsZero() {
+		start = StartOfMonth(s.now())
+	}
+	if end.IsZero() {
+		end = start
+	}
+	if end.Before(start) {
+		return nil, fmt.Errorf("%w: end must be after start", domain.ErrInvalidArgument)
+	}
+
+	budgets, err := s.budgets.ListBudgets(ctx, &userID)
+	if err != nil {
+		s.log.Error("list budgets", "error", err)
+		return nil, err
+	}
+	res := make([]BudgetStatus, 0, len(budgets))
+	if len(budgets) == 0 {
+		return res, nil
+	}
+
+	for _, b := range budgets {
+		totals, err := s.repo.MonthlySummary(ctx, budgetFilter(b, start, end))
+		if err != nil {
+			s.log.Error("budget status", "error", err)
+			return nil, err
+		}
+		status := BudgetStatus{Budget: b, Months: make([]BudgetMonth, 0, len(totals))}
+		for _, t := range totals {
+			status.Months = append(status.Months, BudgetMonth{
+				Month:    t.Month,
+				Spent:    t.Total,
+				Limit:    b.MonthlyLimit,
+				Exceeded: t.Total > int64(b.MonthlyLimit),
+			})
+		}
+		res = append(res, status)
+	}
+	return res, nil
+}
+
+// budgetFilter selects the spend a budget applies to.
+func budgetFilter(b domain.Budget, start, end time.Time) SummaryFilter {
+	filter := SummaryFilter{UserID: &b.UserID, Start: start, End: end}
+	if b.CategoryID != nil {
+		category := b.CategoryID.String()
+		filter.Category = &category
+	}
+	return filter
+}
+
+// budgetWatch remembers the monthly spend under a budget before a change, so
+// that months pushed over the budget by the change can be found.
+type budgetWatch struct {
+	budget domain.Budget
+	filter SummaryFilter
+	before []MonthlyTotal
+}
+
+// watchBudgets snapshots the spend under the budgets of the owners and members
+// of subs over the months the subscriptions cover. It must run in the transaction of the
+// change, before the change is written.
+func (s *Service) watchBudgets(ctx context.Context, subs ...domain.Subscription) ([]budgetWatch, error) {
+	if s.budgets == nil || s.outbox == nil {
+		return nil, nil
+	}
+
+	var watches []budgetWatch
+	for _, userID := range subscriptionUsers(subs) {
+		budgets, err := s.budgets.ListBudgets(ctx, &userID)
+		if err != nil {
+			return nil, err
+		}
+		if len(budgets) == 0 {
+			continue
+		}
+
+		var start, end time.Time
+		for _, sub := range subs {
+			if !slices.Contains(sub.Users(), userID) {
+				continue
+			}
+			subEnd := StartOfMonth(s.now()).AddDate(0, budgetHorizon-1, 0)
+			if sub.EndDate != nil {
+				subEnd = *sub.EndDate
+			}
+			if start.IsZero() || sub.StartDate.Before(start) {
+				start = sub.StartDate
+			}
+			if subEnd.After(end) {
+				end = subEnd
+			}
+		}
+		if end.Before(start) {
+			end = start
+		}
+
+		for _, b := range budgets {
+			filter := budgetFilter(b, start, end)
+			before, err := s.repo.MonthlySummary(ctx, filter)
+			if err != nil {
+				return nil, err
+			}
+			watches = append(watches, budgetWatch{budget: b, filter: filter, before: before})
+		}
+	}
+	return watches, nil
+}
+
+// budgetEvents returns a BudgetExceeded event for every budget and month that
+// was within the budget before the change and is over it now.
+func (s *Service) budgetEvents(ctx context.Context, watches []budgetWatch, changed domain.Subscription) ([]domain.Event, error) {
+	var events []domain.Event
+	for _, w := range watches {
+		after, err := s.repo.MonthlySummary(ctx, w.filter)
+		if err != nil {
+			return nil, err
+		}
+		limit := int64(w.budget.MonthlyLimit)
+		for i, t := range after {
+			if w.before[i].Total > limit || t.Total <= limit {
+				continue
+			}
+			e, err := newEvent(domain.EventBudgetExceeded, changed, budgetExceededPayload{
+				BudgetID:     w.budget.ID,
+				CategoryID:   w.budget.CategoryID,
+				Month:        FormatMonthDate(t.Month),
+				MonthlyLimit: w.budget.MonthlyLimit,
+				Spent:        t.Total,
+				Subscription: snapshotSubscription(changed),
+			})
+			if err != nil {
+				return nil, err
+			}
+			e.UserID = w.budget.UserID
+			events = append(events, e)
+		}
+	}
+	return events, nil
+}
+
+func subscriptionUsers(subs []domain.Subscription) []uuid.UUID {
+	var users []uuid.UUID
+	seen := make(map[uuid.UUID]bool)
+	for _, sub := range subs {
+		for _, id := range sub.Users() {
+			if !seen[id] {
+				seen[id] = true
+				users = append(users, id)
+			}
+		}
+	}
+	return users
+}
+
+func validateBudgetInput(input BudgetInput) (domain.Budget, error) {
+	uid, err := uuid.Parse(input.UserID)
+	if err != nil {
+		return domain.Budget{}, fmt.Errorf("%w: invalid user_id", domain.ErrInvalidArgument)
+	}
+	if input.MonthlyLimit <= 0 {
+		return domain.Budget{}, fmt.Errorf("%w: monthly_limit must be positive integer", domain.ErrInvalidArgument)
+	}
+	b := domain.Budget{UserID: uid, MonthlyLimit: input.MonthlyLimit}
+	if input.CategoryID != nil && strings.TrimSpace(*input.CategoryID) != "" {
+		id, err := uuid.Parse(strings.TrimSpace(*input.CategoryID))
+		if err != nil {
+			return domain.Budget{}, fmt.Errorf("%w: invalid category_id", domain.ErrInvalidArgument)
+		}
+		b.CategoryID = &id
+	}
+	return b, nil
+}
@@ -0,0 +1,103 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/always-tired/crud-subscriptions/internal/domain"
+)
+
+// CalendarTokenRepository stores the hashed secret that grants access to a
+// user's renewal calendar feed. Only one token per user is kept.
+type CalendarTokenRepository interface {
+	SaveCalendarToken(ctx context.Context, userID uuid.UUID, tokenHash string) error
+	GetCalendarTokenHash(ctx context.Context, userID uuid.UUID) (string, error)
+	DeleteCalendarToken(ctx context.Context, userID uuid.UUID) error
+}
+
+var errCalendarDisabled = errors.New("calendar feed is not configured")
+
+// IssueCalendarToken creates a new feed token for the user, replacing (and
+// thereby revoking) any previous one. The plain token is returned only once.
+func (s *Service) IssueCalendarToken(ctx context.Context, userID uuid.UUID) (string, error) {
+	if s.calendars == nil {
+		return "", errCalendarDisabled
+	}
+	if userID == uuid.Nil {
+		return "", fmt.Errorf("%w: user_id is required", domain.ErrInvalidArgument)
+	}
+
+	token, err := randomToken()
+	if err != nil {
+		return "", fmt.Errorf("generate calendar token: %w", err)
+	}
+
+	if err := s.calendars.SaveCalendarToken(ctx, userID, hashToken(token)); err != nil {
+		s.log.Error("save calendar token", "error", err)
+		return "", err
+	}
+	return token, nil
+}
+
+func (s *Service) RevokeCalendarToken(ctx context.Context, userID uuid.UUID) error {
+	if s.calendars == nil {
+		return errCalendarDisabled
+	}
+	if err := s.calendars.DeleteCalendarToken(ctx, userID); err != nil {
+		s.log.Error("revoke calendar token", "error", err)
+		return err
+	}
+	return nil
+}
+
+// RenewalFeed returns the user's subscriptions that are still running, after
+// checking the feed token. A missing or wrong token yields ErrForbidden.
+func (s *Service) RenewalFeed(ctx context.Context, userID uuid.UUID, token string) ([]domain.Subscription, error) {
+	if s.calendars == nil {
+		return nil, errCalendarDisabled
+	}
+	if token == "" {
+		return nil, domain.ErrForbidden
+	}
+
+	stored, err := s.calendars.GetCalendarTokenHash(ctx, userID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil, domain.ErrForbidden
+		}
+		s.log.Error("get calendar token", "error", err)
+		return nil, err
+	}
+	if subtle.ConstantTimeCompare([]byte(stored), []byte(hashToken(token))) != 1 {
+		return nil, domain.ErrForbidden
+	}
+
+	list, err := s.repo.ListActive(ctx, &userID, s.now())
+	if err != nil {
+		s.log.Error("list renewal feed", "error", err)
+		return nil, err
+	}
+	return list, nil
+}
+
+// randomToken returns 256 random bits, URL-safe encoded.
+func randomToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
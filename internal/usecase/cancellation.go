@@ -0,0 +1,149 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/always-tired/crud-subscriptions/internal/domain"
+)
+
+// Cancellation timings besides an explicit MM-YYYY month, see Service.Cancel.
+const (
+	CancelImmediately = "immediately"
+	CancelEndOfPeriod = "end_of_period"
+)
+
+// Cancel ends the subscription. input.When picks the last billed month:
+// end_of_period (the default) keeps the current month, which has already been
+// billed, or the running trial; immediately ends with the previous month so
+// the current one is not charged; MM-YYYY names the month, which must not lie
+// before the current one. The reason must be one of domain.CancelReasons.
+// The subscription is locked while the end is checked and stored, so pauses
+// and discounts added meanwhile cannot run past it.
+func (s *Service) Cancel(ctx context.Context, id uuid.UUID, input CancelInput) (domain.Subscription, error) {
+	reason := strings.TrimSpace(input.Reason)
+	if !slices.Contains(domain.CancelReasons, reason) {
+		return domain.Subscription{}, fmt.Errorf("%w: reason must be one of %s", domain.ErrInvalidArgument, strings.Join(domain.CancelReasons, ", "))
+	}
+
+	var cancelled domain.Subscription
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		before, err := s.repo.GetForUpdate(ctx, id)
+		if err != nil {
+			return err
+		}
+		now := s.now()
+		month := StartOfMonth(now)
+		if before.StatusAt(month) == domain.StatusEnded {
+			return fmt.Errorf("%w: subscription has already ended", domain.ErrInvalidArgument)
+		}
+		end, err := cancelEnd(before, strings.TrimSpace(input.When), month)
+		if err != nil {
+			return err
+		}
+		if cancelled, err = s.repo.Cancel(ctx, id, end, reason, now); err != nil {
+			return err
+		}
+		if err := s.recordAudit(ctx, id, domain.AuditActionCancel, snapshotSubscription(before), snapshotSubscription(cancelled)); err != nil {
+			return err
+		}
+		e, err := subscriptionEvent(domain.EventSubscriptionCancelled, cancelled)
+		if err != nil {
+			return err
+		}
+		return s.emit(ctx, e)
+	})
+	if err != nil {
+		s.log.Error("cancel subscription", "error", err)
+		return domain.Subscription{}, err
+	}
+	return cancelled, nil
+}
+
+// Reactivate undoes a cancellation that has not taken effect yet, so the
+// subscription runs on without an end date. The end date of a fixed term that
+// was never cancelled stays.
+func (s *Service) Reactivate(ctx context.Context, id uuid.UUID) (domain.Subscription, error) {
+	var reactivated domain.Subscription
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		before, err := s.repo.GetForUpdate(ctx, id)
+		if err != nil {
+			return err
+		}
+		now := s.now()
+		month := StartOfMonth(now)
+		if before.EndDate == nil || before.CancelledAt == nil {
+			return fmt.Errorf("%w: subscription is not cancelled", domain.ErrInvalidArgument)
+		}
+		if before.EndDate.Before(month) {
+			return fmt.Errorf("%w: cancellation has already taken effect", domain.ErrInvalidArgument)
+		}
+
+		open := before
+		open.EndDate = nil
+		watches, err := s.watchBudgets(ctx, before, open)
+		if err != nil {
+			return err
+		}
+		if reactivated, err = s.repo.Reactivate(ctx, id, now); err != nil {
+			return err
+		}
+		if err := s.recordAudit(ctx, id, domain.AuditActionReactivate, snapshotSubscription(before), snapshotSubscription(reactivated)); err != nil {
+			return err
+		}
+		e, err := subscriptionEvent(domain.EventSubscriptionReactivated, reactivated)
+		if err != nil {
+			return err
+		}
+		exceeded, err := s.budgetEvents(ctx, watches, reactivated)
+		if err != nil {
+			return err
+		}
+		return s.emit(ctx, append([]domain.Event{e}, exceeded...)...)
+	})
+	if err != nil {
+		s.log.Error("reactivate subscription", "error", err)
+		return domain.Subscription{}, err
+	}
+	return reactivated, nil
+}
+
+// cancelEnd returns the last billed month for a cancellation in month. An
+// explicit month must not lie before month, as that would rewrite months
+// already billed. An end before the start is only possible within a trial,
+// where it is moved to the start as nothing has been charged; otherwise the
+// subscription should be deleted instead.
+func cancelEnd(sub domain.Subscription, when string, month time.Time) (time.Time, error) {
+	var end time.Time
+	switch when {
+	case "", CancelEndOfPeriod:
+		end = month
+		if sub.TrialEndDate != nil && !sub.TrialEndDate.Before(month) {
+			end = *sub.TrialEndDate
+		}
+	case CancelImmediately:
+		end = month.AddDate(0, -1, 0)
+	default:
+		t, err := ParseMonthDate(when)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("%w: when must be %s, %s or MM-YYYY", domain.ErrInvalidArgument, CancelImmediately, CancelEndOfPeriod)
+		}
+		if t.Before(month) {
+			return time.Time{}, fmt.Errorf("%w: when must not be before %s", domain.ErrInvalidArgument, FormatMonthDate(month))
+		}
+		end = t
+	}
+
+	if end.Before(sub.StartDate) {
+		if sub.TrialEndDate == nil {
+			return time.Time{}, fmt.Errorf("%w: subscription starts in %s, delete it instead", domain.ErrInvalidArgument, FormatMonthDate(sub.StartDate))
+		}
+		end = sub.StartDate
+	}
+	return end, nil
+}
@@ -0,0 +1,316 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"unicode"
+
+	"github.com/google/uuid"
+
+	"github.com/always-tired/crud-subscriptions/internal/domain"
+)
+
+type ServiceCatalogRepository interface {
+	CreateService(ctx context.Context, svc domain.CatalogService) (domain.CatalogService, error)
+	GetService(ctx context.Context, id uuid.UUID) (domain.CatalogService, error)
+	ListServices(ctx context.Context) ([]domain.CatalogService, error)
+	UpdateService(ctx context.Context, svc domain.CatalogService) (domain.CatalogService, error)
+	DeleteService(ctx context.Context, id uuid.UUID) error
+	// SetServiceNames replaces the normalized names and aliases the service is
+	// found by. A name taken by another service fails with ErrDuplicate.
+	SetServiceNames(ctx context.Context, id uuid.UUID, names []string) error
+	// FindService finds the entry whose normalized name or alias is name;
+	// failing that, the single entry within maxDist edits, so typos resolve
+	// too.
+	FindService(ctx context.Context, name string, maxDist int) (domain.CatalogService, bool, error)
+}
+
+var errCatalogDisabled = errors.New("service catalog is not configured")
+
+// WithServiceCatalog resolves service names of subscriptions against the
+// catalog. In strict mode subscriptions for services not in the catalog are
+// rejected; otherwise their names are kept as given.
+func WithServiceCatalog(repo ServiceCatalogRepository, strict bool) Option {
+	return func(s *Service) {
+		s.catalog = repo
+		s.catalogStrict = strict
+	}
+}
+
+func (s *Service) CreateService(ctx context.Context, input CatalogServiceInput) (domain.CatalogService, error) {
+	if s.catalog == nil {
+		return domain.CatalogService{}, errCatalogDisabled
+	}
+	svc, err := validateCatalogServiceInput(input)
+	if err != nil {
+		return domain.CatalogService{}, err
+	}
+	svc.ID = uuid.New()
+
+	var created domain.CatalogService
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		if created, err = s.catalog.CreateService(ctx, svc); err != nil {
+			return err
+		}
+		return s.setCatalogNames(ctx, svc)
+	})
+	if err != nil {
+		s.log.Error("create service", "error", err)
+		return domain.CatalogService{}, err
+	}
+	return created, nil
+}
+
+func (s *Service) GetService(ctx context.Context, id uuid.UUID) (domain.CatalogService, error) {
+	if s.catalog == nil {
+		return domain.CatalogService{}, errCatalogDisabled
+	}
+	svc, err := s.catalog.GetService(ctx, id)
+	if err != nil {
+		s.log.Error("get service", "error", err)
+		return domain.CatalogService{}, err
+	}
+	return svc, nil
+}
+
+func (s *Service) ListServices(ctx context.Context) ([]domain.CatalogService, error) {
+	if s.catalog == nil {
+		return nil, errCatalogDisabled
+	}
+	list, err := s.catalog.ListServices(ctx)
+	if err != nil {
+		s.log.Error("list services", "error", err)
+		return nil, err
+	}
+	return list, nil
+}
+
+func (s *Service) UpdateService(ctx context.Context, id uuid.UUID, input CatalogServiceInput) (domain.CatalogService, error) {
+	if s.catalog == nil {
+		return domain.CatalogService{}, errCatalogDisabled
+	}
+	svc, err := validateCatalogServiceInput(input)
+	if err != nil {
+		return domain.CatalogService{}, err
+	}
+	svc.ID = id
+
+	var updated domain.CatalogService
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		if updated, err = s.catalog.UpdateService(ctx, svc); err != nil {
+			return err
+		}
+		return s.setCatalogNames(ctx, svc)
+	})
+	if err != nil {
+		s.log.Error("update service", "error", err)
+		return domain.CatalogService{}, err
+	}
+	return updated, nil
+}
+
+func (s *Service) DeleteService(ctx context.Context, id uuid.UUID) error {
+	if s.catalog == nil {
+		return errCatalogDisabled
+	}
+	if err := s.catalog.DeleteService(ctx, id); err != nil {
+		s.log.Error("delete service", "error", err)
+		return err
+	}
+	return nil
+}
+
+// ResolveService returns the catalog entry a service name resolves to.
+func (s *Service) ResolveService(ctx context.Context, name string) (domain.CatalogService, error) {
+	if s.catalog == nil {
+		return domain.CatalogService{}, errCatalogDisabled
+	}
+	svc, ok, err := s.lookupService(ctx, name)
+	if err != nil {
+		return domain.CatalogService{}, err
+	}
+	if !ok {
+		return domain.CatalogService{}, domain.ErrNotFound
+	}
+	return svc, nil
+}
+
+// applyCatalog replaces the service name of sub with the canonical one and
+// fills in the category and, when no price was given, the default price.
+func (s *Service) applyCatalog(ctx context.Context, sub *domain.Subscription) error {
+	if s.catalog == nil {
+		return nil
+	}
+	svc, ok, err := s.lookupService(ctx, sub.ServiceName)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		if s.catalogStrict {
+			return fmt.Errorf("%w: unknown service_name %q", domain.ErrInvalidArgument, sub.ServiceName)
+		}
+		return nil
+	}
+
+	sub.ServiceName = svc.Name
+	if sub.CategoryID == nil {
+		sub.CategoryID = svc.CategoryID
+	}
+	if sub.Price == 0 && svc.DefaultPrice != nil {
+		sub.Price = *svc.DefaultPrice
+	}
+	return nil
+}
+
+// canonicalServiceName resolves a service name filter so that aliases find
+// the subscriptions stored under the canonical name.
+func (s *Service) canonicalServiceName(ctx context.Context, name *string) (*string, error) {
+	if s.catalog == nil || name == nil {
+		return name, nil
+	}
+	svc, ok, err := s.lookupService(ctx, *name)
+	if err != nil || !ok {
+		return name, err
+	}
+	return &svc.Name, nil
+}
+
+func (s *Service) lookupService(ctx context.Context, name string) (domain.CatalogService, bool, error) {
+	norm := normalizeServiceName(name)
+	if norm == "" {
+		return domain.CatalogService{}, false, nil
+	}
+	svc, ok, err := s.catalog.FindService(ctx, norm, maxServiceNameDistance(norm))
+	if err != nil {
+		s.log.Error("find service", "error", err)
+		return domain.CatalogService{}, false, err
+	}
+	return svc, ok, nil
+}
+
+// resolveServices looks every distinct service name of subs up in the catalog
+// through lookupService and returns the entries found, by service name.
+// Without a catalog nothing is found.
+func (s *Service) resolveServices(ctx context.Context, subs []domain.Subscription) (map[string]domain.CatalogService, error) {
+	found := make(map[string]domain.CatalogService)
+	if s.catalog == nil {
+		return found, nil
+	}
+	seen := make(map[string]bool)
+	for _, sub := range subs {
+		if seen[sub.ServiceName] {
+			continue
+		}
+		seen[sub.ServiceName] = true
+		svc, ok, err := s.lookupService(ctx, sub.ServiceName)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			found[sub.ServiceName] = svc
+		}
+	}
+	return found, nil
+}
+
+// setCatalogNames stores the normalized name and aliases of svc. The store
+// rejects a name that already resolves to another catalog entry, so that
+// concurrent writes cannot both claim it.
+func (s *Service) setCatalogNames(ctx context.Context, svc domain.CatalogService) error {
+	names := []string{normalizeServiceName(svc.Name)}
+	for _, a := range svc.Aliases {
+		names = append(names, normalizeServiceName(a))
+	}
+	err := s.catalog.SetServiceNames(ctx, svc.ID, names)
+	if errors.Is(err, domain.ErrDuplicate) {
+		return fmt.Errorf("%w: the name or an alias is already used by another service", domain.ErrDuplicate)
+	}
+	return err
+}
+
+// maxServiceNameDistance is how many edits a typo of the normalized name may
+// have: one per five characters.
+func maxServiceNameDistance(norm string) int {
+	return len([]rune(norm)) / 5
+}
+
+// normalizeServiceName lowercases name and turns every run of characters
+// other than letters and digits into a single space.
+func normalizeServiceName(name string) string {
+	var b strings.Builder
+	space := false
+	for _, r := range strings.ToLower(name) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if space && b.Len() > 0 {
+				b.WriteByte(' ')
+			}
+			space = false
+			b.WriteRune(r)
+			continue
+		}
+		space = true
+	}
+	return b.String()
+}
+
+func validateCatalogServiceInput(input CatalogServiceInput) (domain.CatalogService, error) {
+	name := strings.TrimSpace(input.Name)
+	if len(name) < 3 {
+		return domain.CatalogService{}, fmt.Errorf("%w: name must be at least 3 characters", domain.ErrInvalidArgument)
+	}
+	if normalizeServiceName(name) == "" {
+		return domain.CatalogService{}, fmt.Errorf("%w: name must contain letters or digits", domain.ErrInvalidArgument)
+	}
+
+	aliases := make([]string, 0, len(input.Aliases))
+	seen := map[string]bool{normalizeServiceName(name): true}
+	for _, a := range input.Aliases {
+		a = strings.TrimSpace(a)
+		norm := normalizeServiceName(a)
+		if norm == "" || seen[norm] {
+			continue
+		}
+		seen[norm] = true
+		aliases = append(aliases, a)
+	}
+
+	if input.DefaultPrice != nil && *input.DefaultPrice <= 0 {
+		return domain.CatalogService{}, fmt.Errorf("%w: default_price must be positive integer", domain.ErrInvalidArgument)
+	}
+	if input.YearlyPrice != nil && *input.YearlyPrice <= 0 {
+		return domain.CatalogService{}, fmt.Errorf("%w: yearly_price must be positive integer", domain.ErrInvalidArgument)
+	}
+	if input.FamilyPrice != nil && *input.FamilyPrice <= 0 {
+		return domain.CatalogService{}, fmt.Errorf("%w: family_price must be positive integer", domain.ErrInvalidArgument)
+	}
+
+	vendorURL := strings.TrimSpace(input.VendorURL)
+	if vendorURL != "" {
+		u, err := url.Parse(vendorURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return domain.CatalogService{}, fmt.Errorf("%w: vendor_url must be an absolute http(s) URL", domain.ErrInvalidArgument)
+		}
+	}
+
+	svc := domain.CatalogService{
+		Name:         name,
+		Aliases:      aliases,
+		DefaultPrice: input.DefaultPrice,
+		YearlyPrice:  input.YearlyPrice,
+		FamilyPrice:  input.FamilyPrice,
+		VendorURL:    vendorURL,
+	}
+	if input.CategoryID != nil && strings.TrimSpace(*input.CategoryID) != "" {
+		id, err := uuid.Parse(strings.TrimSpace(*input.CategoryID))
+		if err != nil {
+			return domain.CatalogService{}, fmt.Errorf("%w: invalid category_id", domain.ErrInvalidArgument)
+		}
+		svc.CategoryID = &id
+	}
+	return svc, nil
+}
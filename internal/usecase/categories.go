@@ -0,0 +1,116 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+
+	"github.com/always-tired/crud-subscriptions/internal/domain"
+)
+
+const maxCategoryNameLength = 64
+
+type CategoryRepository interface {
+	CreateCategory(ctx context.Context, c domain.Category) (domain.Category, error)
+	GetCategory(ctx context.Context, id uuid.UUID) (domain.Category, error)
+	ListCategories(ctx context.Context) ([]domain.Category, error)
+	UpdateCategory(ctx context.Context, c domain.Category) (domain.Category, error)
+	// DeleteCategory removes the category; its subscriptions become
+	// uncategorized and its budgets are deleted.
+	DeleteCategory(ctx context.Context, id uuid.UUID) error
+}
+
+var errCategoriesDisabled = errors.New("categories are not configured")
+
+// WithCategories enables managing the list of categories.
+func WithCategories(repo CategoryRepository) Option {
+	return func(s *Service) {
+		s.categories = repo
+	}
+}
+
+func (s *Service) CreateCategory(ctx context.Context, input CategoryInput) (domain.Category, error) {
+	if s.categories == nil {
+		return domain.Category{}, errCategoriesDisabled
+	}
+	c, err := validateCategoryInput(input)
+	if err != nil {
+		return domain.Category{}, err
+	}
+	c.ID = uuid.New()
+
+	created, err := s.categories.CreateCategory(ctx, c)
+	if err != nil {
+		s.log.Error("create category", "error", err)
+		return domain.Category{}, err
+	}
+	return created, nil
+}
+
+func (s *Service) GetCategory(ctx context.Context, id uuid.UUID) (domain.Category, error) {
+	if s.categories == nil {
+		return domain.Category{}, errCategoriesDisabled
+	}
+	c, err := s.categories.GetCategory(ctx, id)
+	if err != nil {
+		s.log.Error("get category", "error", err)
+		return domain.Category{}, err
+	}
+	return c, nil
+}
+
+func (s *Service) ListCategories(ctx context.Context) ([]domain.Category, error) {
+	if s.categories == nil {
+		return nil, errCategoriesDisabled
+	}
+	list, err := s.categories.ListCategories(ctx)
+	if err != nil {
+		s.log.Error("list categories", "error", err)
+		return nil, err
+	}
+	return list, nil
+}
+
+func (s *Service) UpdateCategory(ctx context.Context, id uuid.UUID, input CategoryInput) (domain.Category, error) {
+	if s.categories == nil {
+		return domain.Category{}, errCategoriesDisabled
+	}
+	c, err := validateCategoryInput(input)
+	if err != nil {
+		return domain.Category{}, err
+	}
+	c.ID = id
+
+	updated, err := s.categories.UpdateCategory(ctx, c)
+	if err != nil {
+		s.log.Error("update category", "error", err)
+		return domain.Category{}, err
+	}
+	return updated, nil
+}
+
+func (s *Service) DeleteCategory(ctx context.Context, id uuid.UUID) error {
+	if s.categories == nil {
+		return errCategoriesDisabled
+	}
+	if err := s.categories.DeleteCategory(ctx, id); err != nil {
+		s.log.Error("delete category", "error", err)
+		return err
+	}
+	return nil
+}
+
+func validateCategoryInput(input CategoryInput) (domain.Category, error) {
+	name := strings.TrimSpace(input.Name)
+	if name == "" || utf8.RuneCountInString(name) > maxCategoryNameLength {
+		return domain.Category{}, fmt.Errorf("%w: name must be 1 to %d characters", domain.ErrInvalidArgument, maxCategoryNameLength)
+	}
+	if err := validateTaxRate(input.TaxRate); err != nil {
+		return domain.Category{}, err
+	}
+	return domain.Category{Name: name, TaxRate: input.TaxRate}, nil
+}
@@ -0,0 +1,38 @@
+package usecase
+
+import "time"
+
+// Clock tells the service what time it is. It drives everything about the
+// subscriptions themselves: billing months, statuses, default ranges and the
+// created and updated timestamps. Delivery timing such as webhook retries
+// and leases keeps using the real time.
+type Clock interface {
+	Now() time.Time
+}
+
+// SystemClock is the real time, the default.
+type SystemClock struct{}
+
+func (SystemClock) Now() time.Time {
+	return time.Now()
+}
+
+// FixedClock always returns the same time, e.g. to pin "now" in tests or to
+// run a demo at a frozen date.
+type FixedClock time.Time
+
+func (c FixedClock) Now() time.Time {
+	return time.Time(c)
+}
+
+// WithClock replaces the system clock.
+func WithClock(c Clock) Option {
+	return func(s *Service) {
+		s.clock = c
+	}
+}
+
+// now returns the current time in UTC, the zone month dates are kept in.
+func (s *Service) now() time.Time {
+	return s.clock.Now().UTC()
+}
@@ -0,0 +1,118 @@
+package usecase_test
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/always-tired/crud-subscriptions/internal/domain"
+	"github.com/always-tired/crud-subscriptions/internal/usecase"
+)
+
+// fakeClockRepo records the timestamps the service hands to its repositories.
+// Methods the tests do not use panic through the nil embedded interfaces.
+type fakeClockRepo struct {
+	usecase.SubscriptionRepository
+	usecase.BudgetRepository
+	usecase.PaymentMethodRepository
+
+	deletedAt time.Time
+}
+
+func (r *fakeClockRepo) Get(_ context.Context, id uuid.UUID) (domain.Subscription, error) {
+	return domain.Subscription{ID: id}, nil
+}
+
+func (r *fakeClockRepo) Delete(_ context.Context, _ uuid.UUID, at time.Time) error {
+	r.deletedAt = at
+	return nil
+}
+
+// Purge removes the deleted row when it was deleted before deletedBefore.
+func (r *fakeClockRepo) Purge(_ context.Context, deletedBefore time.Time) (int64, error) {
+	if r.deletedAt.IsZero() || !r.deletedAt.Before(deletedBefore) {
+		return 0, nil
+	}
+	r.deletedAt = time.Time{}
+	return 1, nil
+}
+
+func (r *fakeClockRepo) CreateBudget(_ context.Context, b domain.Budget) (domain.Budget, error) {
+	return b, nil
+}
+
+func (r *fakeClockRepo) UpdatePaymentMethod(_ context.Context, p domain.PaymentMethod) (domain.PaymentMethod, error) {
+	return p, nil
+}
+
+func TestFixedClock(t *testing.T) {
+	at := time.Date(2025, time.March, 1, 2, 30, 0, 0, time.FixedZone("UTC+3", 3*3600))
+	if got := usecase.FixedClock(at).Now(); !got.Equal(at) {
+		t.Fatalf("Now() = %s, want %s", got, at)
+	}
+	want := at.UTC()
+
+	repo := &fakeClockRepo{}
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	s := usecase.NewService(repo, log,
+		usecase.WithClock(usecase.FixedClock(at)),
+		usecase.WithBudgets(repo),
+		usecase.WithPaymentMethods(repo),
+	)
+	ctx := context.Background()
+
+	b, err := s.CreateBudget(ctx, usecase.BudgetInput{UserID: uuid.NewString(), MonthlyLimit: 1000})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b.CreatedAt != want || b.UpdatedAt != want {
+		t.Errorf("budget created_at %s, updated_at %s, want %s", b.CreatedAt, b.UpdatedAt, want)
+	}
+
+	p, err := s.UpdatePaymentMethod(ctx, uuid.New(), usecase.PaymentMethodInput{Label: "Visa", Type: domain.PaymentMethodCard})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.UpdatedAt != want {
+		t.Errorf("payment method updated_at %s, want %s", p.UpdatedAt, want)
+	}
+
+	if err := s.Delete(ctx, uuid.New()); err != nil {
+		t.Fatal(err)
+	}
+	if repo.deletedAt != want {
+		t.Errorf("deleted at %s, want %s", repo.deletedAt, want)
+	}
+}
+
+func TestFixedClockPurgeKeepsFreshDeletions(t *testing.T) {
+	// A clock frozen in the past must not make a fresh deletion look old.
+	at := time.Date(2020, time.January, 15, 0, 0, 0, 0, time.UTC)
+	repo := &fakeClockRepo{}
+	s := usecase.NewService(repo, slog.New(slog.NewTextHandler(io.Discard, nil)),
+		usecase.WithClock(usecase.FixedClock(at)),
+	)
+	ctx := context.Background()
+
+	if err := s.Delete(ctx, uuid.New()); err != nil {
+		t.Fatal(err)
+	}
+	n, err := s.PurgeDeleted(ctx, 30*24*time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 0 || repo.deletedAt != at {
+		t.Fatalf("purged %d rows right after the deletion, want 0", n)
+	}
+
+	later := usecase.NewService(repo, slog.New(slog.NewTextHandler(io.Discard, nil)),
+		usecase.WithClock(usecase.FixedClock(at.AddDate(0, 0, 31))),
+	)
+	if n, err := later.PurgeDeleted(ctx, 30*24*time.Hour); err != nil || n != 1 {
+		t.Fatalf("PurgeDeleted after the retention = %d, %v, want 1", n, err)
+	}
+}
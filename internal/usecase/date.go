@@ -22,3 +22,16 @@ func FormatMonthDate(t time.Time) string {
 	}
 	return t.Format(MonthLayout)
 }
+
+// StartOfMonth truncates t to the first day of its month in UTC.
+func StartOfMonth(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+// monthsBetween counts the months from the month of from to the month of to,
+// negative when to lies before from.
+func monthsBetween(from, to time.Time) int {
+	from, to = from.UTC(), to.UTC()
+	return (to.Year()-from.Year())*12 + int(to.Month()) - int(from.Month())
+}
@@ -0,0 +1,215 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/always-tired/crud-subscriptions/internal/domain"
+)
+
+const maxDiscountMonths = 120
+
+func (s *Service) ListDiscounts(ctx context.Context, id uuid.UUID) ([]domain.Discount, error) {
+	sub, err := s.repo.Get(ctx, id)
+	if err != nil {
+		s.log.Error("list discounts", "error", err)
+		return nil, err
+	}
+	return sub.Discounts, nil
+}
+
+// AddDiscount lowers the price of the subscription over a range of months:
+// from input.From through input.Until, open-ended without Until, or over the
+// first input.FirstMonths charged months. From defaults to the current month,
+// or the first month of a subscription that has not started; months already
+// billed cannot be discounted. The range is fixed when the
+// discount is added, must lie within the subscription, so it cannot be
+// open-ended when the subscription has an end date, and must not overlap other
+// discounts. The subscription is locked while the discount is checked and
+// stored.
+func (s *Service) AddDiscount(ctx context.Context, id uuid.UUID, input DiscountInput) (domain.Discount, error) {
+	d, err := validateDiscountKind(input)
+	if err != nil {
+		return domain.Discount{}, err
+	}
+	d.ID = uuid.New()
+
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		before, err := s.repo.GetForUpdate(ctx, id)
+		if err != nil {
+			return err
+		}
+		if d.From, d.Until, err = discountRange(before, input, StartOfMonth(s.now())); err != nil {
+			return err
+		}
+		if err := validateDiscount(before, d); err != nil {
+			return err
+		}
+		if err := s.repo.AddDiscount(ctx, id, d); err != nil {
+			return err
+		}
+		after, err := s.repo.Get(ctx, id)
+		if err != nil {
+			return err
+		}
+		return s.recordDiscountChange(ctx, domain.AuditActionAddDiscount, before, after)
+	})
+	if err != nil {
+		s.log.Error("add discount", "error", err)
+		return domain.Discount{}, err
+	}
+	return d, nil
+}
+
+// RemoveDiscount deletes a discount, so its months are charged at list price
+// again.
+func (s *Service) RemoveDiscount(ctx context.Context, id, discountID uuid.UUID) error {
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		before, err := s.repo.Get(ctx, id)
+		if err != nil {
+			return err
+		}
+		watches, err := s.watchBudgets(ctx, before)
+		if err != nil {
+			return err
+		}
+		if err := s.repo.DeleteDiscount(ctx, id, discountID); err != nil {
+			return err
+		}
+		after, err := s.repo.Get(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := s.recordDiscountChange(ctx, domain.AuditActionRemoveDiscount, before, after); err != nil {
+			return err
+		}
+		exceeded, err := s.budgetEvents(ctx, watches, after)
+		if err != nil {
+			return err
+		}
+		return s.emit(ctx, exceeded...)
+	})
+	if err != nil {
+		s.log.Error("remove discount", "error", err)
+		return err
+	}
+	return nil
+}
+
+// recordDiscountChange audits a changed discount and emits SubscriptionUpdated.
+func (s *Service) recordDiscountChange(ctx context.Context, action string, before, after domain.Subscription) error {
+	if err := s.recordAudit(ctx, after.ID, action, snapshotSubscription(before), snapshotSubscription(after)); err != nil {
+		return err
+	}
+	prev := snapshotSubscription(before)
+	e, err := newEvent(domain.EventSubscriptionUpdated, after, subscriptionEventPayload{
+		Subscription: snapshotSubscription(after),
+		Previous:     &prev,
+	})
+	if err != nil {
+		return err
+	}
+	return s.emit(ctx, e)
+}
+
+func validateDiscountKind(input DiscountInput) (domain.Discount, error) {
+	switch input.Kind {
+	case domain.DiscountPercentage:
+		if input.Value <= 0 || input.Value > 100 {
+			return domain.Discount{}, fmt.Errorf("%w: value must be a percentage between 1 and 100", domain.ErrInvalidArgument)
+		}
+	case domain.DiscountFixed:
+		if input.Value <= 0 {
+			return domain.Discount{}, fmt.Errorf("%w: value must be positive integer", domain.ErrInvalidArgument)
+		}
+	default:
+		return domain.Discount{}, fmt.Errorf("%w: kind must be %s or %s",
+			domain.ErrInvalidArgument, domain.DiscountPercentage, domain.DiscountFixed)
+	}
+	return domain.Discount{Kind: input.Kind, Value: input.Value}, nil
+}
+
+// discountRange resolves the months a discount covers. FirstMonths counts from
+// the first charged month, i.e. after a trial. Discounts start in month or
+// later, so the charges of past months stay as they were billed.
+func discountRange(sub domain.Subscription, input DiscountInput, month time.Time) (time.Time, *time.Time, error) {
+	if input.FirstMonths != nil {
+		if (input.From != nil && *input.From != "") || (input.Until != nil && *input.Until != "") {
+			return time.Time{}, nil, fmt.Errorf("%w: set either first_months or from and until", domain.ErrInvalidArgument)
+		}
+		n := *input.FirstMonths
+		if n <= 0 || n > maxDiscountMonths {
+			return time.Time{}, nil, fmt.Errorf("%w: first_months must be between 1 and %d", domain.ErrInvalidArgument, maxDiscountMonths)
+		}
+		from := sub.FirstChargeDate()
+		if from.Before(month) {
+			return time.Time{}, nil, fmt.Errorf("%w: first_months cannot apply, the first charged month %s has been billed",
+				domain.ErrInvalidArgument, FormatMonthDate(from))
+		}
+		until := from.AddDate(0, n-1, 0)
+		return from, &until, nil
+	}
+
+	from, err := parseOptionalMonth(input.From, "from", laterMonth(month, sub.StartDate))
+	if err != nil {
+		return time.Time{}, nil, err
+	}
+	if from.Before(month) {
+		return time.Time{}, nil, fmt.Errorf("%w: from must not be before %s", domain.ErrInvalidArgument, FormatMonthDate(month))
+	}
+	if input.Until == nil || *input.Until == "" {
+		return from, nil, nil
+	}
+	until, err := parseOptionalMonth(input.Until, "until", time.Time{})
+	if err != nil {
+		return time.Time{}, nil, err
+	}
+	if until.Before(from) {
+		return time.Time{}, nil, fmt.Errorf("%w: until must not be before from", domain.ErrInvalidArgument)
+	}
+	return from, &until, nil
+}
+
+func validateDiscount(sub domain.Subscription, d domain.Discount) error {
+	if d.From.Before(sub.StartDate) {
+		return fmt.Errorf("%w: discount must not start before start_date", domain.ErrInvalidArgument)
+	}
+	if sub.EndDate != nil {
+		if d.From.After(*sub.EndDate) {
+			return fmt.Errorf("%w: discount must not start after end_date", domain.ErrInvalidArgument)
+		}
+		if d.Until == nil || d.Until.After(*sub.EndDate) {
+			return fmt.Errorf("%w: discount must end by end_date %s", domain.ErrInvalidArgument, FormatMonthDate(*sub.EndDate))
+		}
+	}
+	for _, other := range sub.Discounts {
+		if discountsOverlap(d, other) {
+			return fmt.Errorf("%w: discount overlaps the one from %s", domain.ErrInvalidArgument, FormatMonthDate(other.From))
+		}
+	}
+	return nil
+}
+
+func laterMonth(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
+
+func discountsOverlap(a, b domain.Discount) bool {
+	return (a.Until == nil || !b.From.After(*a.Until)) && (b.Until == nil || !a.From.After(*b.Until))
+}
+
+// chargeAt returns what sub charges in month m: the list price then in effect
+// less its discount. Trials and pauses are not considered.
+func chargeAt(sub domain.Subscription, m time.Time) int {
+	price := sub.Price
+	if len(sub.Prices) > 0 {
+		price = priceAt(sub.Prices, m)
+	}
+	return sub.Discounted(m, price)
+}
@@ -0,0 +1,60 @@
+package usecase
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/always-tired/crud-subscriptions/internal/domain"
+)
+
+func TestDiscountRange(t *testing.T) {
+	month := time.Date(2025, time.June, 1, 0, 0, 0, 0, time.UTC)
+	sub := recSub(uuid.New(), "Netflix", 1000)
+	upcoming := sub
+	upcoming.StartDate = time.Date(2025, time.September, 1, 0, 0, 0, 0, time.UTC)
+	str := func(v string) *string { return &v }
+
+	tests := []struct {
+		name  string
+		sub   domain.Subscription
+		input DiscountInput
+		from  string
+		until string
+		err   bool
+	}{
+		{name: "from defaults to the current month", sub: sub, from: "06-2025"},
+		{name: "from defaults to a later start", sub: upcoming, from: "09-2025"},
+		{name: "explicit range", sub: sub, input: DiscountInput{From: str("07-2025"), Until: str("09-2025")}, from: "07-2025", until: "09-2025"},
+		{name: "from in the past", sub: sub, input: DiscountInput{From: str("05-2025")}, err: true},
+		{name: "first months not billed yet", sub: upcoming, input: DiscountInput{FirstMonths: intPtr(3)}, from: "09-2025", until: "11-2025"},
+		{name: "first months already billed", sub: sub, input: DiscountInput{FirstMonths: intPtr(3)}, err: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			from, until, err := discountRange(tt.sub, tt.input, month)
+			if tt.err {
+				if !errors.Is(err, domain.ErrInvalidArgument) {
+					t.Fatalf("err = %v, want invalid argument", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := FormatMonthDate(from); got != tt.from {
+				t.Errorf("from = %s, want %s", got, tt.from)
+			}
+			got := ""
+			if until != nil {
+				got = FormatMonthDate(*until)
+			}
+			if got != tt.until {
+				t.Errorf("until = %q, want %q", got, tt.until)
+			}
+		})
+	}
+}
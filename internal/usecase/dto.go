@@ -4,6 +4,8 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+
+	"github.com/always-tired/crud-subscriptions/internal/domain"
 )
 
 type SubscriptionInput struct {
@@ -12,18 +14,247 @@ type SubscriptionInput struct {
 	UserID      string
 	StartDate   string
 	EndDate     *string
+	// TrialEndDate is the last free month; TrialMonths counts free months
+	// from StartDate instead. At most one of them may be set.
+	TrialEndDate *string
+	TrialMonths  *int
+	CategoryID   *string
+	Tags         []string
+	// SplitRule and Members share the subscription with other users, see
+	// domain.Subscription.Split. Both are empty for a personal one.
+	SplitRule string
+	Members   []MemberInput
+	// TaxRate in basis points overrides the rate of the category;
+	// TaxInclusive tells whether Price includes the tax.
+	TaxRate      *int
+	TaxInclusive bool
+	// PaymentMethodID names the card or account the subscription is paid
+	// with.
+	PaymentMethodID *string
+	// Unused flags a subscription the user no longer uses, so that it is
+	// recommended for cancellation.
+	Unused bool
+}
+
+// CategoryInput is a category. TaxRate in basis points applies to its
+// subscriptions that set none.
+type CategoryInput struct {
+	Name    string
+	TaxRate *int
+}
+
+// MemberInput is a user sharing a subscription. Share is a percentage or a
+// monthly amount depending on the split rule.
+type MemberInput struct {
+	UserID string
+	Share  int
+}
+
+// UserInput is a user profile. Currency defaults to RUB and Timezone to UTC.
+type UserInput struct {
+	Name     string
+	Email    string
+	Currency string
+	Timezone string
+}
+
+// PaymentMethodInput is a payment method. Expiry is the last month it can be
+// charged in, as MM-YYYY.
+type PaymentMethodInput struct {
+	Label  string
+	Type   string
+	Last4  string
+	Expiry *string
+}
+
+// CatalogServiceInput is a catalog entry. YearlyPrice is the price of a year
+// billed at once, FamilyPrice the monthly price of a plan shared by a
+// household.
+type CatalogServiceInput struct {
+	Name         string
+	Aliases      []string
+	DefaultPrice *int
+	YearlyPrice  *int
+	FamilyPrice  *int
+	VendorURL    string
+	CategoryID   *string
+}
+
+// PauseInput bounds a pause by MM-YYYY months; see Service.Pause.
+type PauseInput struct {
+	From  *string
+	Until *string
+}
+
+type ResumeInput struct {
+	From *string
+}
+
+// CancelInput picks when a cancellation takes effect and why; see
+// Service.Cancel.
+type CancelInput struct {
+	When   string
+	Reason string
+}
+
+// DiscountInput describes a discount; see Service.AddDiscount. From, Until
+// are MM-YYYY months.
+type DiscountInput struct {
+	Kind        string
+	Value       int
+	From        *string
+	Until       *string
+	FirstMonths *int
+}
+
+type PriceChangeInput struct {
+	Price         int
+	EffectiveFrom string
 }
 
+// Category in filters matches a category ID or name; Tags match
+// subscriptions carrying all of them.
 type ListFilter struct {
 	UserID      *uuid.UUID
 	ServiceName *string
-	Limit       int
-	Offset      int
+	Category    *string
+	Tags        []string
+	// TrialEndingWithin selects subscriptions whose first charge after a
+	// trial is due within that many days.
+	TrialEndingWithin *int
+	IncludeDeleted    bool
+	// Today anchors relative filters; Service.List sets it from its clock.
+	Today  time.Time
+	Limit  int
+	Offset int
 }
 
 type SummaryFilter struct {
 	UserID      *uuid.UUID
 	ServiceName *string
+	Category    *string
+	Tags        []string
 	Start       time.Time
 	End         time.Time
+	// GroupBy breaks the total down, see SummaryGroupBy*.
+	GroupBy string
+}
+
+// SubscriptionInfo holds the values derived from a subscription at a point in
+// time. Price is the list price in effect in the current month,
+// EffectivePrice what the month is charged after discounts and Shares its
+// split when the subscription is shared. NextBillingDate is nil when no further
+// charge is scheduled;
+// MonthsActive counts started months that were not paused, trial included,
+// and LifetimeCost sums what those months were charged.
+type SubscriptionInfo struct {
+	Price           int
+	EffectivePrice  int
+	Shares          []domain.Share
+	Status          string
+	NextBillingDate *time.Time
+	MonthsActive    int
+	LifetimeCost    int64
+}
+
+// SummaryTotals sums charges as priced (Total) and split by tax: Net plus Tax
+// is Gross. Total equals Net for prices without tax and Gross for
+// tax-inclusive ones.
+type SummaryTotals struct {
+	Total int64
+	Net   int64
+	Tax   int64
+	Gross int64
+}
+
+func (t *SummaryTotals) add(o SummaryTotals) {
+	t.Total += o.Total
+	t.Net += o.Net
+	t.Tax += o.Tax
+	t.Gross += o.Gross
+}
+
+// SummaryResult is the total of a summary and, when grouped, its breakdown.
+type SummaryResult struct {
+	SummaryTotals
+	Groups []SummaryGroup
+}
+
+// SummaryGroup is the part of a summary total for one group. ID is nil for
+// subscriptions outside any group, e.g. without a category.
+type SummaryGroup struct {
+	ID   *uuid.UUID
+	Name string
+	SummaryTotals
+}
+
+type ForecastFilter struct {
+	UserID      *uuid.UUID
+	ServiceName *string
+	Months      int
+	GroupBy     string
+}
+
+// AnomalyFilter selects the spend checked for anomalies. Zero Threshold and
+// Window fall back to the configured defaults.
+type AnomalyFilter struct {
+	UserID      *uuid.UUID
+	ServiceName *string
+	Start       time.Time
+	End         time.Time
+	// Threshold is how far above the trailing average, in percent, a
+	// month's spend must be to be flagged.
+	Threshold int
+	// Window is the number of months averaged.
+	Window int
+}
+
+// MonthlyTotal is the spend charged in one month.
+type MonthlyTotal struct {
+	Month time.Time
+	Total int64
+}
+
+// MonthlyGroupTotal is the spend of one group, a user ID or a service name
+// given by Key, charged in one month.
+type MonthlyGroupTotal struct {
+	Key   string
+	Month time.Time
+	Total int64
+}
+
+type BudgetInput struct {
+	UserID       string
+	CategoryID   *string
+	MonthlyLimit int
+}
+
+// Email in UserFilter matches regardless of case.
+type UserFilter struct {
+	Email  *string
+	Limit  int
+	Offset int
+}
+
+type AuditFilter struct {
+	SubscriptionID *uuid.UUID
+	Actor          *string
+	From           *time.Time
+	To             *time.Time
+	Limit          int
+	Offset         int
+}
+
+type WebhookInput struct {
+	URL    string
+	Secret string
+	Events []string
+	Active *bool
+}
+
+type DeliveryFilter struct {
+	WebhookID uuid.UUID
+	Status    *string
+	Limit     int
+	Offset    int
 }
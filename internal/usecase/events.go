@@ -0,0 +1,238 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/always-tired/crud-subscriptions/internal/domain"
+)
+
+// OutboxRepository stores domain events until they are published.
+type OutboxRepository interface {
+	// AddEvents stores events; events whose DedupeKey is already stored are
+	// skipped.
+	AddEvents(ctx context.Context, events []domain.Event) error
+	// FetchUnpublished returns the oldest unpublished events that are neither
+	// parked nor leased at now, and locks them until the surrounding
+	// transaction ends.
+	FetchUnpublished(ctx context.Context, now time.Time, limit int) ([]domain.Event, error)
+	// LeaseEvents hides the events from FetchUnpublished until until.
+	LeaseEvents(ctx context.Context, ids []uuid.UUID, until time.Time) error
+	// ReleaseEvents ends the lease of the events.
+	ReleaseEvents(ctx context.Context, ids []uuid.UUID) error
+	MarkPublished(ctx context.Context, id uuid.UUID) error
+	// MarkFailed records a failed attempt and ends the lease. A parked event
+	// is not published again.
+	MarkFailed(ctx context.Context, id uuid.UUID, reason string, park bool) error
+}
+
+const outboxLease = 5 * time.Minute
+
+// EventPublisher delivers an event to the outside world.
+type EventPublisher interface {
+	Publish(ctx context.Context, e domain.Event) error
+}
+
+type subscriptionEventPayload struct {
+	Subscription subscriptionSnapshot  `json:"subscription"`
+	Previous     *subscriptionSnapshot `json:"previous,omitempty"`
+}
+
+type priceChangedPayload struct {
+	Subscription  subscriptionSnapshot `json:"subscription"`
+	OldPrice      int                  `json:"old_price"`
+	NewPrice      int                  `json:"new_price"`
+	EffectiveFrom string               `json:"effective_from"`
+}
+
+type pausedPayload struct {
+	Subscription subscriptionSnapshot `json:"subscription"`
+	From         string               `json:"from"`
+	Until        *string              `json:"until,omitempty"`
+}
+
+type resumedPayload struct {
+	Subscription subscriptionSnapshot `json:"subscription"`
+	From         string               `json:"from"`
+}
+
+func newEvent(eventType string, sub domain.Subscription, payload any) (domain.Event, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return domain.Event{}, fmt.Errorf("marshal %s event: %w", eventType, err)
+	}
+	return domain.Event{
+		ID:             uuid.New(),
+		Type:           eventType,
+		SubscriptionID: sub.ID,
+		UserID:         sub.UserID,
+		Payload:        data,
+	}, nil
+}
+
+func subscriptionEvent(eventType string, sub domain.Subscription) (domain.Event, error) {
+	return newEvent(eventType, sub, subscriptionEventPayload{Subscription: snapshotSubscription(sub)})
+}
+
+func priceChangedEvent(sub domain.Subscription, oldPrice, newPrice int, from time.Time) (domain.Event, error) {
+	return newEvent(domain.EventPriceChanged, sub, priceChangedPayload{
+		Subscription:  snapshotSubscription(sub),
+		OldPrice:      oldPrice,
+		NewPrice:      newPrice,
+		EffectiveFrom: FormatMonthDate(from),
+	})
+}
+
+func pausedEvent(sub domain.Subscription, p domain.PausePeriod) (domain.Event, error) {
+	payload := pausedPayload{Subscription: snapshotSubscription(sub), From: FormatMonthDate(p.From)}
+	if p.Until != nil {
+		u := FormatMonthDate(*p.Until)
+		payload.Until = &u
+	}
+	return newEvent(domain.EventSubscriptionPaused, sub, payload)
+}
+
+func resumedEvent(sub domain.Subscription, from time.Time) (domain.Event, error) {
+	return newEvent(domain.EventSubscriptionResumed, sub, resumedPayload{
+		Subscription: snapshotSubscription(sub),
+		From:         FormatMonthDate(from),
+	})
+}
+
+// updateEvents describes an update: always SubscriptionUpdated, plus
+// PriceChanged when the price of month moved and SubscriptionCancelled when an
+// end date was set on an open-ended subscription.
+func updateEvents(before, after domain.Subscription, month time.Time) ([]domain.Event, error) {
+	prev := snapshotSubscription(before)
+	updated, err := newEvent(domain.EventSubscriptionUpdated, after, subscriptionEventPayload{
+		Subscription: snapshotSubscription(after),
+		Previous:     &prev,
+	})
+	if err != nil {
+		return nil, err
+	}
+	events := []domain.Event{updated}
+
+	if before.Price != after.Price {
+		from := month
+		if after.StartDate.After(from) {
+			from = after.StartDate
+		}
+		e, err := priceChangedEvent(after, before.Price, after.Price, from)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, e)
+	}
+	if before.EndDate == nil && after.EndDate != nil {
+		e, err := subscriptionEvent(domain.EventSubscriptionCancelled, after)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, e)
+	}
+	return events, nil
+}
+
+// emit stores events in the outbox. It must be called inside the transaction
+// of the change the events describe.
+func (s *Service) emit(ctx context.Context, events ...domain.Event) error {
+	if s.outbox == nil || len(events) == 0 {
+		return nil
+	}
+	return s.outbox.AddEvents(ctx, events)
+}
+
+// RelayEvents publishes up to limit pending events in order. It stops at the
+// first failure so that later events are not delivered ahead of it, and
+// returns the number of published events. An event that failed the
+// configured number of times is parked instead, so it no longer holds up the
+// events behind it.
+//
+// Events are claimed in a short transaction by leasing them for outboxLease,
+// so publishing runs outside any transaction and a crashed relay's events are
+// picked up again once the lease expires.
+func (s *Service) RelayEvents(ctx context.Context, pub EventPublisher, limit int) (int, error) {
+	if s.outbox == nil {
+		return 0, nil
+	}
+
+	var events []domain.Event
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		if events, err = s.outbox.FetchUnpublished(ctx, time.Now(), limit); err != nil {
+			return err
+		}
+		return s.outbox.LeaseEvents(ctx, eventIDs(events), time.Now().Add(outboxLease))
+	})
+	if err != nil {
+		s.log.Error("claim events", "error", err)
+		return 0, err
+	}
+
+	published := 0
+	for i, e := range events {
+		if err := pub.Publish(ctx, e); err != nil {
+			park := e.Attempts+1 >= s.outboxMaxAttempts
+			s.log.Warn("publish event", "event_id", e.ID, "type", e.Type, "parked", park, "error", err)
+			if err := s.outbox.MarkFailed(ctx, e.ID, err.Error(), park); err != nil {
+				s.log.Error("relay events", "error", err)
+				return published, err
+			}
+			if park {
+				continue
+			}
+			if err := s.outbox.ReleaseEvents(ctx, eventIDs(events[i+1:])); err != nil {
+				s.log.Error("relay events", "error", err)
+			}
+			return published, nil
+		}
+		if err := s.outbox.MarkPublished(ctx, e.ID); err != nil {
+			s.log.Error("relay events", "error", err)
+			return published, err
+		}
+		published++
+	}
+	return published, nil
+}
+
+func eventIDs(events []domain.Event) []uuid.UUID {
+	ids := make([]uuid.UUID, 0, len(events))
+	for _, e := range events {
+		ids = append(ids, e.ID)
+	}
+	return ids
+}
+
+// EmitEndedEvents stores a SubscriptionEnded event for every subscription
+// whose last billed month is over. Each end date yields one event only.
+func (s *Service) EmitEndedEvents(ctx context.Context) error {
+	if s.outbox == nil {
+		return nil
+	}
+
+	ended, err := s.repo.ListEnded(ctx, StartOfMonth(s.now()))
+	if err != nil {
+		s.log.Error("list ended subscriptions", "error", err)
+		return err
+	}
+
+	events := make([]domain.Event, 0, len(ended))
+	for _, sub := range ended {
+		e, err := subscriptionEvent(domain.EventSubscriptionEnded, sub)
+		if err != nil {
+			return err
+		}
+		e.DedupeKey = fmt.Sprintf("%s:%s:%s", domain.EventSubscriptionEnded, sub.ID, FormatMonthDate(*sub.EndDate))
+		events = append(events, e)
+	}
+	if err := s.outbox.AddEvents(ctx, events); err != nil {
+		s.log.Error("emit ended events", "error", err)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+package usecase
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/always-tired/crud-subscriptions/internal/domain"
+)
+
+const (
+	defaultForecastMonths = 12
+	maxForecastMonths     = 120
+)
+
+// Forecast groupings.
+const (
+	ForecastGroupByUser    = "user"
+	ForecastGroupByService = "service"
+)
+
+// Forecast is the projected spend of the coming months. Groups breaks it down
+// when the forecast is grouped.
+type Forecast struct {
+	Months []ForecastMonth
+	Total  int64
+	Groups []ForecastGroup
+}
+
+type ForecastMonth struct {
+	Month      time.Time
+	Total      int64
+	Cumulative int64
+}
+
+// ForecastGroup is the projected spend of one user or one service, given by
+// Key, with a month for every month of the forecast.
+type ForecastGroup struct {
+	Key    string
+	Months []ForecastMonth
+	Total  int64
+}
+
+// Forecast projects the monthly spend starting with the current month. Every
+// subscription is billed monthly, so every month is charged like Summary:
+// open-ended subscriptions run on, known end dates stop them, trial and paused
+// months are free and scheduled price changes and discounts apply from their
+// month on.
+func (s *Service) Forecast(ctx context.Context, filter ForecastFilter) (Forecast, error) {
+	months := filter.Months
+	if months == 0 {
+		months = defaultForecastMonths
+	}
+	if months < 0 || months > maxForecastMonths {
+		return Forecast{}, fmt.Errorf("%w: months must be between 1 and %d", domain.ErrInvalidArgument, maxForecastMonths)
+	}
+
+	serviceName, err := s.canonicalServiceName(ctx, filter.ServiceName)
+	if err != nil {
+		return Forecast{}, err
+	}
+
+	start := StartOfMonth(s.now())
+	summary := SummaryFilter{
+		UserID:      filter.UserID,
+		ServiceName: serviceName,
+		Start:       start,
+		End:         start.AddDate(0, months-1, 0),
+	}
+	totals, err := s.repo.MonthlySummary(ctx, summary)
+	if err != nil {
+		s.log.Error("forecast subscriptions", "error", err)
+		return Forecast{}, err
+	}
+	f := Forecast{}
+	f.Months, f.Total = cumulate(totals)
+
+	var grouped []MonthlyGroupTotal
+	switch filter.GroupBy {
+	case "":
+		return f, nil
+	case ForecastGroupByUser:
+		grouped, err = s.repo.MonthlySummaryByUser(ctx, summary)
+	case ForecastGroupByService:
+		grouped, err = s.repo.MonthlySummaryByService(ctx, summary)
+	default:
+		return Forecast{}, fmt.Errorf("%w: unknown group_by %q", domain.ErrInvalidArgument, filter.GroupBy)
+	}
+	if err != nil {
+		s.log.Error("forecast subscriptions", "error", err)
+		return Forecast{}, err
+	}
+	for _, series := range groupSeries(grouped, summary.Start, months) {
+		g := ForecastGroup{Key: series.key}
+		g.Months, g.Total = cumulate(series.totals)
+		f.Groups = append(f.Groups, g)
+	}
+	slices.SortStableFunc(f.Groups, func(a, b ForecastGroup) int {
+		return cmp.Compare(b.Total, a.Total)
+	})
+	return f, nil
+}
+
+func cumulate(totals []MonthlyTotal) ([]ForecastMonth, int64) {
+	var total int64
+	res := make([]ForecastMonth, 0, len(totals))
+	for _, t := range totals {
+		total += t.Total
+		res = append(res, ForecastMonth{Month: t.Month, Total: t.Total, Cumulative: total})
+	}
+	return res, total
+}
+
+type groupTotals struct {
+	key    string
+	totals []MonthlyTotal
+}
+
+// groupSeries turns grouped monthly totals, ordered by key, into one series of
+// months months from start per key, with zero totals for months without
+// charges.
+func groupSeries(grouped []MonthlyGroupTotal, start time.Time, months int) []groupTotals {
+	var res []groupTotals
+	for _, t := range grouped {
+		if len(res) == 0 || res[len(res)-1].key != t.Key {
+			totals := make([]MonthlyTotal, months)
+			for i := range totals {
+				totals[i].Month = start.AddDate(0, i, 0)
+			}
+			res = append(res, groupTotals{key: t.Key, totals: totals})
+		}
+		i := monthsBetween(start, t.Month)
+		if i >= 0 && i < months {
+			res[len(res)-1].totals[i].Total = t.Total
+		}
+	}
+	return res
+}
@@ -0,0 +1,159 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/always-tired/crud-subscriptions/internal/domain"
+)
+
+// Duplicate kinds, see Duplicate.
+const (
+	DuplicateSameUser      = "same_user"
+	DuplicateSeparatePlans = "separate_plans"
+)
+
+// WithStrictOverlaps makes Create, Update and Restore reject a subscription
+// that overlaps another one of the same user for the same service.
+func WithStrictOverlaps(strict bool) Option {
+	return func(s *Service) {
+		s.overlapsStrict = strict
+	}
+}
+
+// Duplicate is a pair of subscriptions for the same service whose periods
+// overlap from From through Until, nil when open-ended. With DuplicateSameUser
+// one user pays for the service twice, as owner or member; with
+// DuplicateSeparatePlans two users pay separately and could share one
+// subscription instead.
+type Duplicate struct {
+	Kind          string
+	ServiceName   string
+	Subscriptions [2]domain.Subscription
+	From          time.Time
+	Until         *time.Time
+}
+
+// Duplicates finds overlapping subscriptions for the same service among those
+// that have not ended. Service names are compared through the catalog, so
+// aliases match. A non-nil userID keeps the pairs the user pays for.
+func (s *Service) Duplicates(ctx context.Context, userID *uuid.UUID) ([]Duplicate, error) {
+	subs, err := s.repo.ListActive(ctx, nil, StartOfMonth(s.now()))
+	if err != nil {
+		s.log.Error("list subscriptions for duplicates", "error", err)
+		return nil, err
+	}
+	key, err := s.serviceKeys(ctx, subs)
+	if err != nil {
+		return nil, err
+	}
+
+	groups := make(map[string][]domain.Subscription)
+	for _, sub := range subs {
+		k := key(sub.ServiceName)
+		groups[k] = append(groups[k], sub)
+	}
+	keys := make([]string, 0, len(groups))
+	for k := range groups {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	res := make([]Duplicate, 0)
+	for _, k := range keys {
+		group := groups[k]
+		for i, a := range group {
+			for _, b := range group[i+1:] {
+				from, until, ok := overlap(a, b)
+				if !ok {
+					continue
+				}
+				if userID != nil && !slices.Contains(a.Users(), *userID) && !slices.Contains(b.Users(), *userID) {
+					continue
+				}
+				kind := DuplicateSeparatePlans
+				if slices.Contains(a.Users(), b.UserID) || slices.Contains(b.Users(), a.UserID) {
+					kind = DuplicateSameUser
+				}
+				res = append(res, Duplicate{
+					Kind:          kind,
+					ServiceName:   a.ServiceName,
+					Subscriptions: [2]domain.Subscription{a, b},
+					From:          from,
+					Until:         until,
+				})
+			}
+		}
+	}
+	return res, nil
+}
+
+// checkOverlaps rejects sub when its owner already has another subscription
+// for the same service in an overlapping period. It must run in the
+// transaction that writes sub: it locks the user and service until that
+// transaction ends, so concurrent creates and updates cannot both pass.
+func (s *Service) checkOverlaps(ctx context.Context, sub domain.Subscription) error {
+	if !s.overlapsStrict {
+		return nil
+	}
+	key, err := s.serviceKeys(ctx, []domain.Subscription{sub})
+	if err != nil {
+		return err
+	}
+	if err := s.repo.LockUserService(ctx, sub.UserID, key(sub.ServiceName)); err != nil {
+		return err
+	}
+	others, err := s.repo.ListActive(ctx, &sub.UserID, sub.StartDate)
+	if err != nil {
+		return err
+	}
+	if key, err = s.serviceKeys(ctx, append(others, sub)); err != nil {
+		return err
+	}
+	for _, other := range others {
+		if other.ID == sub.ID || key(other.ServiceName) != key(sub.ServiceName) {
+			continue
+		}
+		if _, _, ok := overlap(sub, other); ok {
+			return fmt.Errorf("%w: overlaps subscription %s for the same service", domain.ErrDuplicate, other.ID)
+		}
+	}
+	return nil
+}
+
+// serviceKeys returns a function that maps the service names of subs to the
+// same key when they name the same service, resolving aliases and typos
+// through the catalog when configured.
+func (s *Service) serviceKeys(ctx context.Context, subs []domain.Subscription) (func(name string) string, error) {
+	found, err := s.resolveServices(ctx, subs)
+	if err != nil {
+		return nil, err
+	}
+	return func(name string) string {
+		if svc, ok := found[name]; ok {
+			name = svc.Name
+		}
+		return normalizeServiceName(name)
+	}, nil
+}
+
+// overlap returns the months both subscriptions run in, if any.
+func overlap(a, b domain.Subscription) (time.Time, *time.Time, bool) {
+	from := a.StartDate
+	if b.StartDate.After(from) {
+		from = b.StartDate
+	}
+	until := a.EndDate
+	if until == nil || (b.EndDate != nil && b.EndDate.Before(*until)) {
+		until = b.EndDate
+	}
+	if until != nil && until.Before(from) {
+		return time.Time{}, nil, false
+	}
+	return from, until, true
+}
@@ -0,0 +1,158 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/always-tired/crud-subscriptions/internal/domain"
+)
+
+// Pause stops billing from input.From through input.Until, or until the
+// subscription is resumed when Until is omitted. From defaults to the next
+// month since the current one has already been billed. Pauses must lie within
+// the subscription and must not overlap; the subscription is locked while the
+// pause is checked and stored, so concurrent pauses cannot overlap either.
+func (s *Service) Pause(ctx context.Context, id uuid.UUID, input PauseInput) (domain.Subscription, error) {
+	from, err := parseOptionalMonth(input.From, "from", s.nextMonth())
+	if err != nil {
+		return domain.Subscription{}, err
+	}
+	var until *time.Time
+	if input.Until != nil {
+		t, err := parseOptionalMonth(input.Until, "until", time.Time{})
+		if err != nil {
+			return domain.Subscription{}, err
+		}
+		if t.Before(from) {
+			return domain.Subscription{}, fmt.Errorf("%w: until must not be before from", domain.ErrInvalidArgument)
+		}
+		until = &t
+	}
+	pause := domain.PausePeriod{From: from, Until: until}
+
+	var paused domain.Subscription
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		before, err := s.repo.GetForUpdate(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := validatePause(before, pause); err != nil {
+			return err
+		}
+		if err := s.repo.AddPause(ctx, id, pause); err != nil {
+			return err
+		}
+		if paused, err = s.repo.Get(ctx, id); err != nil {
+			return err
+		}
+		if err := s.recordAudit(ctx, id, domain.AuditActionPause, snapshotSubscription(before), snapshotSubscription(paused)); err != nil {
+			return err
+		}
+		e, err := pausedEvent(paused, pause)
+		if err != nil {
+			return err
+		}
+		return s.emit(ctx, e)
+	})
+	if err != nil {
+		s.log.Error("pause subscription", "error", err)
+		return domain.Subscription{}, err
+	}
+	return paused, nil
+}
+
+// Resume restarts billing from input.From, by default the next month. The
+// pause covering that month ends the month before, or is dropped when that
+// month is its first one.
+func (s *Service) Resume(ctx context.Context, id uuid.UUID, input ResumeInput) (domain.Subscription, error) {
+	from, err := parseOptionalMonth(input.From, "from", s.nextMonth())
+	if err != nil {
+		return domain.Subscription{}, err
+	}
+
+	var resumed domain.Subscription
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		before, err := s.repo.GetForUpdate(ctx, id)
+		if err != nil {
+			return err
+		}
+		var pause *domain.PausePeriod
+		for _, p := range before.Pauses {
+			if p.Covers(from) {
+				pause = &p
+				break
+			}
+		}
+		if pause == nil {
+			return fmt.Errorf("%w: subscription is not paused in %s", domain.ErrInvalidArgument, FormatMonthDate(from))
+		}
+		if from.After(pause.From) {
+			err = s.repo.EndPause(ctx, id, pause.From, from.AddDate(0, -1, 0))
+		} else {
+			err = s.repo.DeletePause(ctx, id, pause.From)
+		}
+		if err != nil {
+			return err
+		}
+		if resumed, err = s.repo.Get(ctx, id); err != nil {
+			return err
+		}
+		if err := s.recordAudit(ctx, id, domain.AuditActionResume, snapshotSubscription(before), snapshotSubscription(resumed)); err != nil {
+			return err
+		}
+		e, err := resumedEvent(resumed, from)
+		if err != nil {
+			return err
+		}
+		return s.emit(ctx, e)
+	})
+	if err != nil {
+		s.log.Error("resume subscription", "error", err)
+		return domain.Subscription{}, err
+	}
+	return resumed, nil
+}
+
+func validatePause(sub domain.Subscription, pause domain.PausePeriod) error {
+	if pause.From.Before(sub.StartDate) {
+		return fmt.Errorf("%w: pause must not start before start_date", domain.ErrInvalidArgument)
+	}
+	if sub.EndDate != nil {
+		if pause.From.After(*sub.EndDate) {
+			return fmt.Errorf("%w: pause must not start after end_date", domain.ErrInvalidArgument)
+		}
+		if pause.Until != nil && pause.Until.After(*sub.EndDate) {
+			return fmt.Errorf("%w: pause must not end after end_date", domain.ErrInvalidArgument)
+		}
+	}
+	for _, p := range sub.Pauses {
+		if pausesOverlap(p, pause) {
+			return fmt.Errorf("%w: subscription is already paused from %s", domain.ErrInvalidArgument, FormatMonthDate(p.From))
+		}
+	}
+	return nil
+}
+
+func pausesOverlap(a, b domain.PausePeriod) bool {
+	return (a.Until == nil || !b.From.After(*a.Until)) && (b.Until == nil || !a.From.After(*b.Until))
+}
+
+// parseOptionalMonth parses an optional MM-YYYY value, returning def when it
+// is missing.
+func parseOptionalMonth(v *string, field string, def time.Time) (time.Time, error) {
+	if v == nil || *v == "" {
+		return def, nil
+	}
+	t, err := ParseMonthDate(*v)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: %s: %s", domain.ErrInvalidArgument, field, err.Error())
+	}
+	return t, nil
+}
+
+func (s *Service) nextMonth() time.Time {
+	return StartOfMonth(s.now()).AddDate(0, 1, 0)
+}
@@ -0,0 +1,199 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+
+	"github.com/always-tired/crud-subscriptions/internal/domain"
+)
+
+const (
+	maxPaymentMethodLabelLength = 64
+	maxExpiryWindowMonths       = 24
+)
+
+type PaymentMethodRepository interface {
+	CreatePaymentMethod(ctx context.Context, p domain.PaymentMethod) (domain.PaymentMethod, error)
+	GetPaymentMethod(ctx context.Context, id uuid.UUID) (domain.PaymentMethod, error)
+	ListPaymentMethods(ctx context.Context) ([]domain.PaymentMethod, error)
+	// ListExpiringPaymentMethods returns the methods whose expiry month is
+	// not after until, expired ones included.
+	ListExpiringPaymentMethods(ctx context.Context, until time.Time) ([]domain.PaymentMethod, error)
+	UpdatePaymentMethod(ctx context.Context, p domain.PaymentMethod) (domain.PaymentMethod, error)
+	// DeletePaymentMethod removes the method; its subscriptions are left
+	// without one.
+	DeletePaymentMethod(ctx context.Context, id uuid.UUID) error
+}
+
+var errPaymentMethodsDisabled = errors.New("payment methods are not configured")
+
+// WithPaymentMethods enables managing the cards and accounts subscriptions are
+// paid with.
+func WithPaymentMethods(repo PaymentMethodRepository) Option {
+	return func(s *Service) {
+		s.payments = repo
+	}
+}
+
+func (s *Service) CreatePaymentMethod(ctx context.Context, input PaymentMethodInput) (domain.PaymentMethod, error) {
+	if s.payments == nil {
+		return domain.PaymentMethod{}, errPaymentMethodsDisabled
+	}
+	p, err := validatePaymentMethodInput(input)
+	if err != nil {
+		return domain.PaymentMethod{}, err
+	}
+	p.ID = uuid.New()
+	p.CreatedAt = s.now()
+	p.UpdatedAt = p.CreatedAt
+
+	created, err := s.payments.CreatePaymentMethod(ctx, p)
+	if err != nil {
+		s.log.Error("create payment method", "error", err)
+		return domain.PaymentMethod{}, err
+	}
+	return created, nil
+}
+
+func (s *Service) GetPaymentMethod(ctx context.Context, id uuid.UUID) (domain.PaymentMethod, error) {
+	if s.payments == nil {
+		return domain.PaymentMethod{}, errPaymentMethodsDisabled
+	}
+	p, err := s.payments.GetPaymentMethod(ctx, id)
+	if err != nil {
+		s.log.Error("get payment method", "error", err)
+		return domain.PaymentMethod{}, err
+	}
+	return p, nil
+}
+
+func (s *Service) ListPaymentMethods(ctx context.Context) ([]domain.PaymentMethod, error) {
+	if s.payments == nil {
+		return nil, errPaymentMethodsDisabled
+	}
+	list, err := s.payments.ListPaymentMethods(ctx)
+	if err != nil {
+		s.log.Error("list payment methods", "error", err)
+		return nil, err
+	}
+	return list, nil
+}
+
+func (s *Service) UpdatePaymentMethod(ctx context.Context, id uuid.UUID, input PaymentMethodInput) (domain.PaymentMethod, error) {
+	if s.payments == nil {
+		return domain.PaymentMethod{}, errPaymentMethodsDisabled
+	}
+	p, err := validatePaymentMethodInput(input)
+	if err != nil {
+		return domain.PaymentMethod{}, err
+	}
+	p.ID = id
+	p.UpdatedAt = s.now()
+
+	updated, err := s.payments.UpdatePaymentMethod(ctx, p)
+	if err != nil {
+		s.log.Error("update payment method", "error", err)
+		return domain.PaymentMethod{}, err
+	}
+	return updated, nil
+}
+
+func (s *Service) DeletePaymentMethod(ctx context.Context, id uuid.UUID) error {
+	if s.payments == nil {
+		return errPaymentMethodsDisabled
+	}
+	if err := s.payments.DeletePaymentMethod(ctx, id); err != nil {
+		s.log.Error("delete payment method", "error", err)
+		return err
+	}
+	return nil
+}
+
+// ExpiringPaymentMethod is a payment method that expires soon, or already
+// has, with the subscriptions that still charge it.
+type ExpiringPaymentMethod struct {
+	domain.PaymentMethod
+	Expired       bool
+	Subscriptions []domain.Subscription
+}
+
+// ExpiringPaymentMethods lists the payment methods whose last chargeable
+// month falls within the next months months, counting the current one, or
+// has passed already, soonest first. Each comes with the subscriptions of any
+// user that have not ended and would have to move to another method.
+func (s *Service) ExpiringPaymentMethods(ctx context.Context, months int) ([]ExpiringPaymentMethod, error) {
+	if s.payments == nil {
+		return nil, errPaymentMethodsDisabled
+	}
+	if months <= 0 || months > maxExpiryWindowMonths {
+		return nil, fmt.Errorf("%w: within_months must be between 1 and %d", domain.ErrInvalidArgument, maxExpiryWindowMonths)
+	}
+
+	month := StartOfMonth(s.now())
+	methods, err := s.payments.ListExpiringPaymentMethods(ctx, month.AddDate(0, months-1, 0))
+	if err != nil {
+		s.log.Error("list expiring payment methods", "error", err)
+		return nil, err
+	}
+	res := make([]ExpiringPaymentMethod, 0, len(methods))
+	if len(methods) == 0 {
+		return res, nil
+	}
+
+	ids := make([]uuid.UUID, 0, len(methods))
+	for _, p := range methods {
+		ids = append(ids, p.ID)
+	}
+	subs, err := s.repo.ListByPaymentMethods(ctx, ids, month)
+	if err != nil {
+		s.log.Error("list subscriptions by payment method", "error", err)
+		return nil, err
+	}
+
+	for _, p := range methods {
+		e := ExpiringPaymentMethod{PaymentMethod: p, Expired: p.ExpiredBy(month)}
+		for _, sub := range subs {
+			if sub.PaymentMethodID != nil && *sub.PaymentMethodID == p.ID {
+				e.Subscriptions = append(e.Subscriptions, sub)
+			}
+		}
+		res = append(res, e)
+	}
+	return res, nil
+}
+
+func validatePaymentMethodInput(input PaymentMethodInput) (domain.PaymentMethod, error) {
+	label := strings.TrimSpace(input.Label)
+	if label == "" || utf8.RuneCountInString(label) > maxPaymentMethodLabelLength {
+		return domain.PaymentMethod{}, fmt.Errorf("%w: label must be 1 to %d characters", domain.ErrInvalidArgument, maxPaymentMethodLabelLength)
+	}
+
+	kind := strings.ToLower(strings.TrimSpace(input.Type))
+	types := []string{domain.PaymentMethodCard, domain.PaymentMethodBankAccount, domain.PaymentMethodWallet, domain.PaymentMethodOther}
+	if !slices.Contains(types, kind) {
+		return domain.PaymentMethod{}, fmt.Errorf("%w: type must be one of %s", domain.ErrInvalidArgument, strings.Join(types, ", "))
+	}
+
+	last4 := strings.TrimSpace(input.Last4)
+	if last4 != "" && (len(last4) != 4 || strings.Trim(last4, "0123456789") != "") {
+		return domain.PaymentMethod{}, fmt.Errorf("%w: last4 must be four digits", domain.ErrInvalidArgument)
+	}
+
+	var expiry *time.Time
+	if input.Expiry != nil && strings.TrimSpace(*input.Expiry) != "" {
+		t, err := ParseMonthDate(strings.TrimSpace(*input.Expiry))
+		if err != nil {
+			return domain.PaymentMethod{}, fmt.Errorf("%w: expiry: %s", domain.ErrInvalidArgument, err.Error())
+		}
+		expiry = &t
+	}
+
+	return domain.PaymentMethod{Label: label, Type: kind, Last4: last4, Expiry: expiry}, nil
+}
@@ -0,0 +1,151 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/always-tired/crud-subscriptions/internal/domain"
+)
+
+func (s *Service) ListPrices(ctx context.Context, id uuid.UUID) ([]domain.PricePeriod, error) {
+	prices, err := s.repo.ListPrices(ctx, id)
+	if err != nil {
+		s.log.Error("list prices", "error", err)
+		return nil, err
+	}
+	return prices, nil
+}
+
+// SchedulePriceChange sets the price charged from the given month on. The
+// month must fall after the start and not after the end of the subscription,
+// which is locked so that an update or cancellation cannot move them
+// meanwhile.
+func (s *Service) SchedulePriceChange(ctx context.Context, id uuid.UUID, input PriceChangeInput) ([]domain.PricePeriod, error) {
+	if input.Price <= 0 {
+		return nil, fmt.Errorf("%w: price must be positive integer", domain.ErrInvalidArgument)
+	}
+	from, err := ParseMonthDate(input.EffectiveFrom)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", domain.ErrInvalidArgument, err.Error())
+	}
+
+	var prices []domain.PricePeriod
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		sub, err := s.repo.GetForUpdate(ctx, id)
+		if err != nil {
+			return err
+		}
+		if !from.After(sub.StartDate) {
+			return fmt.Errorf("%w: effective_from must be after start_date", domain.ErrInvalidArgument)
+		}
+		if sub.EndDate != nil && from.After(*sub.EndDate) {
+			return fmt.Errorf("%w: effective_from must not be after end_date", domain.ErrInvalidArgument)
+		}
+		before, err := s.repo.ListPrices(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := s.repo.SetPrice(ctx, id, from, input.Price); err != nil {
+			return err
+		}
+		if prices, err = s.repo.ListPrices(ctx, id); err != nil {
+			return err
+		}
+		if err := s.recordAudit(ctx, id, domain.AuditActionSchedulePrice, snapshotPrices(before), snapshotPrices(prices)); err != nil {
+			return err
+		}
+		return s.emitPriceChange(ctx, sub, before, prices, from)
+	})
+	if err != nil {
+		s.log.Error("schedule price change", "error", err)
+		return nil, err
+	}
+	return prices, nil
+}
+
+func (s *Service) CancelPriceChange(ctx context.Context, id uuid.UUID, month string) error {
+	from, err := ParseMonthDate(month)
+	if err != nil {
+		return fmt.Errorf("%w: %s", domain.ErrInvalidArgument, err.Error())
+	}
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		sub, err := s.repo.GetForUpdate(ctx, id)
+		if err != nil {
+			return err
+		}
+		before, err := s.repo.ListPrices(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := s.repo.DeletePrice(ctx, id, from); err != nil {
+			return err
+		}
+		after, err := s.repo.ListPrices(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := s.recordAudit(ctx, id, domain.AuditActionCancelPriceChange, snapshotPrices(before), snapshotPrices(after)); err != nil {
+			return err
+		}
+		return s.emitPriceChange(ctx, sub, before, after, from)
+	})
+	if err != nil {
+		s.log.Error("cancel price change", "error", err)
+		return err
+	}
+	return nil
+}
+
+// emitPriceChange emits PriceChanged when the price charged from month from
+// differs between the two price series.
+func (s *Service) emitPriceChange(ctx context.Context, sub domain.Subscription, before, after []domain.PricePeriod, from time.Time) error {
+	oldPrice, newPrice := priceAt(before, from), priceAt(after, from)
+	if oldPrice == newPrice {
+		return nil
+	}
+	e, err := priceChangedEvent(sub, oldPrice, newPrice, from)
+	if err != nil {
+		return err
+	}
+	return s.emit(ctx, e)
+}
+
+// keepPriceHistory stops an update from rewriting months that were already
+// billed. If the subscription started before the current month and the price
+// differs from the one in effect now, the new price is recorded as a change
+// from the current month and the initial price is kept. So the initial price
+// of such a subscription cannot be corrected; it has been billed.
+func (s *Service) keepPriceHistory(ctx context.Context, sub *domain.Subscription) error {
+	prices, err := s.repo.ListPrices(ctx, sub.ID)
+	if err != nil {
+		return err
+	}
+
+	month := StartOfMonth(s.now())
+	if !sub.StartDate.Before(month) {
+		return nil
+	}
+
+	newPrice := sub.Price
+	sub.Price = prices[0].Price
+	if priceAt(prices, month) == newPrice {
+		return nil
+	}
+	return s.repo.SetPrice(ctx, sub.ID, month, newPrice)
+}
+
+// priceAt returns the price in effect in month m. Prices must be ordered by
+// EffectiveFrom and non-empty.
+func priceAt(prices []domain.PricePeriod, m time.Time) int {
+	price := prices[0].Price
+	for _, p := range prices[1:] {
+		if p.EffectiveFrom.After(m) {
+			break
+		}
+		price = p.Price
+	}
+	return price
+}
@@ -0,0 +1,215 @@
+package usecase
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/always-tired/crud-subscriptions/internal/domain"
+)
+
+// Recommendation kinds, see Recommendation.
+const (
+	RecommendCancelUnused   = "cancel_unused"
+	RecommendSwitchToYearly = "switch_to_yearly"
+	RecommendFamilyPlan     = "family_plan"
+)
+
+// Recommendation suggests how to spend less on a service. With
+// RecommendCancelUnused the user flagged the subscription as unused; with
+// RecommendSwitchToYearly the catalog has a yearly price below twelve monthly
+// charges; with RecommendFamilyPlan several users pay for the service
+// separately and could share one plan. MonthlySavings estimates the saving
+// per month from the gross charges of the current month, see grossAmount.
+type Recommendation struct {
+	Kind           string
+	ServiceName    string
+	Subscriptions  []domain.Subscription
+	MonthlySavings int
+}
+
+// recommendationRule turns the subscriptions that have not ended by month
+// into recommendations, looking prices up in catalog, the entries of their
+// services by service name, and the tax rates of categories up in rates.
+type recommendationRule func(subs []domain.Subscription, catalog map[string]domain.CatalogService, rates map[uuid.UUID]int, month time.Time) []Recommendation
+
+var recommendationRules = []recommendationRule{
+	recommendCancelUnused,
+	recommendSwitchToYearly,
+	recommendFamilyPlans,
+}
+
+// Recommendations runs the savings rules over the subscriptions that have not
+// ended and returns their suggestions, the largest savings first. A non-nil
+// userID keeps the suggestions for subscriptions the user pays for.
+func (s *Service) Recommendations(ctx context.Context, userID *uuid.UUID) ([]Recommendation, error) {
+	rates := make(map[uuid.UUID]int)
+	if s.categories != nil {
+		categories, err := s.categories.ListCategories(ctx)
+		if err != nil {
+			s.log.Error("list categories for recommendations", "error", err)
+			return nil, err
+		}
+		for _, c := range categories {
+			if c.TaxRate != nil {
+				rates[c.ID] = *c.TaxRate
+			}
+		}
+	}
+	month := StartOfMonth(s.now())
+	subs, err := s.repo.ListActive(ctx, nil, month)
+	if err != nil {
+		s.log.Error("list subscriptions for recommendations", "error", err)
+		return nil, err
+	}
+	catalog, err := s.resolveServices(ctx, subs)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]Recommendation, 0)
+	for _, rule := range recommendationRules {
+		for _, rec := range rule(subs, catalog, rates, month) {
+			if userID == nil || slices.ContainsFunc(rec.Subscriptions, func(sub domain.Subscription) bool {
+				return slices.Contains(sub.Users(), *userID)
+			}) {
+				res = append(res, rec)
+			}
+		}
+	}
+	sort.SliceStable(res, func(i, j int) bool {
+		return res[i].MonthlySavings > res[j].MonthlySavings
+	})
+	return res, nil
+}
+
+// recommendCancelUnused suggests cancelling the subscriptions flagged unused
+// that are not cancelled yet, saving what they charge.
+func recommendCancelUnused(subs []domain.Subscription, _ map[string]domain.CatalogService, rates map[uuid.UUID]int, month time.Time) []Recommendation {
+	var res []Recommendation
+	for _, sub := range subs {
+		if !sub.Unused || sub.EndDate != nil {
+			continue
+		}
+		res = append(res, Recommendation{
+			Kind:           RecommendCancelUnused,
+			ServiceName:    sub.ServiceName,
+			Subscriptions:  []domain.Subscription{sub},
+			MonthlySavings: grossAmount(sub, chargeAt(sub, month), rates),
+		})
+	}
+	return res
+}
+
+// recommendSwitchToYearly suggests yearly billing when the catalog's yearly
+// price comes to less than twelve of the current monthly charges. The yearly
+// price is taxed like the subscription.
+func recommendSwitchToYearly(subs []domain.Subscription, catalog map[string]domain.CatalogService, rates map[uuid.UUID]int, month time.Time) []Recommendation {
+	var res []Recommendation
+	for _, sub := range subs {
+		if !recommendable(sub, month) {
+			continue
+		}
+		svc, ok := catalog[sub.ServiceName]
+		if !ok || svc.YearlyPrice == nil {
+			continue
+		}
+		savings := (grossAmount(sub, chargeAt(sub, month), rates)*12 - grossAmount(sub, *svc.YearlyPrice, rates)) / 12
+		if savings <= 0 {
+			continue
+		}
+		res = append(res, Recommendation{
+			Kind:           RecommendSwitchToYearly,
+			ServiceName:    sub.ServiceName,
+			Subscriptions:  []domain.Subscription{sub},
+			MonthlySavings: savings,
+		})
+	}
+	return res
+}
+
+// recommendFamilyPlans suggests one shared plan for a service that two or
+// more owners pay for separately. The plan costs the catalog's family price,
+// taxed like the most expensive subscription, or else the most expensive of
+// the current charges.
+func recommendFamilyPlans(subs []domain.Subscription, catalog map[string]domain.CatalogService, rates map[uuid.UUID]int, month time.Time) []Recommendation {
+	groups := make(map[string][]domain.Subscription)
+	prices := make(map[string]*int)
+	var keys []string
+	for _, sub := range subs {
+		if !recommendable(sub, month) {
+			continue
+		}
+		k := normalizeServiceName(sub.ServiceName)
+		var familyPrice *int
+		if svc, ok := catalog[sub.ServiceName]; ok {
+			k = normalizeServiceName(svc.Name)
+			familyPrice = svc.FamilyPrice
+		}
+		if _, ok := groups[k]; !ok {
+			keys = append(keys, k)
+		}
+		groups[k] = append(groups[k], sub)
+		prices[k] = familyPrice
+	}
+	sort.Strings(keys)
+
+	var res []Recommendation
+	for _, k := range keys {
+		group := groups[k]
+		owners := make(map[uuid.UUID]bool)
+		total, highest := 0, 0
+		var priciest domain.Subscription
+		for _, sub := range group {
+			owners[sub.UserID] = true
+			charge := grossAmount(sub, chargeAt(sub, month), rates)
+			total += charge
+			if charge > highest {
+				highest, priciest = charge, sub
+			}
+		}
+		if len(owners) < 2 {
+			continue
+		}
+		plan := highest
+		if prices[k] != nil {
+			plan = grossAmount(priciest, *prices[k], rates)
+		}
+		if total-plan <= 0 {
+			continue
+		}
+		res = append(res, Recommendation{
+			Kind:           RecommendFamilyPlan,
+			ServiceName:    group[0].ServiceName,
+			Subscriptions:  group,
+			MonthlySavings: total - plan,
+		})
+	}
+	return res
+}
+
+// recommendable is true for subscriptions that have started, run on without
+// an end date or pause in month and are still used.
+func recommendable(sub domain.Subscription, month time.Time) bool {
+	return !sub.Unused && !sub.StartDate.After(month) && sub.StatusAt(month) == domain.StatusActive
+}
+
+// grossAmount adds the tax to an amount charged like sub, the way the gross
+// totals of the summary do: tax-inclusive amounts are gross already, others
+// get the rate of the subscription or else of its category in rates, rounded
+// half up.
+func grossAmount(sub domain.Subscription, amount int, rates map[uuid.UUID]int) int {
+	if sub.TaxInclusive {
+		return amount
+	}
+	rate := 0
+	if sub.TaxRate != nil {
+		rate = *sub.TaxRate
+	} else if sub.CategoryID != nil {
+		rate = rates[*sub.CategoryID]
+	}
+	return amount + (amount*rate+5000)/10000
+}
@@ -0,0 +1,235 @@
+package usecase
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/always-tired/crud-subscriptions/internal/domain"
+)
+
+var recommendationMonth = time.Date(2025, time.June, 1, 0, 0, 0, 0, time.UTC)
+
+// recSub is a subscription of user running since January 2025.
+func recSub(user uuid.UUID, name string, price int) domain.Subscription {
+	return domain.Subscription{
+		ID:          uuid.New(),
+		UserID:      user,
+		ServiceName: name,
+		Price:       price,
+		StartDate:   time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC),
+	}
+}
+
+func intPtr(v int) *int {
+	return &v
+}
+
+// recommended is a recommendation reduced to what the rules decide.
+type recommended struct {
+	kind    string
+	savings int
+}
+
+func summarize(recs []Recommendation) []recommended {
+	res := make([]recommended, 0, len(recs))
+	for _, r := range recs {
+		res = append(res, recommended{r.Kind, r.MonthlySavings})
+	}
+	return res
+}
+
+func TestRecommendationRules(t *testing.T) {
+	alice, bob := uuid.New(), uuid.New()
+	category := uuid.New()
+	rates := map[uuid.UUID]int{category: 1000}
+	with := func(sub domain.Subscription, f func(*domain.Subscription)) domain.Subscription {
+		f(&sub)
+		return sub
+	}
+	ended := time.Date(2025, time.August, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		rule    recommendationRule
+		subs    []domain.Subscription
+		catalog map[string]domain.CatalogService
+		want    []recommended
+	}{
+		{
+			name: "unused saves its charge",
+			rule: recommendCancelUnused,
+			subs: []domain.Subscription{with(recSub(alice, "Netflix", 1000), func(s *domain.Subscription) { s.Unused = true })},
+			want: []recommended{{RecommendCancelUnused, 1000}},
+		},
+		{
+			name: "unused but already cancelled",
+			rule: recommendCancelUnused,
+			subs: []domain.Subscription{with(recSub(alice, "Netflix", 1000), func(s *domain.Subscription) {
+				s.Unused, s.EndDate = true, &ended
+			})},
+			want: []recommended{},
+		},
+		{
+			name: "used",
+			rule: recommendCancelUnused,
+			subs: []domain.Subscription{recSub(alice, "Netflix", 1000)},
+			want: []recommended{},
+		},
+		{
+			name: "unused saves the discounted charge plus tax rounded half up",
+			rule: recommendCancelUnused,
+			subs: []domain.Subscription{with(recSub(alice, "Netflix", 220), func(s *domain.Subscription) {
+				s.Unused, s.TaxRate = true, intPtr(500)
+				s.Discounts = []domain.Discount{{Kind: domain.DiscountPercentage, Value: 50, From: s.StartDate}}
+			})},
+			want: []recommended{{RecommendCancelUnused, 116}},
+		},
+		{
+			name: "unused taxed at the category rate",
+			rule: recommendCancelUnused,
+			subs: []domain.Subscription{with(recSub(alice, "Netflix", 200), func(s *domain.Subscription) {
+				s.Unused, s.CategoryID = true, &category
+			})},
+			want: []recommended{{RecommendCancelUnused, 220}},
+		},
+		{
+			name: "unused with tax included",
+			rule: recommendCancelUnused,
+			subs: []domain.Subscription{with(recSub(alice, "Netflix", 200), func(s *domain.Subscription) {
+				s.Unused, s.TaxRate, s.TaxInclusive = true, intPtr(2000), true
+			})},
+			want: []recommended{{RecommendCancelUnused, 200}},
+		},
+		{
+			name:    "yearly is cheaper",
+			rule:    recommendSwitchToYearly,
+			subs:    []domain.Subscription{recSub(alice, "Netflix", 1000)},
+			catalog: map[string]domain.CatalogService{"Netflix": {Name: "Netflix", YearlyPrice: intPtr(9000)}},
+			want:    []recommended{{RecommendSwitchToYearly, 250}},
+		},
+		{
+			name:    "yearly is not cheaper",
+			rule:    recommendSwitchToYearly,
+			subs:    []domain.Subscription{recSub(alice, "Netflix", 1000)},
+			catalog: map[string]domain.CatalogService{"Netflix": {Name: "Netflix", YearlyPrice: intPtr(12000)}},
+			want:    []recommended{},
+		},
+		{
+			name:    "no yearly price",
+			rule:    recommendSwitchToYearly,
+			subs:    []domain.Subscription{recSub(alice, "Netflix", 1000)},
+			catalog: map[string]domain.CatalogService{"Netflix": {Name: "Netflix"}},
+			want:    []recommended{},
+		},
+		{
+			name: "yearly taxed like the subscription",
+			rule: recommendSwitchToYearly,
+			subs: []domain.Subscription{with(recSub(alice, "Netflix", 1000), func(s *domain.Subscription) {
+				s.TaxRate = intPtr(2000)
+			})},
+			catalog: map[string]domain.CatalogService{"Netflix": {Name: "Netflix", YearlyPrice: intPtr(9000)}},
+			want:    []recommended{{RecommendSwitchToYearly, 300}},
+		},
+		{
+			name:    "family plan with one owner",
+			rule:    recommendFamilyPlans,
+			subs:    []domain.Subscription{recSub(alice, "Netflix", 500), recSub(alice, "netflix", 700)},
+			catalog: map[string]domain.CatalogService{"Netflix": {Name: "Netflix", FamilyPrice: intPtr(900)}},
+			want:    []recommended{},
+		},
+		{
+			name: "family plan without family price",
+			rule: recommendFamilyPlans,
+			subs: []domain.Subscription{recSub(alice, "Netflix", 500), recSub(bob, "Netflix", 700)},
+			want: []recommended{{RecommendFamilyPlan, 500}},
+		},
+		{
+			name:    "family plan with family price",
+			rule:    recommendFamilyPlans,
+			subs:    []domain.Subscription{recSub(alice, "Netflix", 500), recSub(bob, "Netflix", 700)},
+			catalog: map[string]domain.CatalogService{"Netflix": {Name: "Netflix", FamilyPrice: intPtr(900)}},
+			want:    []recommended{{RecommendFamilyPlan, 300}},
+		},
+		{
+			name:    "family price above the separate charges",
+			rule:    recommendFamilyPlans,
+			subs:    []domain.Subscription{recSub(alice, "Netflix", 500), recSub(bob, "Netflix", 700)},
+			catalog: map[string]domain.CatalogService{"Netflix": {Name: "Netflix", FamilyPrice: intPtr(1300)}},
+			want:    []recommended{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := summarize(tt.rule(tt.subs, tt.catalog, rates, recommendationMonth))
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+type fakeRecommendationRepo struct {
+	SubscriptionRepository
+	subs []domain.Subscription
+}
+
+func (r fakeRecommendationRepo) ListActive(context.Context, *uuid.UUID, time.Time) ([]domain.Subscription, error) {
+	return r.subs, nil
+}
+
+type fakeCatalog struct {
+	ServiceCatalogRepository
+	list []domain.CatalogService
+}
+
+// FindService matches exact names only.
+func (c fakeCatalog) FindService(_ context.Context, name string, _ int) (domain.CatalogService, bool, error) {
+	for _, svc := range c.list {
+		if normalizeServiceName(svc.Name) == name {
+			return svc, true, nil
+		}
+	}
+	return domain.CatalogService{}, false, nil
+}
+
+func TestRecommendationsSortsBySavings(t *testing.T) {
+	users := []uuid.UUID{uuid.New(), uuid.New(), uuid.New(), uuid.New()}
+	unused := recSub(users[1], "Spotify", 300)
+	unused.Unused = true
+	repo := fakeRecommendationRepo{subs: []domain.Subscription{
+		recSub(users[0], "Netflix", 1000),
+		unused,
+		recSub(users[2], "YouTube Premium", 500),
+		recSub(users[3], "YouTube Premium", 700),
+	}}
+	catalog := fakeCatalog{list: []domain.CatalogService{{Name: "Netflix", YearlyPrice: intPtr(9000)}}}
+	s := NewService(repo, slog.New(slog.NewTextHandler(io.Discard, nil)),
+		WithServiceCatalog(catalog, false),
+		WithClock(FixedClock(recommendationMonth.AddDate(0, 0, 14))),
+	)
+
+	recs, err := s.Recommendations(context.Background(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []recommended{{RecommendFamilyPlan, 500}, {RecommendCancelUnused, 300}, {RecommendSwitchToYearly, 250}}
+	if got := summarize(recs); !slices.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	recs, err = s.Recommendations(context.Background(), &users[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = []recommended{{RecommendCancelUnused, 300}}
+	if got := summarize(recs); !slices.Equal(got, want) {
+		t.Errorf("user filter: got %v, want %v", got, want)
+	}
+}
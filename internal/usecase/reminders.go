@@ -0,0 +1,137 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/always-tired/crud-subscriptions/internal/domain"
+)
+
+// ReminderRepository remembers reminders so that each one goes out once per
+// channel, even with several workers running.
+type ReminderRepository interface {
+	// ClaimReminder reserves r for sending. It returns false when r was sent
+	// already or another worker is sending it right now.
+	ClaimReminder(ctx context.Context, r domain.Reminder) (bool, error)
+	MarkReminderSent(ctx context.Context, id uuid.UUID) error
+	// ReleaseReminder drops an unsent claim so the reminder is retried.
+	ReleaseReminder(ctx context.Context, id uuid.UUID) error
+}
+
+// Notifier delivers reminders to users. Name identifies the channel and must
+// stay stable, as reminders are deduplicated per channel.
+type Notifier interface {
+	Name() string
+	Notify(ctx context.Context, r domain.Reminder) error
+}
+
+// WithReminders enables renewal and ending reminders sent through notifiers.
+func WithReminders(repo ReminderRepository, notifiers ...Notifier) Option {
+	return func(s *Service) {
+		s.reminders = repo
+		s.notifiers = notifiers
+	}
+}
+
+// SendReminders notifies about charges and endings due within the given
+// window, and about spend anomalies of the current month when enabled, and
+// returns how many notifications were sent. A window of zero only sends the
+// anomaly reminders. A failed notification is retried on the next call; a
+// failed anomaly check does not hold back the other reminders.
+func (s *Service) SendReminders(ctx context.Context, within time.Duration) (int, error) {
+	if s.reminders == nil || len(s.notifiers) == 0 {
+		return 0, nil
+	}
+
+	now := s.now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	until := today.Add(within)
+
+	var due []domain.Reminder
+	if within > 0 {
+		subs, err := s.repo.ListActive(ctx, nil, today)
+		if err != nil {
+			s.log.Error("list subscriptions for reminders", "error", err)
+			return 0, err
+		}
+		for _, sub := range subs {
+			due = append(due, dueReminders(sub, today, until)...)
+		}
+	}
+	var errs []error
+	anomalies, err := s.anomalyReminders(ctx, StartOfMonth(today))
+	if err != nil {
+		s.log.Error("check spend anomalies for reminders", "error", err)
+		errs = append(errs, err)
+	}
+	due = append(due, anomalies...)
+
+	sent := 0
+	for _, r := range due {
+		for _, n := range s.notifiers {
+			r.ID = uuid.New()
+			r.Channel = n.Name()
+			ok, err := s.sendReminder(ctx, n, r)
+			if err != nil {
+				s.log.Error("send reminder", "channel", r.Channel, "subscription_id", r.SubscriptionID, "error", err)
+				errs = append(errs, err)
+				continue
+			}
+			if ok {
+				sent++
+			}
+		}
+	}
+	return sent, errors.Join(errs...)
+}
+
+func (s *Service) sendReminder(ctx context.Context, n Notifier, r domain.Reminder) (bool, error) {
+	ok, err := s.reminders.ClaimReminder(ctx, r)
+	if err != nil || !ok {
+		return false, err
+	}
+	if err := n.Notify(ctx, r); err != nil {
+		if relErr := s.reminders.ReleaseReminder(ctx, r.ID); relErr != nil {
+			err = errors.Join(err, relErr)
+		}
+		return false, err
+	}
+	if err := s.reminders.MarkReminderSent(ctx, r.ID); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// dueReminders returns the reminders for sub whose due date lies between
+// today and until. Subscriptions are charged on the first of every month from
+// start_date through end_date, except for trial and paused months.
+func dueReminders(sub domain.Subscription, today, until time.Time) []domain.Reminder {
+	var res []domain.Reminder
+	reminder := func(kind string, due time.Time) domain.Reminder {
+		return domain.Reminder{
+			Kind:           kind,
+			SubscriptionID: sub.ID,
+			UserID:         sub.UserID,
+			ServiceName:    sub.ServiceName,
+			Price:          sub.Price,
+			DueDate:        due,
+		}
+	}
+
+	if next := nextChargeDate(sub, today); next != nil && !next.After(until) {
+		r := reminder(domain.ReminderRenewal, *next)
+		r.Price = chargeAt(sub, *next)
+		res = append(res, r)
+	}
+
+	if sub.EndDate != nil {
+		end := sub.EndDate.AddDate(0, 1, 0)
+		if !end.Before(today) && !end.After(until) {
+			res = append(res, reminder(domain.ReminderEnding, end))
+		}
+	}
+	return res
+}
@@ -0,0 +1,43 @@
+package usecase
+
+import "context"
+
+const anonymousActor = "anonymous"
+
+type actorKey struct{}
+
+type requestIDKey struct{}
+
+type adminTokenKey struct{}
+
+// ContextWithActor attaches the identity of whoever makes the request.
+func ContextWithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFrom returns the actor attached to ctx or "anonymous".
+func ActorFrom(ctx context.Context) string {
+	if v, ok := ctx.Value(actorKey{}).(string); ok && v != "" {
+		return v
+	}
+	return anonymousActor
+}
+
+// ContextWithAdminToken attaches the admin token the request presented.
+func ContextWithAdminToken(ctx context.Context, token string) context.Context {
+	return context.WithValue(ctx, adminTokenKey{}, token)
+}
+
+func adminTokenFrom(ctx context.Context) string {
+	v, _ := ctx.Value(adminTokenKey{}).(string)
+	return v
+}
+
+func ContextWithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+func RequestIDFrom(ctx context.Context) string {
+	v, _ := ctx.Value(requestIDKey{}).(string)
+	return v
+}
@@ -0,0 +1,71 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+
+	"github.com/always-tired/crud-subscriptions/internal/domain"
+)
+
+// EventStreamRepository reads committed events in stream order.
+type EventStreamRepository interface {
+	// HeadCursor returns a cursor positioned after every event visible now.
+	HeadCursor(ctx context.Context) (string, error)
+	// ListEventsAfter returns events after cursor, optionally for one user.
+	ListEventsAfter(ctx context.Context, cursor string, userID *uuid.UUID, limit int) ([]domain.Event, error)
+}
+
+// EventSignals wakes up stream readers when new events may be available.
+type EventSignals interface {
+	Subscribe(userID *uuid.UUID) (<-chan struct{}, func())
+}
+
+var errStreamDisabled = errors.New("event stream is not configured")
+
+// WithEventStream enables streaming subscription events to clients.
+func WithEventStream(repo EventStreamRepository, signals EventSignals) Option {
+	return func(s *Service) {
+		s.stream = repo
+		s.signals = signals
+	}
+}
+
+// SubscribeEvents returns a channel signalled when events for userID (all
+// users when nil) may be available. The channel is closed when the stream
+// should end. Without signals the channel never fires and readers rely on
+// polling.
+func (s *Service) SubscribeEvents(userID *uuid.UUID) (<-chan struct{}, func()) {
+	if s.signals == nil {
+		return nil, func() {}
+	}
+	return s.signals.Subscribe(userID)
+}
+
+// EventsHead returns the cursor to start a stream from when the client has
+// not seen any event yet.
+func (s *Service) EventsHead(ctx context.Context) (string, error) {
+	if s.stream == nil {
+		return "", errStreamDisabled
+	}
+	cursor, err := s.stream.HeadCursor(ctx)
+	if err != nil {
+		s.log.Error("events head", "error", err)
+		return "", err
+	}
+	return cursor, nil
+}
+
+// EventsAfter returns up to limit events following cursor in stream order.
+func (s *Service) EventsAfter(ctx context.Context, cursor string, userID *uuid.UUID, limit int) ([]domain.Event, error) {
+	if s.stream == nil {
+		return nil, errStreamDisabled
+	}
+	events, err := s.stream.ListEventsAfter(ctx, cursor, userID, limit)
+	if err != nil {
+		s.log.Error("events after", "error", err)
+		return nil, err
+	}
+	return events, nil
+}
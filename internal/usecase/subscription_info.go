@@ -0,0 +1,72 @@
+package usecase
+
+import (
+	"time"
+
+	"github.com/always-tired/crud-subscriptions/internal/domain"
+)
+
+// Describe derives the status, next charge and totals of a subscription as
+// of the service clock.
+func (s *Service) Describe(sub domain.Subscription) SubscriptionInfo {
+	now := s.now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	month := StartOfMonth(now)
+
+	info := SubscriptionInfo{
+		Price:           sub.Price,
+		Status:          sub.StatusAt(month),
+		NextBillingDate: nextChargeDate(sub, today),
+	}
+	if len(sub.Prices) > 0 {
+		info.Price = priceAt(sub.Prices, month)
+	}
+	info.EffectivePrice = sub.Discounted(month, info.Price)
+	if len(sub.Members) > 0 {
+		info.Shares = sub.Split(info.EffectivePrice)
+	}
+
+	last := month
+	if sub.EndDate != nil && sub.EndDate.Before(last) {
+		last = *sub.EndDate
+	}
+	first := sub.FirstChargeDate()
+	for m := sub.StartDate; !m.After(last); m = m.AddDate(0, 1, 0) {
+		if sub.PausedIn(m) {
+			continue
+		}
+		info.MonthsActive++
+		if !m.Before(first) {
+			info.LifetimeCost += int64(chargeAt(sub, m))
+		}
+	}
+	return info
+}
+
+// nextChargeDate returns the first charge of sub on or after today, or nil
+// when none is scheduled because the subscription ends or is paused until
+// resumed first. Charges fall on the first of every billed month.
+func nextChargeDate(sub domain.Subscription, today time.Time) *time.Time {
+	next := StartOfMonth(today)
+	if next.Before(today) {
+		next = next.AddDate(0, 1, 0)
+	}
+	if first := sub.FirstChargeDate(); next.Before(first) {
+		next = first
+	}
+
+	for _, p := range sub.Pauses {
+		if !p.Covers(next) {
+			continue
+		}
+		if p.Until == nil {
+			return nil
+		}
+		next = p.Until.AddDate(0, 1, 0)
+	}
+
+	if sub.EndDate != nil && next.After(*sub.EndDate) {
+		return nil
+	}
+	return &next
+}
@@ -0,0 +1,195 @@
+package usecase_test
+
+import (
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/always-tired/crud-subscriptions/internal/domain"
+	"github.com/always-tired/crud-subscriptions/internal/usecase"
+)
+
+func month(year int, m time.Month) time.Time {
+	return time.Date(year, m, 1, 0, 0, 0, 0, time.UTC)
+}
+
+func monthPtr(year int, m time.Month) *time.Time {
+	t := month(year, m)
+	return &t
+}
+
+func TestDescribe(t *testing.T) {
+	base := domain.Subscription{ServiceName: "Netflix", Price: 100, StartDate: month(2025, time.January)}
+	with := func(f func(*domain.Subscription)) domain.Subscription {
+		sub := base
+		f(&sub)
+		return sub
+	}
+	cancelledAt := time.Date(2025, time.March, 2, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name         string
+		sub          domain.Subscription
+		now          time.Time
+		status       string
+		nextBilling  string
+		monthsActive int
+		lifetimeCost int64
+	}{
+		{
+			name:         "first day of a month is charged that day",
+			sub:          base,
+			now:          time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC),
+			status:       domain.StatusActive,
+			nextBilling:  "2025-03-01",
+			monthsActive: 3,
+			lifetimeCost: 300,
+		},
+		{
+			name:         "last moment of a month bills the next one",
+			sub:          base,
+			now:          time.Date(2025, time.March, 31, 23, 59, 59, 0, time.UTC),
+			status:       domain.StatusActive,
+			nextBilling:  "2025-04-01",
+			monthsActive: 3,
+			lifetimeCost: 300,
+		},
+		{
+			name:         "other zones are read in UTC",
+			sub:          base,
+			now:          time.Date(2025, time.April, 1, 1, 0, 0, 0, time.FixedZone("UTC+3", 3*3600)),
+			status:       domain.StatusActive,
+			nextBilling:  "2025-04-01",
+			monthsActive: 3,
+			lifetimeCost: 300,
+		},
+		{
+			name:         "not started yet",
+			sub:          with(func(s *domain.Subscription) { s.StartDate = month(2025, time.May) }),
+			now:          time.Date(2025, time.March, 15, 12, 0, 0, 0, time.UTC),
+			status:       domain.StatusUpcoming,
+			nextBilling:  "2025-05-01",
+			monthsActive: 0,
+			lifetimeCost: 0,
+		},
+		{
+			name:         "within the trial",
+			sub:          with(func(s *domain.Subscription) { s.TrialEndDate = monthPtr(2025, time.February) }),
+			now:          time.Date(2025, time.February, 10, 0, 0, 0, 0, time.UTC),
+			status:       domain.StatusActive,
+			nextBilling:  "2025-03-01",
+			monthsActive: 2,
+			lifetimeCost: 0,
+		},
+		{
+			name:         "first month after the trial",
+			sub:          with(func(s *domain.Subscription) { s.TrialEndDate = monthPtr(2025, time.February) }),
+			now:          time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC),
+			status:       domain.StatusActive,
+			nextBilling:  "2025-03-01",
+			monthsActive: 3,
+			lifetimeCost: 100,
+		},
+		{
+			name: "within a pause",
+			sub: with(func(s *domain.Subscription) {
+				s.Pauses = []domain.PausePeriod{{From: month(2025, time.March), Until: monthPtr(2025, time.April)}}
+			}),
+			now:          time.Date(2025, time.March, 15, 0, 0, 0, 0, time.UTC),
+			status:       domain.StatusPaused,
+			nextBilling:  "2025-05-01",
+			monthsActive: 2,
+			lifetimeCost: 200,
+		},
+		{
+			name: "after a pause",
+			sub: with(func(s *domain.Subscription) {
+				s.Pauses = []domain.PausePeriod{{From: month(2025, time.March), Until: monthPtr(2025, time.April)}}
+			}),
+			now:          time.Date(2025, time.May, 1, 0, 0, 0, 0, time.UTC),
+			status:       domain.StatusActive,
+			nextBilling:  "2025-05-01",
+			monthsActive: 3,
+			lifetimeCost: 300,
+		},
+		{
+			name: "paused until resumed",
+			sub: with(func(s *domain.Subscription) {
+				s.Pauses = []domain.PausePeriod{{From: month(2025, time.April)}}
+			}),
+			now:          time.Date(2025, time.March, 20, 0, 0, 0, 0, time.UTC),
+			status:       domain.StatusActive,
+			nextBilling:  "",
+			monthsActive: 3,
+			lifetimeCost: 300,
+		},
+		{
+			name: "cancelled, last month ahead",
+			sub: with(func(s *domain.Subscription) {
+				s.EndDate, s.CancelledAt = monthPtr(2025, time.April), &cancelledAt
+			}),
+			now:          time.Date(2025, time.March, 15, 0, 0, 0, 0, time.UTC),
+			status:       domain.StatusCancelledPending,
+			nextBilling:  "2025-04-01",
+			monthsActive: 3,
+			lifetimeCost: 300,
+		},
+		{
+			name: "cancelled, within the last month",
+			sub: with(func(s *domain.Subscription) {
+				s.EndDate, s.CancelledAt = monthPtr(2025, time.April), &cancelledAt
+			}),
+			now:          time.Date(2025, time.April, 2, 0, 0, 0, 0, time.UTC),
+			status:       domain.StatusCancelledPending,
+			nextBilling:  "",
+			monthsActive: 4,
+			lifetimeCost: 400,
+		},
+		{
+			name: "cancelled, ended",
+			sub: with(func(s *domain.Subscription) {
+				s.EndDate, s.CancelledAt = monthPtr(2025, time.April), &cancelledAt
+			}),
+			now:          time.Date(2025, time.May, 1, 0, 0, 0, 0, time.UTC),
+			status:       domain.StatusEnded,
+			nextBilling:  "",
+			monthsActive: 4,
+			lifetimeCost: 400,
+		},
+		{
+			name:         "fixed term that was never cancelled",
+			sub:          with(func(s *domain.Subscription) { s.EndDate = monthPtr(2025, time.June) }),
+			now:          time.Date(2025, time.March, 15, 0, 0, 0, 0, time.UTC),
+			status:       domain.StatusActive,
+			nextBilling:  "2025-04-01",
+			monthsActive: 3,
+			lifetimeCost: 300,
+		},
+	}
+
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := usecase.NewService(nil, log, usecase.WithClock(usecase.FixedClock(tt.now)))
+			info := s.Describe(tt.sub)
+
+			if info.Status != tt.status {
+				t.Errorf("status = %s, want %s", info.Status, tt.status)
+			}
+			next := ""
+			if info.NextBillingDate != nil {
+				next = info.NextBillingDate.Format(time.DateOnly)
+			}
+			if next != tt.nextBilling {
+				t.Errorf("next_billing_date = %q, want %q", next, tt.nextBilling)
+			}
+			if info.MonthsActive != tt.monthsActive {
+				t.Errorf("months_active = %d, want %d", info.MonthsActive, tt.monthsActive)
+			}
+			if info.LifetimeCost != tt.lifetimeCost {
+				t.Errorf("lifetime_cost = %d, want %d", info.LifetimeCost, tt.lifetimeCost)
+			}
+		})
+	}
+}
@@ -2,8 +2,10 @@ package usecase
 
 import (
 	"context"
+	"crypto/subtle"
 	"fmt"
 	"log/slog"
+	"time"
 
 	"github.com/google/uuid"
 
@@ -13,29 +15,208 @@ import (
 type SubscriptionRepository interface {
 	Create(ctx context.Context, s domain.Subscription) (domain.Subscription, error)
 	Get(ctx context.Context, id uuid.UUID) (domain.Subscription, error)
+	// GetForUpdate is Get that locks the subscription until the surrounding
+	// transaction ends.
+	GetForUpdate(ctx context.Context, id uuid.UUID) (domain.Subscription, error)
+	// LockUserService serializes writers of the same user and service key
+	// until the surrounding transaction ends.
+	LockUserService(ctx context.Context, userID uuid.UUID, key string) error
 	Update(ctx context.Context, s domain.Subscription) (domain.Subscription, error)
-	Delete(ctx context.Context, id uuid.UUID) error
+	Delete(ctx context.Context, id uuid.UUID, at time.Time) error
+	Restore(ctx context.Context, id uuid.UUID, at time.Time) (domain.Subscription, error)
+	Cancel(ctx context.Context, id uuid.UUID, end time.Time, reason string, at time.Time) (domain.Subscription, error)
+	Reactivate(ctx context.Context, id uuid.UUID, at time.Time) (domain.Subscription, error)
+	Purge(ctx context.Context, deletedBefore time.Time) (int64, error)
 	List(ctx context.Context, filter ListFilter) ([]domain.Subscription, error)
-	Summary(ctx context.Context, filter SummaryFilter) (int64, error)
+	ListActive(ctx context.Context, userID *uuid.UUID, at time.Time) ([]domain.Subscription, error)
+	// ListActiveOfUser is ListActive for the subscriptions the user owns or
+	// is a member of.
+	ListActiveOfUser(ctx context.Context, userID uuid.UUID, at time.Time) ([]domain.Subscription, error)
+	ListByPaymentMethods(ctx context.Context, ids []uuid.UUID, at time.Time) ([]domain.Subscription, error)
+	ListEnded(ctx context.Context, before time.Time) ([]domain.Subscription, error)
+	Summary(ctx context.Context, filter SummaryFilter) (SummaryTotals, error)
+	MonthlySummary(ctx context.Context, filter SummaryFilter) ([]MonthlyTotal, error)
+	MonthlySummaryByUser(ctx context.Context, filter SummaryFilter) ([]MonthlyGroupTotal, error)
+	MonthlySummaryByService(ctx context.Context, filter SummaryFilter) ([]MonthlyGroupTotal, error)
+	SummaryByCategory(ctx context.Context, filter SummaryFilter) ([]SummaryGroup, error)
+	SummaryByPaymentMethod(ctx context.Context, filter SummaryFilter) ([]SummaryGroup, error)
+
+	ListPrices(ctx context.Context, id uuid.UUID) ([]domain.PricePeriod, error)
+	SetPrice(ctx context.Context, id uuid.UUID, from time.Time, price int) error
+	DeletePrice(ctx context.Context, id uuid.UUID, from time.Time) error
+
+	AddPause(ctx context.Context, id uuid.UUID, p domain.PausePeriod) error
+	EndPause(ctx context.Context, id uuid.UUID, from, until time.Time) error
+	DeletePause(ctx context.Context, id uuid.UUID, from time.Time) error
+
+	AddDiscount(ctx context.Context, id uuid.UUID, d domain.Discount) error
+	DeleteDiscount(ctx context.Context, id, discountID uuid.UUID) error
+
+	SetMembers(ctx context.Context, id uuid.UUID, members []domain.SubscriptionMember) error
+}
+
+// TxManager runs fn atomically. Repositories must use the context passed to fn.
+type TxManager interface {
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+type noTx struct{}
+
+func (noTx) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
 }
 
 type Service struct {
-	repo SubscriptionRepository
-	log  *slog.Logger
+	repo              SubscriptionRepository
+	tx                TxManager
+	calendars         CalendarTokenRepository
+	audit             AuditRepository
+	outbox            OutboxRepository
+	outboxMaxAttempts int
+	stream            EventStreamRepository
+	signals           EventSignals
+	log               *slog.Logger
+
+	webhooks           WebhookRepository
+	webhookSender      WebhookSender
+	webhookMaxAttempts int
+
+	reminders ReminderRepository
+	notifiers []Notifier
+	budgets   BudgetRepository
+
+	users       UserRepository
+	usersStrict bool
+
+	categories    CategoryRepository
+	payments      PaymentMethodRepository
+	catalog       ServiceCatalogRepository
+	catalogStrict bool
+
+	overlapsStrict bool
+
+	anomalyThreshold int
+	anomalyWindow    int
+	anomalyAlerts    bool
+
+	adminToken string
+
+	clock Clock
+}
+
+// Option configures optional dependencies of the Service.
+type Option func(*Service)
+
+// WithTxManager makes multi-step operations atomic. Without it every
+// repository call commits on its own.
+func WithTxManager(tx TxManager) Option {
+	return func(s *Service) {
+		s.tx = tx
+	}
+}
+
+// WithAuditLog records every change of a subscription in the audit log, in the
+// same transaction as the change itself.
+func WithAuditLog(repo AuditRepository) Option {
+	return func(s *Service) {
+		s.audit = repo
+	}
 }
 
-func NewService(repo SubscriptionRepository, log *slog.Logger) *Service {
-	return &Service{repo: repo, log: log}
+// WithOutbox stores domain events about subscription changes in the outbox,
+// in the same transaction as the change itself. An event is parked after
+// maxAttempts failed attempts to publish it.
+func WithOutbox(repo OutboxRepository, maxAttempts int) Option {
+	return func(s *Service) {
+		s.outbox = repo
+		s.outboxMaxAttempts = max(maxAttempts, 1)
+	}
+}
+
+// WithWebhooks enables integrator webhooks. A delivery is dead-lettered after
+// maxAttempts failed attempts.
+func WithWebhooks(repo WebhookRepository, sender WebhookSender, maxAttempts int) Option {
+	return func(s *Service) {
+		s.webhooks = repo
+		s.webhookSender = sender
+		s.webhookMaxAttempts = max(maxAttempts, 1)
+	}
+}
+
+// WithCalendarTokens enables the per-user renewal calendar feed.
+func WithCalendarTokens(repo CalendarTokenRepository) Option {
+	return func(s *Service) {
+		s.calendars = repo
+	}
+}
+
+// WithAdminToken sets the token that unlocks admin-only reads such as listing
+// deleted subscriptions. Without it those reads are forbidden.
+func WithAdminToken(token string) Option {
+	return func(s *Service) {
+		s.adminToken = token
+	}
+}
+
+// isAdmin reports whether the request presented the admin token.
+func (s *Service) isAdmin(ctx context.Context) bool {
+	token := adminTokenFrom(ctx)
+	return s.adminToken != "" && subtle.ConstantTimeCompare([]byte(token), []byte(s.adminToken)) == 1
+}
+
+func NewService(repo SubscriptionRepository, log *slog.Logger, opts ...Option) *Service {
+	s := &Service{repo: repo, tx: noTx{}, clock: SystemClock{}, log: log}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
 func (s *Service) Create(ctx context.Context, input SubscriptionInput) (domain.Subscription, error) {
-	sub, err := s.validateInput(input)
+	sub, err := s.validateInput(ctx, input)
 	if err != nil {
 		return domain.Subscription{}, err
 	}
 	sub.ID = uuid.New()
+	sub.CreatedAt = s.now()
+	sub.UpdatedAt = sub.CreatedAt
 
-	created, err := s.repo.Create(ctx, sub)
+	var created domain.Subscription
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.checkUsers(ctx, sub); err != nil {
+			return err
+		}
+		if err := s.checkOverlaps(ctx, sub); err != nil {
+			return err
+		}
+		watches, err := s.watchBudgets(ctx, sub)
+		if err != nil {
+			return err
+		}
+		if created, err = s.repo.Create(ctx, sub); err != nil {
+			return err
+		}
+		if len(sub.Members) > 0 {
+			if err := s.repo.SetMembers(ctx, created.ID, sub.Members); err != nil {
+				return err
+			}
+			if created, err = s.repo.Get(ctx, created.ID); err != nil {
+				return err
+			}
+		}
+		if err := s.recordAudit(ctx, created.ID, domain.AuditActionCreate, nil, snapshotSubscription(created)); err != nil {
+			return err
+		}
+		e, err := subscriptionEvent(domain.EventSubscriptionCreated, created)
+		if err != nil {
+			return err
+		}
+		exceeded, err := s.budgetEvents(ctx, watches, created)
+		if err != nil {
+			return err
+		}
+		return s.emit(ctx, append([]domain.Event{e}, exceeded...)...)
+	})
 	if err != nil {
 		s.log.Error("create subscription", "error", err)
 		return domain.Subscription{}, err
@@ -53,13 +234,51 @@ func (s *Service) Get(ctx context.Context, id uuid.UUID) (domain.Subscription, e
 }
 
 func (s *Service) Update(ctx context.Context, id uuid.UUID, input SubscriptionInput) (domain.Subscription, error) {
-	sub, err := s.validateInput(input)
+	sub, err := s.validateInput(ctx, input)
 	if err != nil {
 		return domain.Subscription{}, err
 	}
 	sub.ID = id
+	sub.UpdatedAt = s.now()
 
-	updated, err := s.repo.Update(ctx, sub)
+	var updated domain.Subscription
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		before, err := s.repo.GetForUpdate(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := s.checkUsers(ctx, sub); err != nil {
+			return err
+		}
+		if err := s.checkOverlaps(ctx, sub); err != nil {
+			return err
+		}
+		watches, err := s.watchBudgets(ctx, before, sub)
+		if err != nil {
+			return err
+		}
+		if err := s.keepPriceHistory(ctx, &sub); err != nil {
+			return err
+		}
+		if err := s.repo.SetMembers(ctx, id, sub.Members); err != nil {
+			return err
+		}
+		if updated, err = s.repo.Update(ctx, sub); err != nil {
+			return err
+		}
+		if err := s.recordAudit(ctx, id, domain.AuditActionUpdate, snapshotSubscription(before), snapshotSubscription(updated)); err != nil {
+			return err
+		}
+		events, err := updateEvents(before, updated, StartOfMonth(s.now()))
+		if err != nil {
+			return err
+		}
+		exceeded, err := s.budgetEvents(ctx, watches, updated)
+		if err != nil {
+			return err
+		}
+		return s.emit(ctx, append(events, exceeded...)...)
+	})
 	if err != nil {
 		s.log.Error("update subscription", "error", err)
 		return domain.Subscription{}, err
@@ -68,14 +287,85 @@ func (s *Service) Update(ctx context.Context, id uuid.UUID, input SubscriptionIn
 }
 
 func (s *Service) Delete(ctx context.Context, id uuid.UUID) error {
-	if err := s.repo.Delete(ctx, id); err != nil {
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		before, err := s.repo.Get(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := s.repo.Delete(ctx, id, s.now()); err != nil {
+			return err
+		}
+		if err := s.recordAudit(ctx, id, domain.AuditActionDelete, snapshotSubscription(before), nil); err != nil {
+			return err
+		}
+		e, err := subscriptionEvent(domain.EventSubscriptionDeleted, before)
+		if err != nil {
+			return err
+		}
+		return s.emit(ctx, e)
+	})
+	if err != nil {
 		s.log.Error("delete subscription", "error", err)
 		return err
 	}
 	return nil
 }
 
+// Restore brings back a soft-deleted subscription. With strict overlaps it
+// fails like Create when the subscription overlaps another one.
+func (s *Service) Restore(ctx context.Context, id uuid.UUID) (domain.Subscription, error) {
+	var restored domain.Subscription
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		if restored, err = s.repo.Restore(ctx, id, s.now()); err != nil {
+			return err
+		}
+		if err := s.checkOverlaps(ctx, restored); err != nil {
+			return err
+		}
+		if err := s.recordAudit(ctx, id, domain.AuditActionRestore, nil, snapshotSubscription(restored)); err != nil {
+			return err
+		}
+		e, err := subscriptionEvent(domain.EventSubscriptionRestored, restored)
+		if err != nil {
+			return err
+		}
+		return s.emit(ctx, e)
+	})
+	if err != nil {
+		s.log.Error("restore subscription", "error", err)
+		return domain.Subscription{}, err
+	}
+	return restored, nil
+}
+
+// PurgeDeleted permanently removes subscriptions that were deleted more than
+// retention ago and returns how many were removed. Deletion times come from
+// the clock, so retention is measured against it too.
+func (s *Service) PurgeDeleted(ctx context.Context, retention time.Duration) (int64, error) {
+	n, err := s.repo.Purge(ctx, s.now().Add(-retention))
+	if err != nil {
+		s.log.Error("purge subscriptions", "error", err)
+		return 0, err
+	}
+	return n, nil
+}
+
 func (s *Service) List(ctx context.Context, filter ListFilter) ([]domain.Subscription, error) {
+	if d := filter.TrialEndingWithin; d != nil && (*d < 0 || *d > 366) {
+		return nil, fmt.Errorf("%w: trial_ending_within must be between 0 and 366 days", domain.ErrInvalidArgument)
+	}
+	if filter.IncludeDeleted && !s.isAdmin(ctx) {
+		return nil, fmt.Errorf("%w: include_deleted requires the admin token", domain.ErrForbidden)
+	}
+
+	now := s.now()
+	filter.Today = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	var err error
+	if filter.ServiceName, err = s.canonicalServiceName(ctx, filter.ServiceName); err != nil {
+		return nil, err
+	}
 	list, err := s.repo.List(ctx, filter)
 	if err != nil {
 		s.log.Error("list subscriptions", "error", err)
@@ -84,18 +374,44 @@ func (s *Service) List(ctx context.Context, filter ListFilter) ([]domain.Subscri
 	return list, nil
 }
 
-func (s *Service) Summary(ctx context.Context, filter SummaryFilter) (int64, error) {
+// Summary groupings.
+const (
+	SummaryGroupByCategory      = "category"
+	SummaryGroupByPaymentMethod = "payment_method"
+)
+
+func (s *Service) Summary(ctx context.Context, filter SummaryFilter) (SummaryResult, error) {
 	if filter.Start.IsZero() || filter.End.IsZero() {
-		return 0, fmt.Errorf("%w: start and end are required", domain.ErrInvalidArgument)
+		return SummaryResult{}, fmt.Errorf("%w: start and end are required", domain.ErrInvalidArgument)
 	}
 	if filter.End.Before(filter.Start) {
-		return 0, fmt.Errorf("%w: end must be after start", domain.ErrInvalidArgument)
+		return SummaryResult{}, fmt.Errorf("%w: end must be after start", domain.ErrInvalidArgument)
 	}
 
-	total, err := s.repo.Summary(ctx, filter)
+	var res SummaryResult
+	var err error
+	if filter.ServiceName, err = s.canonicalServiceName(ctx, filter.ServiceName); err != nil {
+		return SummaryResult{}, err
+	}
+	switch filter.GroupBy {
+	case "":
+		res.SummaryTotals, err = s.repo.Summary(ctx, filter)
+	case SummaryGroupByCategory:
+		res.Groups, err = s.repo.SummaryByCategory(ctx, filter)
+		for _, g := range res.Groups {
+			res.add(g.SummaryTotals)
+		}
+	case SummaryGroupByPaymentMethod:
+		res.Groups, err = s.repo.SummaryByPaymentMethod(ctx, filter)
+		for _, g := range res.Groups {
+			res.add(g.SummaryTotals)
+		}
+	default:
+		return SummaryResult{}, fmt.Errorf("%w: unknown group_by %q", domain.ErrInvalidArgument, filter.GroupBy)
+	}
 	if err != nil {
 		s.log.Error("summary subscriptions", "error", err)
-		return 0, err
+		return SummaryResult{}, err
 	}
-	return total, nil
+	return res, nil
 }
@@ -0,0 +1,48 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/always-tired/crud-subscriptions/internal/domain"
+	"github.com/always-tired/crud-subscriptions/internal/usecase"
+)
+
+type fakeListRepo struct {
+	usecase.SubscriptionRepository
+}
+
+func (fakeListRepo) List(context.Context, usecase.ListFilter) ([]domain.Subscription, error) {
+	return nil, nil
+}
+
+func TestListDeletedRequiresAdmin(t *testing.T) {
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	deleted := usecase.ListFilter{IncludeDeleted: true}
+
+	tests := []struct {
+		name    string
+		opts    []usecase.Option
+		token   string
+		filter  usecase.ListFilter
+		wantErr error
+	}{
+		{name: "live rows need no token", filter: usecase.ListFilter{}},
+		{name: "admin token", opts: []usecase.Option{usecase.WithAdminToken("secret")}, token: "secret", filter: deleted},
+		{name: "wrong token", opts: []usecase.Option{usecase.WithAdminToken("secret")}, token: "guess", filter: deleted, wantErr: domain.ErrForbidden},
+		{name: "no token", opts: []usecase.Option{usecase.WithAdminToken("secret")}, filter: deleted, wantErr: domain.ErrForbidden},
+		{name: "admin disabled", filter: deleted, wantErr: domain.ErrForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := usecase.NewService(fakeListRepo{}, log, tt.opts...)
+			ctx := usecase.ContextWithAdminToken(context.Background(), tt.token)
+			if _, err := s.List(ctx, tt.filter); !errors.Is(err, tt.wantErr) {
+				t.Errorf("List() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,274 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/mail"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+
+	"github.com/always-tired/crud-subscriptions/internal/domain"
+)
+
+const (
+	maxUserNameLength = 100
+	defaultCurrency   = "RUB"
+	defaultTimezone   = "UTC"
+)
+
+type UserRepository interface {
+	CreateUser(ctx context.Context, u domain.User) (domain.User, error)
+	GetUser(ctx context.Context, id uuid.UUID) (domain.User, error)
+	ListUsers(ctx context.Context, filter UserFilter) ([]domain.User, error)
+	UpdateUser(ctx context.Context, u domain.User) (domain.User, error)
+	// DeleteUser removes the user and their memberships. It fails with
+	// ErrConflict while the user still owns subscriptions, deleted ones
+	// included.
+	DeleteUser(ctx context.Context, id uuid.UUID) error
+	// EnsureUsers registers the users that do not exist yet with an empty
+	// profile, created at the given time.
+	EnsureUsers(ctx context.Context, ids []uuid.UUID, at time.Time) error
+}
+
+var errUsersDisabled = errors.New("users are not configured")
+
+// WithUsers enables managing users. In strict mode subscriptions naming an
+// unknown owner or member are rejected; otherwise such users are registered
+// on the fly with an empty profile.
+func WithUsers(repo UserRepository, strict bool) Option {
+	return func(s *Service) {
+		s.users = repo
+		s.usersStrict = strict
+	}
+}
+
+func (s *Service) CreateUser(ctx context.Context, input UserInput) (domain.User, error) {
+	if s.users == nil {
+		return domain.User{}, errUsersDisabled
+	}
+	u, err := validateUserInput(input)
+	if err != nil {
+		return domain.User{}, err
+	}
+	u.ID = uuid.New()
+	u.CreatedAt = s.now()
+	u.UpdatedAt = u.CreatedAt
+
+	created, err := s.users.CreateUser(ctx, u)
+	if err != nil {
+		s.log.Error("create user", "error", err)
+		return domain.User{}, err
+	}
+	return created, nil
+}
+
+func (s *Service) GetUser(ctx context.Context, id uuid.UUID) (domain.User, error) {
+	if s.users == nil {
+		return domain.User{}, errUsersDisabled
+	}
+	u, err := s.users.GetUser(ctx, id)
+	if err != nil {
+		s.log.Error("get user", "error", err)
+		return domain.User{}, err
+	}
+	return u, nil
+}
+
+func (s *Service) ListUsers(ctx context.Context, filter UserFilter) ([]domain.User, error) {
+	if s.users == nil {
+		return nil, errUsersDisabled
+	}
+	list, err := s.users.ListUsers(ctx, filter)
+	if err != nil {
+		s.log.Error("list users", "error", err)
+		return nil, err
+	}
+	return list, nil
+}
+
+func (s *Service) UpdateUser(ctx context.Context, id uuid.UUID, input UserInput) (domain.User, error) {
+	if s.users == nil {
+		return domain.User{}, errUsersDisabled
+	}
+	u, err := validateUserInput(input)
+	if err != nil {
+		return domain.User{}, err
+	}
+	u.ID = id
+	u.UpdatedAt = s.now()
+
+	updated, err := s.users.UpdateUser(ctx, u)
+	if err != nil {
+		s.log.Error("update user", "error", err)
+		return domain.User{}, err
+	}
+	return updated, nil
+}
+
+func (s *Service) DeleteUser(ctx context.Context, id uuid.UUID) error {
+	if s.users == nil {
+		return errUsersDisabled
+	}
+	if err := s.users.DeleteUser(ctx, id); err != nil {
+		s.log.Error("delete user", "error", err)
+		return err
+	}
+	return nil
+}
+
+// checkUsers makes sure the owner and the members of sub exist. It must run
+// in the transaction that writes sub.
+func (s *Service) checkUsers(ctx context.Context, sub domain.Subscription) error {
+	if s.users == nil {
+		return nil
+	}
+	if !s.usersStrict {
+		return s.users.EnsureUsers(ctx, sub.Users(), s.now())
+	}
+	for i, id := range sub.Users() {
+		if _, err := s.users.GetUser(ctx, id); err != nil {
+			if !errors.Is(err, domain.ErrNotFound) {
+				return err
+			}
+			if i == 0 {
+				return fmt.Errorf("%w: unknown user_id", domain.ErrInvalidArgument)
+			}
+			return fmt.Errorf("%w: unknown member user_id %s", domain.ErrInvalidArgument, id)
+		}
+	}
+	return nil
+}
+
+// UserOverview aggregates the subscriptions and spend of a user as of today
+// in their timezone. Spend counts the user's shares of shared subscriptions.
+type UserOverview struct {
+	User domain.User
+	// Statuses counts the subscriptions the user owns that have not ended,
+	// by status.
+	Statuses     map[string]int
+	MonthlySpend int64
+	// ByCategory breaks MonthlySpend down by category.
+	ByCategory []SummaryGroup
+	// YearlySpend is the spend of the twelve months starting with the
+	// current one.
+	YearlySpend int64
+	NextCharge  *UpcomingCharge
+}
+
+// UpcomingCharge is the next charge of one of the subscriptions the user owns
+// or is a member of; Amount is the user's share of it.
+type UpcomingCharge struct {
+	SubscriptionID uuid.UUID
+	ServiceName    string
+	Date           time.Time
+	Amount         int
+}
+
+func (s *Service) UserOverview(ctx context.Context, id uuid.UUID) (UserOverview, error) {
+	if s.users == nil {
+		return UserOverview{}, errUsersDisabled
+	}
+	u, err := s.users.GetUser(ctx, id)
+	if err != nil {
+		s.log.Error("get user", "error", err)
+		return UserOverview{}, err
+	}
+
+	loc, err := time.LoadLocation(u.Timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+	local := s.now().In(loc)
+	today := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, time.UTC)
+	month := time.Date(local.Year(), local.Month(), 1, 0, 0, 0, 0, time.UTC)
+
+	subs, err := s.repo.ListActiveOfUser(ctx, id, month)
+	if err != nil {
+		s.log.Error("list subscriptions for overview", "error", err)
+		return UserOverview{}, err
+	}
+
+	res := UserOverview{User: u, Statuses: make(map[string]int)}
+	for _, sub := range subs {
+		if sub.UserID == id {
+			res.Statuses[sub.StatusAt(month)]++
+		}
+		next := nextChargeDate(sub, today)
+		if next == nil || (res.NextCharge != nil && !next.Before(res.NextCharge.Date)) {
+			continue
+		}
+		res.NextCharge = &UpcomingCharge{
+			SubscriptionID: sub.ID,
+			ServiceName:    sub.ServiceName,
+			Date:           *next,
+			Amount:         shareOf(sub.Split(chargeAt(sub, *next)), id),
+		}
+	}
+
+	filter := SummaryFilter{UserID: &id, Start: month, End: month}
+	if res.ByCategory, err = s.repo.SummaryByCategory(ctx, filter); err != nil {
+		s.log.Error("summary for overview", "error", err)
+		return UserOverview{}, err
+	}
+	for _, g := range res.ByCategory {
+		res.MonthlySpend += g.Total
+	}
+
+	filter.End = month.AddDate(0, 11, 0)
+	totals, err := s.repo.MonthlySummary(ctx, filter)
+	if err != nil {
+		s.log.Error("forecast for overview", "error", err)
+		return UserOverview{}, err
+	}
+	for _, t := range totals {
+		res.YearlySpend += t.Total
+	}
+	return res, nil
+}
+
+// shareOf returns the amount of shares charged to userID.
+func shareOf(shares []domain.Share, userID uuid.UUID) int {
+	for _, sh := range shares {
+		if sh.UserID == userID {
+			return sh.Amount
+		}
+	}
+	return 0
+}
+
+func validateUserInput(input UserInput) (domain.User, error) {
+	name := strings.TrimSpace(input.Name)
+	if name == "" || utf8.RuneCountInString(name) > maxUserNameLength {
+		return domain.User{}, fmt.Errorf("%w: name must be 1 to %d characters", domain.ErrInvalidArgument, maxUserNameLength)
+	}
+
+	email := strings.TrimSpace(input.Email)
+	if email != "" {
+		addr, err := mail.ParseAddress(email)
+		if err != nil || addr.Address != email {
+			return domain.User{}, fmt.Errorf("%w: invalid email", domain.ErrInvalidArgument)
+		}
+	}
+
+	currency := strings.ToUpper(strings.TrimSpace(input.Currency))
+	if currency == "" {
+		currency = defaultCurrency
+	}
+	if len(currency) != 3 || strings.Trim(currency, "ABCDEFGHIJKLMNOPQRSTUVWXYZ") != "" {
+		return domain.User{}, fmt.Errorf("%w: currency must be a three-letter ISO 4217 code", domain.ErrInvalidArgument)
+	}
+
+	timezone := strings.TrimSpace(input.Timezone)
+	if timezone == "" {
+		timezone = defaultTimezone
+	}
+	if _, err := time.LoadLocation(timezone); err != nil || timezone == "Local" {
+		return domain.User{}, fmt.Errorf("%w: unknown timezone %q", domain.ErrInvalidArgument, timezone)
+	}
+
+	return domain.User{Name: name, Email: email, Currency: currency, Timezone: timezone}, nil
+}
@@ -1,21 +1,25 @@
 package usecase
 
 import (
+	"bytes"
+	"context"
 	"fmt"
+	"slices"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/google/uuid"
 
 	"github.com/always-tired/crud-subscriptions/internal/domain"
 )
 
-func (s *Service) validateInput(input SubscriptionInput) (domain.Subscription, error) {
+func (s *Service) validateInput(ctx context.Context, input SubscriptionInput) (domain.Subscription, error) {
 	name := strings.TrimSpace(input.ServiceName)
 	if len(name) < 3 {
 		return domain.Subscription{}, fmt.Errorf("%w: service_name must be at least 3 characters", domain.ErrInvalidArgument)
 	}
-	if input.Price <= 0 {
+	if input.Price < 0 {
 		return domain.Subscription{}, fmt.Errorf("%w: price must be positive integer", domain.ErrInvalidArgument)
 	}
 
@@ -41,12 +45,64 @@ func (s *Service) validateInput(input SubscriptionInput) (domain.Subscription, e
 		return domain.Subscription{}, fmt.Errorf("%w: end_date must be after start_date", domain.ErrInvalidArgument)
 	}
 
+	trialEnd, err := parseTrialEnd(input, start, end)
+	if err != nil {
+		return domain.Subscription{}, err
+	}
+
+	var categoryID *uuid.UUID
+	if input.CategoryID != nil && strings.TrimSpace(*input.CategoryID) != "" {
+		id, err := uuid.Parse(strings.TrimSpace(*input.CategoryID))
+		if err != nil {
+			return domain.Subscription{}, fmt.Errorf("%w: invalid category_id", domain.ErrInvalidArgument)
+		}
+		categoryID = &id
+	}
+
+	var paymentMethodID *uuid.UUID
+	if input.PaymentMethodID != nil && strings.TrimSpace(*input.PaymentMethodID) != "" {
+		id, err := uuid.Parse(strings.TrimSpace(*input.PaymentMethodID))
+		if err != nil {
+			return domain.Subscription{}, fmt.Errorf("%w: invalid payment_method_id", domain.ErrInvalidArgument)
+		}
+		paymentMethodID = &id
+	}
+
+	tags, err := normalizeTags(input.Tags)
+	if err != nil {
+		return domain.Subscription{}, err
+	}
+
+	rule, members, err := parseMembers(input, uid)
+	if err != nil {
+		return domain.Subscription{}, err
+	}
+	if err := validateTaxRate(input.TaxRate); err != nil {
+		return domain.Subscription{}, err
+	}
+
 	sub := domain.Subscription{
-		ServiceName: name,
-		Price:       input.Price,
-		UserID:      uid,
-		StartDate:   start,
-		EndDate:     end,
+		ServiceName:     name,
+		Price:           input.Price,
+		UserID:          uid,
+		StartDate:       start,
+		EndDate:         end,
+		TrialEndDate:    trialEnd,
+		CategoryID:      categoryID,
+		PaymentMethodID: paymentMethodID,
+		Tags:            tags,
+		SplitRule:       rule,
+		Members:         members,
+		TaxRate:         input.TaxRate,
+		TaxInclusive:    input.TaxInclusive,
+		Unused:          input.Unused,
+	}
+	// The catalog may rename the service and supply its default price.
+	if err := s.applyCatalog(ctx, &sub); err != nil {
+		return domain.Subscription{}, err
+	}
+	if sub.Price <= 0 {
+		return domain.Subscription{}, fmt.Errorf("%w: price must be positive integer", domain.ErrInvalidArgument)
 	}
 	if err := s.validateDomain(sub); err != nil {
 		return domain.Subscription{}, err
@@ -63,3 +119,140 @@ func (s *Service) validateDomain(sub domain.Subscription) error {
 	}
 	return nil
 }
+
+const maxTrialMonths = 24
+
+// parseTrialEnd returns the last free month given either as trial_end_date or
+// as trial_months, or nil without a trial. The trial must lie within the
+// subscription and leave at least one month to charge.
+func parseTrialEnd(input SubscriptionInput, start time.Time, end *time.Time) (*time.Time, error) {
+	hasDate := input.TrialEndDate != nil && strings.TrimSpace(*input.TrialEndDate) != ""
+	if hasDate && input.TrialMonths != nil {
+		return nil, fmt.Errorf("%w: set either trial_end_date or trial_months", domain.ErrInvalidArgument)
+	}
+
+	var trialEnd time.Time
+	switch {
+	case hasDate:
+		t, err := ParseMonthDate(*input.TrialEndDate)
+		if err != nil {
+			return nil, fmt.Errorf("%w: trial_end_date: %s", domain.ErrInvalidArgument, err.Error())
+		}
+		if t.Before(start) {
+			return nil, fmt.Errorf("%w: trial_end_date must not be before start_date", domain.ErrInvalidArgument)
+		}
+		trialEnd = t
+	case input.TrialMonths != nil:
+		n := *input.TrialMonths
+		if n < 0 || n > maxTrialMonths {
+			return nil, fmt.Errorf("%w: trial_months must be between 0 and %d", domain.ErrInvalidArgument, maxTrialMonths)
+		}
+		if n == 0 {
+			return nil, nil
+		}
+		trialEnd = start.AddDate(0, n-1, 0)
+	default:
+		return nil, nil
+	}
+
+	if end != nil && !trialEnd.Before(*end) {
+		return nil, fmt.Errorf("%w: trial must end before end_date", domain.ErrInvalidArgument)
+	}
+	return &trialEnd, nil
+}
+
+const (
+	maxTags      = 20
+	maxTagLength = 50
+)
+
+// normalizeTags trims and lowercases tags and drops empty and repeated ones.
+func normalizeTags(tags []string) ([]string, error) {
+	res := make([]string, 0, len(tags))
+	for _, t := range tags {
+		t = strings.ToLower(strings.TrimSpace(t))
+		if t == "" || slices.Contains(res, t) {
+			continue
+		}
+		if utf8.RuneCountInString(t) > maxTagLength {
+			return nil, fmt.Errorf("%w: tags must be at most %d characters", domain.ErrInvalidArgument, maxTagLength)
+		}
+		res = append(res, t)
+	}
+	if len(res) > maxTags {
+		return nil, fmt.Errorf("%w: at most %d tags are allowed", domain.ErrInvalidArgument, maxTags)
+	}
+	return res, nil
+}
+
+const maxMembers = 20
+
+// parseMembers validates the split rule and the members of a shared
+// subscription and returns the members ordered by user ID. Percentages may
+// add up to at most 100, the owner carrying the rest.
+func parseMembers(input SubscriptionInput, owner uuid.UUID) (string, []domain.SubscriptionMember, error) {
+	rule := strings.ToLower(strings.TrimSpace(input.SplitRule))
+	if len(input.Members) == 0 {
+		if rule != "" {
+			return "", nil, fmt.Errorf("%w: split_rule requires members", domain.ErrInvalidArgument)
+		}
+		return "", nil, nil
+	}
+	if !slices.Contains([]string{domain.SplitEqual, domain.SplitPercentage, domain.SplitFixed}, rule) {
+		return "", nil, fmt.Errorf("%w: split_rule must be one of %s, %s, %s",
+			domain.ErrInvalidArgument, domain.SplitEqual, domain.SplitPercentage, domain.SplitFixed)
+	}
+	if len(input.Members) > maxMembers {
+		return "", nil, fmt.Errorf("%w: at most %d members are allowed", domain.ErrInvalidArgument, maxMembers)
+	}
+
+	members := make([]domain.SubscriptionMember, 0, len(input.Members))
+	percent := 0
+	for _, m := range input.Members {
+		uid, err := uuid.Parse(strings.TrimSpace(m.UserID))
+		if err != nil {
+			return "", nil, fmt.Errorf("%w: invalid member user_id", domain.ErrInvalidArgument)
+		}
+		if uid == owner {
+			return "", nil, fmt.Errorf("%w: the owner cannot be a member", domain.ErrInvalidArgument)
+		}
+		if slices.ContainsFunc(members, func(o domain.SubscriptionMember) bool { return o.UserID == uid }) {
+			return "", nil, fmt.Errorf("%w: member %s is listed twice", domain.ErrInvalidArgument, uid)
+		}
+
+		switch rule {
+		case domain.SplitEqual:
+			if m.Share != 0 {
+				return "", nil, fmt.Errorf("%w: share is not used with the equal split", domain.ErrInvalidArgument)
+			}
+		case domain.SplitPercentage:
+			if m.Share <= 0 || m.Share > 100 {
+				return "", nil, fmt.Errorf("%w: share must be a percentage between 1 and 100", domain.ErrInvalidArgument)
+			}
+			percent += m.Share
+		case domain.SplitFixed:
+			if m.Share <= 0 {
+				return "", nil, fmt.Errorf("%w: share must be positive integer", domain.ErrInvalidArgument)
+			}
+		}
+		members = append(members, domain.SubscriptionMember{UserID: uid, Share: m.Share})
+	}
+	if percent > 100 {
+		return "", nil, fmt.Errorf("%w: member shares must add up to at most 100 percent", domain.ErrInvalidArgument)
+	}
+
+	slices.SortFunc(members, func(a, b domain.SubscriptionMember) int {
+		return bytes.Compare(a.UserID[:], b.UserID[:])
+	})
+	return rule, members, nil
+}
+
+// maxTaxRate is 100% in basis points.
+const maxTaxRate = 10000
+
+func validateTaxRate(rate *int) error {
+	if rate != nil && (*rate < 0 || *rate > maxTaxRate) {
+		return fmt.Errorf("%w: tax_rate must be between 0 and %d basis points", domain.ErrInvalidArgument, maxTaxRate)
+	}
+	return nil
+}
@@ -0,0 +1,364 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/always-tired/crud-subscriptions/internal/domain"
+)
+
+const (
+	webhookBaseBackoff = 30 * time.Second
+	webhookMaxBackoff  = 6 * time.Hour
+	webhookLease       = 5 * time.Minute
+)
+
+type WebhookRepository interface {
+	CreateWebhook(ctx context.Context, w domain.Webhook) (domain.Webhook, error)
+	GetWebhook(ctx context.Context, id uuid.UUID) (domain.Webhook, error)
+	ListWebhooks(ctx context.Context) ([]domain.Webhook, error)
+	UpdateWebhook(ctx context.Context, w domain.Webhook) (domain.Webhook, error)
+	DeleteWebhook(ctx context.Context, id uuid.UUID) error
+	// ListWebhooksForEvent returns active webhooks that accept the event type.
+	ListWebhooksForEvent(ctx context.Context, eventType string) ([]domain.Webhook, error)
+
+	// CreateDeliveries skips deliveries of an event already queued for the
+	// same webhook.
+	CreateDeliveries(ctx context.Context, deliveries []domain.WebhookDelivery) error
+	// FetchDueDeliveries locks deliveries due at now until the surrounding
+	// transaction ends.
+	FetchDueDeliveries(ctx context.Context, now time.Time, limit int) ([]DueDelivery, error)
+	// LeaseDeliveries postpones the next attempt of the deliveries to until.
+	LeaseDeliveries(ctx context.Context, ids []uuid.UUID, until time.Time) error
+	SaveAttempt(ctx context.Context, d domain.WebhookDelivery, a domain.WebhookAttempt) error
+	ListDeliveries(ctx context.Context, filter DeliveryFilter) ([]domain.WebhookDelivery, error)
+	GetDelivery(ctx context.Context, webhookID, id uuid.UUID) (domain.WebhookDelivery, error)
+	ListAttempts(ctx context.Context, deliveryID uuid.UUID) ([]domain.WebhookAttempt, error)
+	// ResetDelivery queues the delivery again with a fresh attempt budget.
+	ResetDelivery(ctx context.Context, webhookID, id uuid.UUID, at time.Time) (domain.WebhookDelivery, error)
+}
+
+// DueDelivery is a delivery ready to be attempted with everything needed to
+// send it.
+type DueDelivery struct {
+	Delivery domain.WebhookDelivery
+	Webhook  domain.Webhook
+	Event    domain.Event
+}
+
+// WebhookSender performs one signed HTTP delivery. It returns the response
+// status code (0 when none was received) and an error for anything but 2xx.
+type WebhookSender interface {
+	Send(ctx context.Context, hook domain.Webhook, e domain.Event) (int, error)
+}
+
+var errWebhooksDisabled = errors.New("webhooks are not configured")
+
+func (s *Service) CreateWebhook(ctx context.Context, input WebhookInput) (domain.Webhook, error) {
+	if s.webhooks == nil {
+		return domain.Webhook{}, errWebhooksDisabled
+	}
+	hook, err := validateWebhookInput(input)
+	if err != nil {
+		return domain.Webhook{}, err
+	}
+	hook.ID = uuid.New()
+	if hook.Secret == "" {
+		if hook.Secret, err = randomToken(); err != nil {
+			return domain.Webhook{}, fmt.Errorf("generate webhook secret: %w", err)
+		}
+	}
+
+	created, err := s.webhooks.CreateWebhook(ctx, hook)
+	if err != nil {
+		s.log.Error("create webhook", "error", err)
+		return domain.Webhook{}, err
+	}
+	return created, nil
+}
+
+func (s *Service) GetWebhook(ctx context.Context, id uuid.UUID) (domain.Webhook, error) {
+	if s.webhooks == nil {
+		return domain.Webhook{}, errWebhooksDisabled
+	}
+	hook, err := s.webhooks.GetWebhook(ctx, id)
+	if err != nil {
+		s.log.Error("get webhook", "error", err)
+		return domain.Webhook{}, err
+	}
+	return hook, nil
+}
+
+func (s *Service) ListWebhooks(ctx context.Context) ([]domain.Webhook, error) {
+	if s.webhooks == nil {
+		return nil, errWebhooksDisabled
+	}
+	list, err := s.webhooks.ListWebhooks(ctx)
+	if err != nil {
+		s.log.Error("list webhooks", "error", err)
+		return nil, err
+	}
+	return list, nil
+}
+
+// UpdateWebhook replaces URL, event filter and active flag. The secret is
+// only rotated when a new one is given.
+func (s *Service) UpdateWebhook(ctx context.Context, id uuid.UUID, input WebhookInput) (domain.Webhook, error) {
+	if s.webhooks == nil {
+		return domain.Webhook{}, errWebhooksDisabled
+	}
+	hook, err := validateWebhookInput(input)
+	if err != nil {
+		return domain.Webhook{}, err
+	}
+	hook.ID = id
+
+	var updated domain.Webhook
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		current, err := s.webhooks.GetWebhook(ctx, id)
+		if err != nil {
+			return err
+		}
+		if hook.Secret == "" {
+			hook.Secret = current.Secret
+		}
+		updated, err = s.webhooks.UpdateWebhook(ctx, hook)
+		return err
+	})
+	if err != nil {
+		s.log.Error("update webhook", "error", err)
+		return domain.Webhook{}, err
+	}
+	return updated, nil
+}
+
+func (s *Service) DeleteWebhook(ctx context.Context, id uuid.UUID) error {
+	if s.webhooks == nil {
+		return errWebhooksDisabled
+	}
+	if err := s.webhooks.DeleteWebhook(ctx, id); err != nil {
+		s.log.Error("delete webhook", "error", err)
+		return err
+	}
+	return nil
+}
+
+func (s *Service) ListDeliveries(ctx context.Context, filter DeliveryFilter) ([]domain.WebhookDelivery, error) {
+	if s.webhooks == nil {
+		return nil, errWebhooksDisabled
+	}
+	if filter.Status != nil && !slices.Contains(deliveryStatuses, *filter.Status) {
+		return nil, fmt.Errorf("%w: unknown status %q", domain.ErrInvalidArgument, *filter.Status)
+	}
+	if _, err := s.webhooks.GetWebhook(ctx, filter.WebhookID); err != nil {
+		return nil, err
+	}
+
+	list, err := s.webhooks.ListDeliveries(ctx, filter)
+	if err != nil {
+		s.log.Error("list webhook deliveries", "error", err)
+		return nil, err
+	}
+	return list, nil
+}
+
+// GetDelivery returns a delivery with its attempts, oldest first.
+func (s *Service) GetDelivery(ctx context.Context, webhookID, id uuid.UUID) (domain.WebhookDelivery, []domain.WebhookAttempt, error) {
+	if s.webhooks == nil {
+		return domain.WebhookDelivery{}, nil, errWebhooksDisabled
+	}
+	d, err := s.webhooks.GetDelivery(ctx, webhookID, id)
+	if err != nil {
+		s.log.Error("get webhook delivery", "error", err)
+		return domain.WebhookDelivery{}, nil, err
+	}
+	attempts, err := s.webhooks.ListAttempts(ctx, id)
+	if err != nil {
+		s.log.Error("list webhook attempts", "error", err)
+		return domain.WebhookDelivery{}, nil, err
+	}
+	return d, attempts, nil
+}
+
+// Redeliver queues a delivery again, whatever its state, e.g. to replay a
+// dead-lettered event once the receiver is fixed.
+func (s *Service) Redeliver(ctx context.Context, webhookID, id uuid.UUID) (domain.WebhookDelivery, error) {
+	if s.webhooks == nil {
+		return domain.WebhookDelivery{}, errWebhooksDisabled
+	}
+	d, err := s.webhooks.ResetDelivery(ctx, webhookID, id, time.Now())
+	if err != nil {
+		s.log.Error("redeliver webhook", "error", err)
+		return domain.WebhookDelivery{}, err
+	}
+	return d, nil
+}
+
+// WebhookPublisher returns an EventPublisher for the outbox relay that queues
+// a delivery for every webhook interested in the event. The relay publishes
+// outside its transaction, so an event whose lease expired may be published
+// again; the unique index on webhook and event
+// (idx_webhook_deliveries_webhook_event_unique) drops the duplicate
+// deliveries.
+func (s *Service) WebhookPublisher() EventPublisher {
+	return webhookFanout{s: s}
+}
+
+type webhookFanout struct {
+	s *Service
+}
+
+func (f webhookFanout) Publish(ctx context.Context, e domain.Event) error {
+	if f.s.webhooks == nil {
+		return nil
+	}
+	hooks, err := f.s.webhooks.ListWebhooksForEvent(ctx, e.Type)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	deliveries := make([]domain.WebhookDelivery, 0, len(hooks))
+	for _, h := range hooks {
+		deliveries = append(deliveries, domain.WebhookDelivery{
+			ID:            uuid.New(),
+			WebhookID:     h.ID,
+			EventID:       e.ID,
+			EventType:     e.Type,
+			Status:        domain.DeliveryPending,
+			NextAttemptAt: now,
+		})
+	}
+	return f.s.webhooks.CreateDeliveries(ctx, deliveries)
+}
+
+// DeliverWebhooks attempts up to limit due deliveries and returns how many
+// were attempted. Failed deliveries are retried with exponential backoff and
+// dead-lettered after the configured number of attempts.
+//
+// Deliveries are claimed in a short transaction by pushing their next attempt
+// past webhookLease, so HTTP calls run outside any transaction and a crashed
+// worker's deliveries are picked up again once the lease expires.
+func (s *Service) DeliverWebhooks(ctx context.Context, limit int) (int, error) {
+	if s.webhooks == nil || s.webhookSender == nil {
+		return 0, nil
+	}
+
+	var due []DueDelivery
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		if due, err = s.webhooks.FetchDueDeliveries(ctx, time.Now(), limit); err != nil {
+			return err
+		}
+		ids := make([]uuid.UUID, 0, len(due))
+		for _, dd := range due {
+			ids = append(ids, dd.Delivery.ID)
+		}
+		return s.webhooks.LeaseDeliveries(ctx, ids, time.Now().Add(webhookLease))
+	})
+	if err != nil {
+		s.log.Error("claim webhook deliveries", "error", err)
+		return 0, err
+	}
+
+	for i, dd := range due {
+		if err := s.attemptDelivery(ctx, dd); err != nil {
+			s.log.Error("save webhook attempt", "error", err)
+			return i, err
+		}
+	}
+	return len(due), nil
+}
+
+func (s *Service) attemptDelivery(ctx context.Context, dd DueDelivery) error {
+	start := time.Now()
+	code, sendErr := s.webhookSender.Send(ctx, dd.Webhook, dd.Event)
+
+	attempt := domain.WebhookAttempt{
+		ID:          uuid.New(),
+		DeliveryID:  dd.Delivery.ID,
+		AttemptedAt: start,
+		Duration:    time.Since(start),
+	}
+	if code != 0 {
+		attempt.StatusCode = &code
+	}
+
+	d := dd.Delivery
+	d.Attempts++
+	d.LastStatusCode = attempt.StatusCode
+	switch {
+	case sendErr == nil:
+		d.Status = domain.DeliverySucceeded
+		d.LastError = nil
+	case d.Attempts >= s.webhookMaxAttempts:
+		msg := sendErr.Error()
+		attempt.Error, d.LastError = &msg, &msg
+		d.Status = domain.DeliveryDead
+		s.log.Warn("webhook delivery dead-lettered", "delivery_id", d.ID, "webhook_id", d.WebhookID, "error", sendErr)
+	default:
+		msg := sendErr.Error()
+		attempt.Error, d.LastError = &msg, &msg
+		d.Status = domain.DeliveryRetrying
+		d.NextAttemptAt = start.Add(webhookBackoff(d.Attempts))
+	}
+	return s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		return s.webhooks.SaveAttempt(ctx, d, attempt)
+	})
+}
+
+// webhookBackoff doubles the wait after every failed attempt, starting at 30s
+// and capped at 6h.
+func webhookBackoff(attempts int) time.Duration {
+	d := webhookBaseBackoff
+	for i := 1; i < attempts; i++ {
+		d *= 2
+		if d >= webhookMaxBackoff {
+			return webhookMaxBackoff
+		}
+	}
+	return d
+}
+
+var deliveryStatuses = []string{
+	domain.DeliveryPending,
+	domain.DeliveryRetrying,
+	domain.DeliverySucceeded,
+	domain.DeliveryDead,
+}
+
+func validateWebhookInput(input WebhookInput) (domain.Webhook, error) {
+	u, err := url.Parse(strings.TrimSpace(input.URL))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return domain.Webhook{}, fmt.Errorf("%w: url must be an absolute http(s) URL", domain.ErrInvalidArgument)
+	}
+
+	events := make([]string, 0, len(input.Events))
+	for _, e := range input.Events {
+		if !slices.Contains(domain.EventTypes, e) {
+			return domain.Webhook{}, fmt.Errorf("%w: unknown event type %q", domain.ErrInvalidArgument, e)
+		}
+		if !slices.Contains(events, e) {
+			events = append(events, e)
+		}
+	}
+
+	active := true
+	if input.Active != nil {
+		active = *input.Active
+	}
+
+	return domain.Webhook{
+		URL:    u.String(),
+		Secret: input.Secret,
+		Events: events,
+		Active: active,
+	}, nil
+}
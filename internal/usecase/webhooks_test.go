@@ -0,0 +1,169 @@
+package usecase_test
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/always-tired/crud-subscriptions/internal/domain"
+	"github.com/always-tired/crud-subscriptions/internal/publisher"
+	"github.com/always-tired/crud-subscriptions/internal/usecase"
+)
+
+// fakeWebhookRepo keeps deliveries in memory. Methods the dispatcher does not
+// use panic through the nil embedded interface.
+type fakeWebhookRepo struct {
+	usecase.WebhookRepository
+
+	mu       sync.Mutex
+	hook     domain.Webhook
+	event    domain.Event
+	delivery domain.WebhookDelivery
+	attempts []domain.WebhookAttempt
+}
+
+func (r *fakeWebhookRepo) FetchDueDeliveries(_ context.Context, now time.Time, _ int) ([]usecase.DueDelivery, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	d := r.delivery
+	if d.Status == domain.DeliverySucceeded || d.Status == domain.DeliveryDead || d.NextAttemptAt.After(now) {
+		return nil, nil
+	}
+	return []usecase.DueDelivery{{Delivery: d, Webhook: r.hook, Event: r.event}}, nil
+}
+
+func (r *fakeWebhookRepo) LeaseDeliveries(_ context.Context, ids []uuid.UUID, until time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, id := range ids {
+		if id == r.delivery.ID {
+			r.delivery.NextAttemptAt = until
+		}
+	}
+	return nil
+}
+
+func (r *fakeWebhookRepo) SaveAttempt(_ context.Context, d domain.WebhookDelivery, a domain.WebhookAttempt) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.delivery = d
+	r.attempts = append(r.attempts, a)
+	return nil
+}
+
+// makeDue pretends the backoff has passed.
+func (r *fakeWebhookRepo) makeDue() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.delivery.NextAttemptAt = time.Now().Add(-time.Second)
+}
+
+func newWebhookFixture(t *testing.T, statuses ...int) (*usecase.Service, *fakeWebhookRepo, *int) {
+	t.Helper()
+
+	repo := &fakeWebhookRepo{
+		hook:  domain.Webhook{ID: uuid.New(), Secret: "s3cret", Active: true},
+		event: domain.Event{ID: uuid.New(), Type: domain.EventSubscriptionCreated, Payload: []byte(`{}`)},
+	}
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if err := publisher.VerifySignature("s3cret", r.Header.Get(publisher.SignatureHeader), body, time.Minute, time.Now()); err != nil {
+			t.Errorf("signature: %v", err)
+		}
+		if got := r.Header.Get("X-Event-ID"); got != repo.event.ID.String() {
+			t.Errorf("X-Event-ID = %q, want %q", got, repo.event.ID)
+		}
+		if got := r.Header.Get("X-Event-Type"); got != repo.event.Type {
+			t.Errorf("X-Event-Type = %q, want %q", got, repo.event.Type)
+		}
+		status := statuses[min(calls, len(statuses)-1)]
+		calls++
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(srv.Close)
+
+	repo.hook.URL = srv.URL
+	repo.delivery = domain.WebhookDelivery{
+		ID:            uuid.New(),
+		WebhookID:     repo.hook.ID,
+		EventID:       repo.event.ID,
+		EventType:     repo.event.Type,
+		Status:        domain.DeliveryPending,
+		NextAttemptAt: time.Now().Add(-time.Second),
+	}
+
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	s := usecase.NewService(nil, log,
+		usecase.WithWebhooks(repo, publisher.NewWebhookSender(srv.Client()), 3),
+	)
+	return s, repo, &calls
+}
+
+func TestDeliverWebhooksRetriesWithBackoff(t *testing.T) {
+	s, repo, calls := newWebhookFixture(t, http.StatusServiceUnavailable, http.StatusInternalServerError, http.StatusNoContent)
+	ctx := context.Background()
+
+	wantBackoff := []time.Duration{30 * time.Second, 60 * time.Second}
+	for i, backoff := range wantBackoff {
+		start := time.Now()
+		n, err := s.DeliverWebhooks(ctx, 10)
+		if err != nil || n != 1 {
+			t.Fatalf("attempt %d: DeliverWebhooks = %d, %v", i+1, n, err)
+		}
+		d := repo.delivery
+		if d.Status != domain.DeliveryRetrying || d.Attempts != i+1 {
+			t.Fatalf("attempt %d: status %s after %d attempts", i+1, d.Status, d.Attempts)
+		}
+		if d.LastStatusCode == nil || *d.LastStatusCode < 500 {
+			t.Fatalf("attempt %d: last status code %v", i+1, d.LastStatusCode)
+		}
+		if wait := d.NextAttemptAt.Sub(start); wait < backoff || wait > backoff+5*time.Second {
+			t.Fatalf("attempt %d: next attempt in %s, want %s", i+1, wait, backoff)
+		}
+
+		if n, _ := s.DeliverWebhooks(ctx, 10); n != 0 {
+			t.Fatalf("attempt %d: delivery retried before its backoff", i+1)
+		}
+		repo.makeDue()
+	}
+
+	if n, err := s.DeliverWebhooks(ctx, 10); err != nil || n != 1 {
+		t.Fatalf("last attempt: DeliverWebhooks = %d, %v", n, err)
+	}
+	d := repo.delivery
+	if d.Status != domain.DeliverySucceeded || d.Attempts != 3 || d.LastError != nil {
+		t.Fatalf("delivery = %+v, want succeeded after 3 attempts", d)
+	}
+	if *calls != 3 || len(repo.attempts) != 3 {
+		t.Fatalf("%d calls and %d recorded attempts, want 3", *calls, len(repo.attempts))
+	}
+	if code := repo.attempts[2].StatusCode; code == nil || *code != http.StatusNoContent {
+		t.Fatalf("last attempt status code %v, want 204", code)
+	}
+}
+
+func TestDeliverWebhooksDeadLetters(t *testing.T) {
+	s, repo, calls := newWebhookFixture(t, http.StatusBadGateway)
+	ctx := context.Background()
+
+	for range 5 {
+		if _, err := s.DeliverWebhooks(ctx, 10); err != nil {
+			t.Fatal(err)
+		}
+		repo.makeDue()
+	}
+	if d := repo.delivery; d.Status != domain.DeliveryDead || d.Attempts != 3 || d.LastError == nil {
+		t.Fatalf("delivery = %+v, want dead after 3 attempts", d)
+	}
+	if *calls != 3 {
+		t.Fatalf("%d calls, want 3", *calls)
+	}
+}
@@ -0,0 +1,34 @@
+package worker
+
+import (
+	"context"
+	"time"
+
+	"github.com/always-tired/crud-subscriptions/internal/usecase"
+)
+
+// EndedScanner emits SubscriptionEnded events once a subscription's last
+// billed month is over. Months are coarse, so an hourly scan is plenty.
+type EndedScanner struct {
+	service  *usecase.Service
+	interval time.Duration
+}
+
+func NewEndedScanner(service *usecase.Service, interval time.Duration) *EndedScanner {
+	return &EndedScanner{service: service, interval: interval}
+}
+
+func (s *EndedScanner) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		_ = s.service.EmitEndedEvents(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
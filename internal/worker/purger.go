@@ -0,0 +1,41 @@
+package worker
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/always-tired/crud-subscriptions/internal/usecase"
+)
+
+// Purger periodically removes soft-deleted subscriptions whose retention
+// period has passed.
+type Purger struct {
+	service   *usecase.Service
+	interval  time.Duration
+	retention time.Duration
+	log       *slog.Logger
+}
+
+func NewPurger(service *usecase.Service, interval, retention time.Duration, log *slog.Logger) *Purger {
+	return &Purger{service: service, interval: interval, retention: retention, log: log}
+}
+
+// Run purges once immediately and then on every tick until ctx is done.
+func (p *Purger) Run(ctx context.Context) {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		n, err := p.service.PurgeDeleted(ctx, p.retention)
+		if err == nil && n > 0 {
+			p.log.Info("purged deleted subscriptions", "count", n)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
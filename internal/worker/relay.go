@@ -0,0 +1,48 @@
+package worker
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/always-tired/crud-subscriptions/internal/usecase"
+)
+
+const relayBatchSize = 100
+
+// Relay moves events from the outbox to a publisher.
+type Relay struct {
+	service   *usecase.Service
+	publisher usecase.EventPublisher
+	interval  time.Duration
+	log       *slog.Logger
+}
+
+func NewRelay(service *usecase.Service, publisher usecase.EventPublisher, interval time.Duration, log *slog.Logger) *Relay {
+	return &Relay{service: service, publisher: publisher, interval: interval, log: log}
+}
+
+// Run drains the outbox on every tick until ctx is done. Full batches are
+// followed immediately by the next one.
+func (r *Relay) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		for {
+			n, err := r.service.RelayEvents(ctx, r.publisher, relayBatchSize)
+			if n > 0 {
+				r.log.Debug("relayed events", "count", n)
+			}
+			if err != nil || n < relayBatchSize || ctx.Err() != nil {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package worker
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/always-tired/crud-subscriptions/internal/usecase"
+)
+
+// ReminderScheduler sends reminders about charges and endings due within a
+// window, and spend anomaly reminders when enabled. Reminders are
+// deduplicated, so ticking often only costs a query.
+type ReminderScheduler struct {
+	service  *usecase.Service
+	interval time.Duration
+	within   time.Duration
+	log      *slog.Logger
+}
+
+func NewReminderScheduler(service *usecase.Service, interval, within time.Duration, log *slog.Logger) *ReminderScheduler {
+	return &ReminderScheduler{service: service, interval: interval, within: within, log: log}
+}
+
+func (s *ReminderScheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		if n, _ := s.service.SendReminders(ctx, s.within); n > 0 {
+			s.log.Info("sent reminders", "count", n)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package worker
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/always-tired/crud-subscriptions/internal/usecase"
+)
+
+const webhookBatchSize = 20
+
+// WebhookDispatcher attempts due webhook deliveries.
+type WebhookDispatcher struct {
+	service  *usecase.Service
+	interval time.Duration
+	log      *slog.Logger
+}
+
+func NewWebhookDispatcher(service *usecase.Service, interval time.Duration, log *slog.Logger) *WebhookDispatcher {
+	return &WebhookDispatcher{service: service, interval: interval, log: log}
+}
+
+func (d *WebhookDispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+
+	for {
+		for {
+			n, err := d.service.DeliverWebhooks(ctx, webhookBatchSize)
+			if n > 0 {
+				d.log.Debug("attempted webhook deliveries", "count", n)
+			}
+			if err != nil || n < webhookBatchSize || ctx.Err() != nil {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,17 @@
+-- +goose Up
+ALTER TABLE subscriptions ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ NULL;
+
+DROP INDEX IF EXISTS idx_subscriptions_user_service_start_unique;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_subscriptions_user_service_start_unique
+ON subscriptions (user_id, service_name, start_date)
+WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_subscriptions_deleted_at ON subscriptions (deleted_at)
+WHERE deleted_at IS NOT NULL;
+
+-- +goose Down
+DELETE FROM subscriptions WHERE deleted_at IS NOT NULL;
+DROP INDEX IF EXISTS idx_subscriptions_deleted_at;
+DROP INDEX IF EXISTS idx_subscriptions_user_service_start_unique;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_subscriptions_user_service_start_unique
+ON subscriptions (user_id, service_name, start_date);
+ALTER TABLE subscriptions DROP COLUMN IF EXISTS deleted_at;
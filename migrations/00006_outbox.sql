@@ -0,0 +1,20 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS outbox (
+    id UUID PRIMARY KEY,
+    event_type TEXT NOT NULL,
+    subscription_id UUID NOT NULL,
+    user_id UUID NOT NULL,
+    payload JSONB NOT NULL,
+    dedupe_key TEXT NULL,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    published_at TIMESTAMPTZ NULL
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_outbox_dedupe_key_unique ON outbox (dedupe_key);
+CREATE INDEX IF NOT EXISTS idx_outbox_unpublished ON outbox (created_at)
+WHERE published_at IS NULL;
+
+-- +goose Down
+DROP TABLE IF EXISTS outbox;
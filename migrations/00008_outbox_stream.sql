@@ -0,0 +1,25 @@
+-- +goose Up
+ALTER TABLE outbox ADD COLUMN IF NOT EXISTS seq BIGSERIAL;
+ALTER TABLE outbox ADD COLUMN IF NOT EXISTS xid XID8 NOT NULL DEFAULT pg_current_xact_id();
+
+CREATE INDEX IF NOT EXISTS idx_outbox_stream ON outbox (xid, seq);
+
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION notify_subscription_event() RETURNS trigger AS $$
+BEGIN
+    PERFORM pg_notify('subscription_events', NEW.user_id::text);
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+CREATE TRIGGER outbox_notify
+AFTER INSERT ON outbox
+FOR EACH ROW EXECUTE FUNCTION notify_subscription_event();
+
+-- +goose Down
+DROP TRIGGER IF EXISTS outbox_notify ON outbox;
+DROP FUNCTION IF EXISTS notify_subscription_event();
+DROP INDEX IF EXISTS idx_outbox_stream;
+ALTER TABLE outbox DROP COLUMN IF EXISTS xid;
+ALTER TABLE outbox DROP COLUMN IF EXISTS seq;
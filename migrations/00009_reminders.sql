@@ -0,0 +1,17 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS reminders (
+    id UUID PRIMARY KEY,
+    kind TEXT NOT NULL,
+    subscription_id UUID NOT NULL REFERENCES subscriptions (id) ON DELETE CASCADE,
+    user_id UUID NOT NULL,
+    due_date DATE NOT NULL,
+    channel TEXT NOT NULL,
+    claimed_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    sent_at TIMESTAMPTZ NULL
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_reminders_unique
+ON reminders (subscription_id, kind, due_date, channel);
+
+-- +goose Down
+DROP TABLE IF EXISTS reminders;
@@ -0,0 +1,37 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS categories (
+    id UUID PRIMARY KEY,
+    name TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_categories_name_unique ON categories (lower(name));
+
+ALTER TABLE subscriptions
+    ADD COLUMN IF NOT EXISTS category_id UUID NULL REFERENCES categories (id) ON DELETE SET NULL,
+    ADD COLUMN IF NOT EXISTS tags TEXT[] NOT NULL DEFAULT '{}';
+
+CREATE INDEX IF NOT EXISTS idx_subscriptions_category_id ON subscriptions (category_id);
+CREATE INDEX IF NOT EXISTS idx_subscriptions_tags ON subscriptions USING GIN (tags);
+
+ALTER TABLE budgets
+    ADD COLUMN IF NOT EXISTS category_id UUID NULL REFERENCES categories (id) ON DELETE CASCADE;
+
+DROP INDEX IF EXISTS idx_budgets_user_unique;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_budgets_user_category_unique
+ON budgets (user_id, category_id) NULLS NOT DISTINCT;
+
+-- +goose Down
+DELETE FROM budgets WHERE category_id IS NOT NULL;
+DROP INDEX IF EXISTS idx_budgets_user_category_unique;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_budgets_user_unique ON budgets (user_id);
+ALTER TABLE budgets DROP COLUMN IF EXISTS category_id;
+
+DROP INDEX IF EXISTS idx_subscriptions_tags;
+DROP INDEX IF EXISTS idx_subscriptions_category_id;
+ALTER TABLE subscriptions
+    DROP COLUMN IF EXISTS tags,
+    DROP COLUMN IF EXISTS category_id;
+
+DROP TABLE IF EXISTS categories;
@@ -0,0 +1,16 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS services (
+    id UUID PRIMARY KEY,
+    name TEXT NOT NULL,
+    aliases TEXT[] NOT NULL DEFAULT '{}',
+    default_price INTEGER NULL CHECK (default_price > 0),
+    vendor_url TEXT NOT NULL DEFAULT '',
+    category_id UUID NULL REFERENCES categories (id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_services_name_unique ON services (lower(name));
+
+-- +goose Down
+DROP TABLE IF EXISTS services;
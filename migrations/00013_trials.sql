@@ -0,0 +1,14 @@
+-- +goose Up
+ALTER TABLE subscriptions
+    ADD COLUMN IF NOT EXISTS trial_end_date DATE NULL;
+
+ALTER TABLE subscriptions
+    ADD CONSTRAINT subscriptions_trial_end_check CHECK (trial_end_date >= start_date);
+
+CREATE INDEX IF NOT EXISTS idx_subscriptions_trial_end_date ON subscriptions (trial_end_date)
+    WHERE trial_end_date IS NOT NULL;
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_subscriptions_trial_end_date;
+ALTER TABLE subscriptions DROP CONSTRAINT IF EXISTS subscriptions_trial_end_check;
+ALTER TABLE subscriptions DROP COLUMN IF EXISTS trial_end_date;
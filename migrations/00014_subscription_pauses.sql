@@ -0,0 +1,17 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS subscription_pauses (
+    subscription_id UUID NOT NULL REFERENCES subscriptions (id) ON DELETE CASCADE,
+    paused_from DATE NOT NULL,
+    paused_until DATE NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (subscription_id, paused_from),
+    CHECK (paused_until >= paused_from)
+);
+
+-- At most one pause per subscription runs until resumed.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_subscription_pauses_open
+    ON subscription_pauses (subscription_id)
+    WHERE paused_until IS NULL;
+
+-- +goose Down
+DROP TABLE IF EXISTS subscription_pauses;
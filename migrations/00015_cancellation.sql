@@ -0,0 +1,9 @@
+-- +goose Up
+ALTER TABLE subscriptions
+    ADD COLUMN IF NOT EXISTS cancel_reason TEXT NULL,
+    ADD COLUMN IF NOT EXISTS cancelled_at TIMESTAMPTZ NULL;
+
+-- +goose Down
+ALTER TABLE subscriptions
+    DROP COLUMN IF EXISTS cancelled_at,
+    DROP COLUMN IF EXISTS cancel_reason;
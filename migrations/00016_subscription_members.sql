@@ -0,0 +1,22 @@
+-- +goose Up
+ALTER TABLE subscriptions
+    ADD COLUMN IF NOT EXISTS split_rule TEXT NULL
+        CHECK (split_rule IN ('equal', 'percentage', 'fixed'));
+
+-- Users sharing a subscription with its owner. share is a percentage or a
+-- monthly amount depending on the split rule of the subscription.
+CREATE TABLE IF NOT EXISTS subscription_members (
+    subscription_id UUID NOT NULL REFERENCES subscriptions (id) ON DELETE CASCADE,
+    user_id UUID NOT NULL,
+    share INTEGER NOT NULL DEFAULT 0 CHECK (share >= 0),
+    PRIMARY KEY (subscription_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_subscription_members_user_id
+    ON subscription_members (user_id);
+
+-- +goose Down
+DROP TABLE IF EXISTS subscription_members;
+
+ALTER TABLE subscriptions
+    DROP COLUMN IF EXISTS split_rule;
@@ -0,0 +1,33 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS users (
+    id UUID PRIMARY KEY,
+    name TEXT NOT NULL DEFAULT '',
+    email TEXT NULL,
+    currency TEXT NOT NULL DEFAULT 'RUB',
+    timezone TEXT NOT NULL DEFAULT 'UTC',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_email_unique ON users (lower(email));
+
+-- Register every user already referenced before adding the foreign keys.
+INSERT INTO users (id)
+SELECT user_id FROM subscriptions
+UNION
+SELECT user_id FROM subscription_members
+ON CONFLICT (id) DO NOTHING;
+
+ALTER TABLE subscriptions
+    ADD CONSTRAINT subscriptions_user_id_fkey
+        FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE RESTRICT;
+
+ALTER TABLE subscription_members
+    ADD CONSTRAINT subscription_members_user_id_fkey
+        FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE;
+
+-- +goose Down
+ALTER TABLE subscription_members DROP CONSTRAINT IF EXISTS subscription_members_user_id_fkey;
+ALTER TABLE subscriptions DROP CONSTRAINT IF EXISTS subscriptions_user_id_fkey;
+
+DROP TABLE IF EXISTS users;
@@ -0,0 +1,18 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS subscription_discounts (
+    id UUID PRIMARY KEY,
+    subscription_id UUID NOT NULL REFERENCES subscriptions (id) ON DELETE CASCADE,
+    kind TEXT NOT NULL CHECK (kind IN ('percentage', 'fixed')),
+    value INTEGER NOT NULL CHECK (value > 0),
+    valid_from DATE NOT NULL,
+    valid_until DATE NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CHECK (kind <> 'percentage' OR value <= 100),
+    CHECK (valid_until >= valid_from)
+);
+
+CREATE INDEX IF NOT EXISTS idx_subscription_discounts_subscription
+    ON subscription_discounts (subscription_id, valid_from);
+
+-- +goose Down
+DROP TABLE IF EXISTS subscription_discounts;
@@ -0,0 +1,14 @@
+-- +goose Up
+ALTER TABLE categories
+    ADD COLUMN IF NOT EXISTS tax_rate INTEGER NULL CHECK (tax_rate BETWEEN 0 AND 10000);
+
+ALTER TABLE subscriptions
+    ADD COLUMN IF NOT EXISTS tax_rate INTEGER NULL CHECK (tax_rate BETWEEN 0 AND 10000),
+    ADD COLUMN IF NOT EXISTS tax_inclusive BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- +goose Down
+ALTER TABLE subscriptions
+    DROP COLUMN IF EXISTS tax_inclusive,
+    DROP COLUMN IF EXISTS tax_rate;
+
+ALTER TABLE categories DROP COLUMN IF EXISTS tax_rate;
@@ -0,0 +1,23 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS payment_methods (
+    id UUID PRIMARY KEY,
+    label TEXT NOT NULL,
+    type TEXT NOT NULL CHECK (type IN ('card', 'bank_account', 'wallet', 'other')),
+    last4 TEXT NULL CHECK (last4 ~ '^[0-9]{4}$'),
+    expiry DATE NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_payment_methods_expiry ON payment_methods (expiry) WHERE expiry IS NOT NULL;
+
+ALTER TABLE subscriptions
+    ADD COLUMN IF NOT EXISTS payment_method_id UUID NULL
+        CONSTRAINT subscriptions_payment_method_id_fkey REFERENCES payment_methods (id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_subscriptions_payment_method_id ON subscriptions (payment_method_id);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_subscriptions_payment_method_id;
+ALTER TABLE subscriptions DROP COLUMN IF EXISTS payment_method_id;
+DROP TABLE IF EXISTS payment_methods;
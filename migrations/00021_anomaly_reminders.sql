@@ -0,0 +1,10 @@
+-- +goose Up
+ALTER TABLE reminders ALTER COLUMN subscription_id DROP NOT NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_reminders_user_unique
+ON reminders (user_id, kind, due_date, channel) WHERE subscription_id IS NULL;
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_reminders_user_unique;
+DELETE FROM reminders WHERE subscription_id IS NULL;
+ALTER TABLE reminders ALTER COLUMN subscription_id SET NOT NULL;
@@ -0,0 +1,13 @@
+-- +goose Up
+ALTER TABLE services
+    ADD COLUMN IF NOT EXISTS yearly_price INTEGER NULL CHECK (yearly_price > 0),
+    ADD COLUMN IF NOT EXISTS family_price INTEGER NULL CHECK (family_price > 0);
+
+ALTER TABLE subscriptions ADD COLUMN IF NOT EXISTS unused BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- +goose Down
+ALTER TABLE subscriptions DROP COLUMN IF EXISTS unused;
+
+ALTER TABLE services
+    DROP COLUMN IF EXISTS family_price,
+    DROP COLUMN IF EXISTS yearly_price;
@@ -0,0 +1,17 @@
+-- +goose Up
+ALTER TABLE outbox
+    ADD COLUMN IF NOT EXISTS locked_until TIMESTAMPTZ NULL,
+    ADD COLUMN IF NOT EXISTS parked_at TIMESTAMPTZ NULL;
+
+DROP INDEX IF EXISTS idx_outbox_unpublished;
+CREATE INDEX IF NOT EXISTS idx_outbox_unpublished ON outbox (created_at)
+WHERE published_at IS NULL AND parked_at IS NULL;
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_outbox_unpublished;
+CREATE INDEX IF NOT EXISTS idx_outbox_unpublished ON outbox (created_at)
+WHERE published_at IS NULL;
+
+ALTER TABLE outbox
+    DROP COLUMN IF EXISTS parked_at,
+    DROP COLUMN IF EXISTS locked_until;
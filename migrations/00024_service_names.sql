@@ -0,0 +1,25 @@
+-- +goose Up
+CREATE EXTENSION IF NOT EXISTS fuzzystrmatch;
+
+-- Normalized names and aliases of catalog services, see normalizeServiceName.
+-- The primary key keeps a name from resolving to two services.
+CREATE TABLE IF NOT EXISTS service_names (
+    name TEXT PRIMARY KEY,
+    service_id UUID NOT NULL REFERENCES services (id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_service_names_service_id ON service_names (service_id);
+
+INSERT INTO service_names (name, service_id)
+SELECT DISTINCT ON (n.name) n.name, n.service_id
+FROM (
+    SELECT btrim(regexp_replace(lower(x.name), '[^[:alnum:]]+', ' ', 'g')) AS name, s.id AS service_id, s.created_at
+    FROM services s
+    CROSS JOIN LATERAL unnest(ARRAY[s.name] || s.aliases) AS x(name)
+) n
+WHERE n.name <> ''
+ORDER BY n.name, n.created_at
+ON CONFLICT (name) DO NOTHING;
+
+-- +goose Down
+DROP TABLE IF EXISTS service_names;